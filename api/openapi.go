@@ -0,0 +1,11 @@
+// Package api contiene los contratos de la API: la especificación OpenAPI de
+// la API REST (openapi.json) y el proto de la API gRPC (todo/v1).
+package api
+
+import _ "embed"
+
+// OpenAPI es la especificación OpenAPI 3 de las rutas registradas en
+// routes.SetupRouter. Un test de routes falla si alguna ruta no está descrita.
+//
+//go:embed openapi.json
+var OpenAPI []byte
@@ -0,0 +1,4 @@
+// Package todov1 contiene el código generado de la API gRPC de items (todo.proto)
+package todov1
+
+//go:generate protoc -I ../../.. --go_out=../../.. --go_opt=paths=source_relative --go-grpc_out=../../.. --go-grpc_opt=paths=source_relative api/todo/v1/todo.proto
@@ -3,26 +3,36 @@ module github.com/Milagrosgzmn/devops_todo_go.git
 go 1.23.2
 
 require (
+	github.com/getkin/kin-openapi v0.132.0 // validación de la especificación OpenAPI en los tests
+	github.com/gin-contrib/sse v0.1.0 // Server-Sent Events
 	github.com/gin-gonic/gin v1.7.2 // libreria de enrutamiento
 	github.com/go-sql-driver/mysql v1.9.3 // driver de MySQL
+	github.com/golang-jwt/jwt/v5 v5.3.0 // tokens de acceso JWT
+	github.com/gorilla/websocket v1.5.3 // WebSocket
+	github.com/graph-gophers/dataloader v5.0.0+incompatible // agrupación de cargas de la API GraphQL
+	github.com/graph-gophers/graphql-go v1.5.0 // API GraphQL
 	github.com/joho/godotenv v1.5.1 // carga de variables de entorno
 	github.com/pressly/goose/v3 v3.26.0 // migraciones de base de datos
+	github.com/swaggo/files/v2 v2.0.2 // archivos de Swagger UI
+	github.com/vektah/gqlparser/v2 v2.5.31 // análisis de consultas GraphQL (límites)
+	golang.org/x/crypto v0.40.0 // hash de contraseñas (bcrypt)
+	golang.org/x/text v0.27.0 // negociación del idioma de las respuestas
+	google.golang.org/grpc v1.73.0 // API gRPC
+	google.golang.org/protobuf v1.36.6 // mensajes de la API gRPC
 )
 
 require (
 	filippo.io/edwards25519 v1.1.0 // indirect
-	github.com/gin-contrib/sse v0.1.0 // indirect
 	github.com/go-playground/locales v0.13.0 // indirect
 	github.com/go-playground/universal-translator v0.17.0 // indirect
 	github.com/go-playground/validator/v10 v10.4.1 // indirect
-	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/json-iterator/go v1.1.9 // indirect
 	github.com/leodido/go-urn v1.2.0 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
 	github.com/modern-go/reflect2 v0.0.0-20180701023420-4b7aa43c6742 // indirect
-	github.com/ugorji/go/codec v1.1.7 // indirect
-	golang.org/x/crypto v0.40.0 // indirect
+	github.com/ugorji/go/codec v1.2.7 // indirect
 	golang.org/x/sys v0.34.0 // indirect
 	gopkg.in/yaml.v2 v2.2.8 // indirect
 )
@@ -31,11 +41,21 @@ require github.com/stretchr/testify v1.11.1
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-openapi/jsonpointer v0.21.0 // indirect
+	github.com/go-openapi/swag v0.23.0 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/mfridman/interpolate v0.0.2 // indirect
+	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
+	github.com/oasdiff/yaml v0.0.0-20250309154309-f31be36b4037 // indirect
+	github.com/oasdiff/yaml3 v0.0.0-20250309153720-d2182401db90 // indirect
+	github.com/opentracing/opentracing-go v1.2.0 // indirect
+	github.com/perimeterx/marshmallow v1.1.5 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/sethvargo/go-retry v0.3.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
+	golang.org/x/net v0.42.0 // indirect
 	golang.org/x/sync v0.16.0 // indirect
-	google.golang.org/protobuf v1.33.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250324211829-b45e905df463 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
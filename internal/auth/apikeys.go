@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// prefixLength es el largo del prefijo visible de una key (tdk_ y 8 caracteres),
+// que permite reconocerla en los listados sin guardarla en claro
+const prefixLength = len(constants.APIKeyPrefix) + 8
+
+// NewAPIKey genera una API key aleatoria y devuelve la key en claro, su prefijo
+// visible y el hash que se guarda
+func NewAPIKey() (key string, prefix string, hash string) {
+	key = constants.APIKeyPrefix + randomHex(32)
+	return key, key[:prefixLength], HashToken(key)
+}
+
+// HashToken devuelve el hash con el que se guarda y se busca una API key o un
+// refresh token. Son aleatorios y largos, por lo que alcanza con SHA-256 (no
+// hace falta un hash lento como con las contraseñas).
+func HashToken(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewShareToken genera el token de un enlace compartido y devuelve el token en
+// claro, su prefijo visible y el hash que se guarda, como NewAPIKey
+func NewShareToken() (token string, prefix string, hash string) {
+	token = constants.ShareTokenPrefix + randomHex(32)
+	return token, token[:len(constants.ShareTokenPrefix)+8], HashToken(token)
+}
@@ -0,0 +1,358 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// oidcMethods son los algoritmos de firma aceptados en los tokens del proveedor;
+// los simétricos (HS*) no, porque el secreto sería el client secret
+var oidcMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// clockSkew es la tolerancia con el reloj del proveedor al validar exp, nbf e iat
+const clockSkew = 30 * time.Second
+
+// Identity es el usuario de un token del proveedor OIDC ya validado
+type Identity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Roles         []string
+	// Tenant es el slug del claim OIDCConfig.TenantClaim; vacío sin ese claim
+	Tenant string
+}
+
+// jwk es una clave pública del JWKS del proveedor
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// verificationKey es una clave del JWKS ya decodificada; alg vacío acepta
+// cualquier algoritmo compatible con la clave
+type verificationKey struct {
+	alg string
+	key any
+}
+
+// OIDCVerifier valida los tokens de un proveedor OpenID Connect. Obtiene el
+// jwks_uri del documento de descubrimiento y guarda las claves del JWKS durante
+// JWKSCacheTTL; un kid desconocido las vuelve a pedir, como máximo una vez
+// cada JWKSMinRefresh, para seguir al proveedor cuando rota sus claves.
+type OIDCVerifier struct {
+	config config.OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	jwksURI   string
+	keys      map[string]verificationKey
+	fetchedAt time.Time
+	attempted time.Time
+}
+
+// NewOIDCVerifier crea el verificador. No contacta al proveedor hasta validar
+// el primer token, así la API arranca aunque el proveedor no esté disponible.
+func NewOIDCVerifier(oidc config.OIDCConfig) *OIDCVerifier {
+	return &OIDCVerifier{
+		config: oidc,
+		client: &http.Client{Timeout: oidc.HTTPTimeout},
+	}
+}
+
+// Issuer es el emisor (iss) de los tokens que valida
+func (v *OIDCVerifier) Issuer() string {
+	return v.config.Issuer
+}
+
+// Verify valida la firma, el emisor, la audiencia y el vencimiento del token y
+// devuelve la identidad según los claims configurados
+func (v *OIDCVerifier) Verify(ctx context.Context, token string) (Identity, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(oidcMethods),
+		jwt.WithIssuer(v.config.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	}
+	if v.config.Audience != "" {
+		options = append(options, jwt.WithAudience(v.config.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if key.alg != "" && key.alg != t.Method.Alg() {
+			return nil, fmt.Errorf("la clave %q es para %s, no %s", kid, key.alg, t.Method.Alg())
+		}
+		return key.key, nil
+	}, options...)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	identity := Identity{Issuer: v.config.Issuer}
+	identity.Subject, _ = claims["sub"].(string)
+	if identity.Subject == "" {
+		return Identity{}, errors.New("token sin sub")
+	}
+	email, _ := lookupClaim(claims, v.config.EmailClaim).(string)
+	identity.Email = strings.ToLower(strings.TrimSpace(email))
+	identity.Name, _ = lookupClaim(claims, v.config.NameClaim).(string)
+	identity.Roles = stringList(lookupClaim(claims, v.config.RolesClaim))
+	tenant, _ := lookupClaim(claims, v.config.TenantClaim).(string)
+	identity.Tenant = strings.ToLower(strings.TrimSpace(tenant))
+
+	// Algunos proveedores envían email_verified como cadena
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+	return identity, nil
+}
+
+// MapsTenants indica si el tenant de los usuarios sale de un claim (TenantClaim)
+func (v *OIDCVerifier) MapsTenants() bool {
+	return v.config.TenantClaim != ""
+}
+
+// MapsRoles indica si hay un mapeo de roles a scopes configurado (RoleScopes)
+func (v *OIDCVerifier) MapsRoles() bool {
+	return len(v.config.RoleScopes) > 0
+}
+
+// Scopes devuelve los scopes que otorgan los roles según RoleScopes; sin
+// mapeo configurado, los de un usuario local
+func (v *OIDCVerifier) Scopes(roles []string) []string {
+	if len(v.config.RoleScopes) == 0 {
+		return constants.UserScopes
+	}
+	scopes := []string{}
+	for _, role := range roles {
+		for _, scope := range v.config.RoleScopes[role] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// key devuelve la clave con el kid, pidiendo el JWKS si las claves vencieron
+// o si no la conoce. Si el proveedor no responde se siguen usando las claves
+// que ya se tenían.
+func (v *OIDCVerifier) key(ctx context.Context, kid string) (verificationKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key, found := v.lookup(kid)
+	if found && time.Since(v.fetchedAt) < v.config.JWKSCacheTTL {
+		return key, nil
+	}
+	if v.attempted.IsZero() || time.Since(v.attempted) >= v.config.JWKSMinRefresh {
+		if err := v.refresh(ctx); err != nil {
+			log.Printf("WARN: no se pudo obtener el JWKS de %s: %v", v.config.Issuer, err)
+		} else {
+			key, found = v.lookup(kid)
+		}
+	}
+	if !found {
+		return verificationKey{}, fmt.Errorf("clave %q desconocida", kid)
+	}
+	return key, nil
+}
+
+// lookup busca la clave con el kid; un token sin kid solo se acepta si el JWKS
+// tiene una única clave. Requiere v.mu tomado.
+func (v *OIDCVerifier) lookup(kid string) (verificationKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, found := v.keys[kid]
+	return key, found
+}
+
+// refresh obtiene el jwks_uri (la primera vez) y las claves del JWKS.
+// Requiere v.mu tomado.
+func (v *OIDCVerifier) refresh(ctx context.Context) error {
+	v.attempted = time.Now()
+
+	if v.jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.fetch(ctx, v.config.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+			return err
+		}
+		// El emisor del documento debe ser exactamente el configurado (OIDC Discovery 4.3)
+		if strings.TrimSuffix(discovery.Issuer, "/") != v.config.Issuer {
+			return fmt.Errorf("el documento de descubrimiento es del emisor %q", discovery.Issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return errors.New("el documento de descubrimiento no tiene jwks_uri")
+		}
+		v.jwksURI = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := v.fetch(ctx, v.jwksURI, &set); err != nil {
+		return err
+	}
+	keys := make(map[string]verificationKey, len(set.Keys))
+	for _, raw := range set.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		key, err := parseJWK(raw)
+		if err != nil {
+			log.Printf("WARN: se ignora la clave %q del JWKS de %s: %v", raw.Kid, v.config.Issuer, err)
+			continue
+		}
+		keys[raw.Kid] = verificationKey{alg: raw.Alg, key: key}
+	}
+	if len(keys) == 0 {
+		return errors.New("el JWKS no tiene claves de firma válidas")
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	return nil
+}
+
+// fetch hace un GET a url y decodifica la respuesta JSON en target
+func (v *OIDCVerifier) fetch(ctx context.Context, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s respondió %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// parseJWK decodifica una clave pública RSA o EC (P-256, P-384 o P-521)
+func parseJWK(raw jwk) (any, error) {
+	switch raw.Kty {
+	case "RSA":
+		n, err := decodeBigInt(raw.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(raw.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("exponente RSA inválido")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{
+			"P-256": elliptic.P256(),
+			"P-384": elliptic.P384(),
+			"P-521": elliptic.P521(),
+		}
+		curve, ok := curves[raw.Crv]
+		if !ok {
+			return nil, fmt.Errorf("curva %q no soportada", raw.Crv)
+		}
+		x, err := decodeBigInt(raw.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(raw.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("el punto no está en la curva")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("tipo de clave %q no soportado", raw.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("entero base64url inválido")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// lookupClaim busca un claim por su ruta con puntos, por ejemplo
+// realm_access.roles para los roles de Keycloak
+func lookupClaim(claims map[string]any, path string) any {
+	if path == "" {
+		return nil
+	}
+	var value any = claims
+	for _, part := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[part]
+	}
+	return value
+}
+
+// stringList convierte un claim en una lista de cadenas: acepta un arreglo o
+// una cadena con los valores separados por espacios
+func stringList(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
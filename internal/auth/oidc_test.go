@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth/oidctest"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newVerifier(issuer *oidctest.Issuer) *OIDCVerifier {
+	oidc := config.DefaultOIDCConfig()
+	oidc.Issuer = issuer.URL()
+	oidc.Audience = "todo-api"
+	oidc.JWKSMinRefresh = 0
+	return NewOIDCVerifier(oidc)
+}
+
+func TestOIDCVerify(t *testing.T) {
+	issuer := oidctest.NewIssuer()
+	defer issuer.Close()
+	verifier := newVerifier(issuer)
+	ctx := context.Background()
+
+	identity, err := verifier.Verify(ctx, issuer.Token(jwt.MapClaims{
+		"aud":            "todo-api",
+		"email":          "Ana@Example.com",
+		"email_verified": true,
+		"name":           "Ana",
+		"roles":          []string{"editor", "viewer"},
+	}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, issuer.URL(), identity.Issuer)
+		assert.Equal(t, "subject-1", identity.Subject)
+		assert.Equal(t, "ana@example.com", identity.Email)
+		assert.True(t, identity.EmailVerified)
+		assert.Equal(t, "Ana", identity.Name)
+		assert.Equal(t, []string{"editor", "viewer"}, identity.Roles)
+	}
+
+	rejected := map[string]jwt.MapClaims{
+		"otra audiencia": {"aud": "otra-api"},
+		"sin audiencia":  {},
+		"vencido":        {"aud": "todo-api", "exp": time.Now().Add(-time.Hour).Unix()},
+		"otro emisor":    {"aud": "todo-api", "iss": "https://otro.example.com"},
+		"sin sub":        {"aud": "todo-api", "sub": ""},
+	}
+	for name, claims := range rejected {
+		_, err := verifier.Verify(ctx, issuer.Token(claims))
+		assert.Error(t, err, name)
+	}
+
+	// Un token firmado por otro proveedor con el mismo kid no vale
+	other := oidctest.NewIssuer()
+	defer other.Close()
+	_, err = verifier.Verify(ctx, other.Token(jwt.MapClaims{"aud": "todo-api", "iss": issuer.URL()}))
+	assert.Error(t, err)
+}
+
+func TestOIDCKeyRotation(t *testing.T) {
+	issuer := oidctest.NewIssuer()
+	defer issuer.Close()
+	verifier := newVerifier(issuer)
+	ctx := context.Background()
+	claims := jwt.MapClaims{"aud": "todo-api"}
+
+	_, err := verifier.Verify(ctx, issuer.Token(claims))
+	assert.NoError(t, err)
+	_, err = verifier.Verify(ctx, issuer.Token(claims))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, issuer.JWKSRequests(), "las claves quedan en caché")
+
+	// Un kid nuevo vuelve a pedir el JWKS
+	old := issuer.Token(claims)
+	issuer.RotateKey()
+	_, err = verifier.Verify(ctx, issuer.Token(claims))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, issuer.JWKSRequests())
+
+	// Cuando el proveedor retira la clave anterior sus tokens dejan de valer
+	_, err = verifier.Verify(ctx, old)
+	assert.NoError(t, err)
+	issuer.RetireKeys()
+	verifier.config.JWKSCacheTTL = 0
+	_, err = verifier.Verify(ctx, old)
+	assert.Error(t, err)
+}
+
+func TestOIDCRefreshIsThrottled(t *testing.T) {
+	issuer := oidctest.NewIssuer()
+	defer issuer.Close()
+	verifier := newVerifier(issuer)
+	verifier.config.JWKSMinRefresh = time.Hour
+	ctx := context.Background()
+	claims := jwt.MapClaims{"aud": "todo-api"}
+
+	_, err := verifier.Verify(ctx, issuer.Token(claims))
+	assert.NoError(t, err)
+
+	// Los kid desconocidos no pueden forzar pedidos al proveedor
+	issuer.RotateKey()
+	for range 3 {
+		_, err = verifier.Verify(ctx, issuer.Token(claims))
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 1, issuer.JWKSRequests())
+}
+
+func TestOIDCUnavailableIssuer(t *testing.T) {
+	issuer := oidctest.NewIssuer()
+	verifier := newVerifier(issuer)
+	token := issuer.Token(jwt.MapClaims{"aud": "todo-api"})
+	issuer.Close()
+
+	_, err := verifier.Verify(context.Background(), token)
+	assert.Error(t, err)
+}
+
+func TestOIDCClaimMapping(t *testing.T) {
+	issuer := oidctest.NewIssuer()
+	defer issuer.Close()
+	verifier := newVerifier(issuer)
+	verifier.config.EmailClaim = "preferred_username"
+	verifier.config.RolesClaim = "realm_access.roles"
+	verifier.config.TenantClaim = "org.slug"
+
+	identity, err := verifier.Verify(context.Background(), issuer.Token(jwt.MapClaims{
+		"aud":                "todo-api",
+		"preferred_username": "ana@example.com",
+		"email_verified":     "true",
+		"realm_access":       map[string]any{"roles": []string{"todo-admin"}},
+		"org":                map[string]any{"slug": " Acme "},
+	}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "ana@example.com", identity.Email)
+		assert.True(t, identity.EmailVerified)
+		assert.Equal(t, []string{"todo-admin"}, identity.Roles)
+		assert.Equal(t, "acme", identity.Tenant)
+	}
+
+	assert.Equal(t, []string{"read", "write"}, stringList("read write"))
+	assert.Nil(t, stringList(42))
+}
+
+func TestOIDCScopes(t *testing.T) {
+	verifier := NewOIDCVerifier(config.DefaultOIDCConfig())
+	assert.Equal(t, constants.UserScopes, verifier.Scopes(nil))
+
+	verifier.config.RoleScopes = map[string][]string{
+		"viewer": {constants.ScopeItemsRead},
+		"editor": {constants.ScopeItemsRead, constants.ScopeItemsWrite},
+	}
+	assert.Equal(t, []string{constants.ScopeItemsRead}, verifier.Scopes([]string{"viewer", "otro"}))
+	assert.Equal(t, []string{constants.ScopeItemsRead, constants.ScopeItemsWrite}, verifier.Scopes([]string{"viewer", "editor"}))
+	assert.Empty(t, verifier.Scopes([]string{"otro"}))
+}
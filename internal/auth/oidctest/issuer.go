@@ -0,0 +1,142 @@
+// Package oidctest tiene un proveedor OpenID Connect local para las pruebas:
+// sirve el documento de descubrimiento y el JWKS y firma tokens con sus claves.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type signingKey struct {
+	kid string
+	key *rsa.PrivateKey
+}
+
+// Issuer es un proveedor OIDC de prueba que firma con RS256. La última clave
+// creada es la que firma; las anteriores siguen publicadas hasta RetireKeys.
+type Issuer struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	keys         []signingKey
+	jwksRequests int
+}
+
+// NewIssuer levanta el proveedor con una clave; hay que cerrarlo con Close
+func NewIssuer() *Issuer {
+	issuer := &Issuer{}
+	issuer.RotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.serveDiscovery)
+	mux.HandleFunc("/jwks", issuer.serveJWKS)
+	issuer.server = httptest.NewServer(mux)
+	return issuer
+}
+
+// URL es el emisor (iss) de los tokens
+func (i *Issuer) URL() string {
+	return i.server.URL
+}
+
+func (i *Issuer) Close() {
+	i.server.Close()
+}
+
+// RotateKey crea una clave nueva, que pasa a firmar los tokens, y devuelve su kid
+func (i *Issuer) RotateKey() string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	kid := "key-" + strconv.Itoa(len(i.keys)+1)
+	i.keys = append(i.keys, signingKey{kid: kid, key: key})
+	return kid
+}
+
+// RetireKeys deja de publicar todas las claves salvo la que firma
+func (i *Issuer) RetireKeys() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keys = i.keys[len(i.keys)-1:]
+}
+
+// JWKSRequests es la cantidad de veces que se pidió el JWKS
+func (i *Issuer) JWKSRequests() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.jwksRequests
+}
+
+// Token firma un token con la clave actual. Completa iss, sub, iat y exp
+// (una hora) si no están en claims.
+func (i *Issuer) Token(claims jwt.MapClaims) string {
+	now := time.Now()
+	full := jwt.MapClaims{
+		"iss": i.URL(),
+		"sub": "subject-1",
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	for name, value := range claims {
+		full[name] = value
+	}
+
+	i.mu.Lock()
+	current := i.keys[len(i.keys)-1]
+	i.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, full)
+	token.Header["kid"] = current.kid
+	signed, err := token.SignedString(current.key)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func (i *Issuer) serveDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"issuer":                                i.URL(),
+		"jwks_uri":                              i.URL() + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (i *Issuer) serveJWKS(w http.ResponseWriter, r *http.Request) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.jwksRequests++
+
+	keys := make([]map[string]string, 0, len(i.keys))
+	for _, key := range i.keys {
+		public := key.key.PublicKey
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": key.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		})
+	}
+	writeJSON(w, map[string]any{"keys": keys})
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,23 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// dummyHash se compara cuando el usuario no existe, para que el login tarde lo
+// mismo y no revele qué emails están registrados
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("contraseña de relleno"), bcrypt.DefaultCost)
+
+// HashPassword devuelve el hash bcrypt de la contraseña
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// CheckPassword indica si la contraseña corresponde al hash; con hash vacío
+// (usuario inexistente) igual hace la comparación y devuelve false
+func CheckPassword(hash string, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
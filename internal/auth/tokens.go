@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// issuer es el emisor (iss) de los tokens de acceso
+const issuer = "devops_todo_go"
+
+// Claims son los datos de un token de acceso: el usuario (sub), su email y el
+// tenant en el que vale
+type Claims struct {
+	Email    string `json:"email"`
+	TenantID string `json:"tid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// TokenIssuer emite y valida los tokens de acceso (JWT firmados con HS256)
+type TokenIssuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewTokenIssuer crea el emisor; con secret vacío usa uno aleatorio, por lo que
+// los tokens no sobreviven a un reinicio ni sirven en otras instancias
+func NewTokenIssuer(secret string, ttl time.Duration) *TokenIssuer {
+	key := []byte(secret)
+	if secret == "" {
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+	return &TokenIssuer{secret: key, ttl: ttl}
+}
+
+// TTL es la duración de los tokens de acceso
+func (i *TokenIssuer) TTL() time.Duration {
+	return i.ttl
+}
+
+// Issue emite un token de acceso para el usuario
+func (i *TokenIssuer) Issue(user models.User) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Email:    user.Email,
+		TenantID: user.TenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   user.ID,
+			ID:        randomHex(16),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+}
+
+// Parse valida la firma, el emisor y el vencimiento del token y devuelve sus claims
+func (i *TokenIssuer) Parse(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return i.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(issuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" || claims.ID == "" {
+		return nil, errors.New("token sin sub o jti")
+	}
+	return claims, nil
+}
+
+// UnverifiedIssuer devuelve el emisor (iss) del token sin validarlo, para
+// decidir con quién validarlo; vacío si no es un JWT
+func UnverifiedIssuer(token string) string {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return ""
+	}
+	iss, _ := claims["iss"].(string)
+	return iss
+}
+
+// NewRefreshToken genera un refresh token aleatorio y devuelve el token en claro
+// y el hash que se guarda
+func NewRefreshToken() (token string, hash string) {
+	token = randomHex(32)
+	return token, HashToken(token)
+}
+
+func randomHex(size int) string {
+	buf := make([]byte, size)
+	// crypto/rand.Read no falla en las plataformas soportadas
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
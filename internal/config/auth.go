@@ -0,0 +1,90 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// AuthConfig configura la autenticación de la API con API keys y con los tokens
+// de acceso de los usuarios
+type AuthConfig struct {
+	// Enabled exige una API key en las rutas de la API; deshabilitarla deja la API abierta
+	Enabled bool
+	// BootstrapKey es una key con scope admin definida por entorno, para emitir
+	// las primeras keys; vacía no se acepta ninguna
+	BootstrapKey string
+	// TouchInterval es cada cuánto, como máximo, se registra el último uso de una
+	// key, para no escribir en la base de datos en cada petición
+	TouchInterval time.Duration
+	// JWTSecret firma los tokens de acceso; vacío se usa uno aleatorio por proceso
+	JWTSecret string
+	// AccessTokenTTL y RefreshTokenTTL son la duración de los tokens de acceso y de refresco
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// OIDC valida además los tokens de un proveedor de identidad externo
+	OIDC OIDCConfig
+}
+
+// DefaultAuthConfig devuelve la configuración por defecto
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		Enabled:         true,
+		TouchInterval:   time.Minute,
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 30 * 24 * time.Hour,
+		OIDC:            DefaultOIDCConfig(),
+	}
+}
+
+// NewAuthConfig crea la configuración desde variables de entorno, usando los
+// valores por defecto para las que no estén definidas o sean inválidas
+func NewAuthConfig() AuthConfig {
+	auth := DefaultAuthConfig()
+
+	if value := os.Getenv("API_AUTH_ENABLED"); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			auth.Enabled = parsed
+		} else {
+			log.Printf("WARN: API_AUTH_ENABLED inválido (%q), se usa %v", value, auth.Enabled)
+		}
+	}
+
+	auth.BootstrapKey = os.Getenv("API_BOOTSTRAP_KEY")
+
+	if value := os.Getenv("API_KEY_TOUCH_INTERVAL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed >= 0 {
+			auth.TouchInterval = parsed
+		} else {
+			log.Printf("WARN: API_KEY_TOUCH_INTERVAL inválido (%q), se usa %s", value, auth.TouchInterval)
+		}
+	}
+
+	auth.JWTSecret = os.Getenv("JWT_SECRET")
+	if auth.JWTSecret == "" {
+		log.Println("WARN: JWT_SECRET no definido, se usa uno aleatorio: las sesiones no sobreviven a un reinicio ni se comparten entre instancias")
+	}
+
+	durations := map[string]*time.Duration{
+		"JWT_ACCESS_TTL":  &auth.AccessTokenTTL,
+		"JWT_REFRESH_TTL": &auth.RefreshTokenTTL,
+	}
+	for name, target := range durations {
+		if value := os.Getenv(name); value != "" {
+			if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+				*target = parsed
+			} else {
+				log.Printf("WARN: %s inválido (%q), se usa %s", name, value, *target)
+			}
+		}
+	}
+
+	auth.OIDC = NewOIDCConfig()
+
+	if !auth.Enabled {
+		log.Println("WARN: API_AUTH_ENABLED=false, la API no exige API keys")
+	}
+
+	return auth
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"log"
+	"os"
+)
+
+// Modos de aplicación de las dependencias entre items
+const (
+	// EnforcementRefuse rechaza iniciar o completar un item con bloqueantes abiertos
+	EnforcementRefuse = "refuse"
+	// EnforcementWarn permite el cambio pero agrega una advertencia a la respuesta
+	EnforcementWarn = "warn"
+	// EnforcementOff no verifica los bloqueantes
+	EnforcementOff = "off"
+)
+
+// DependencyRules son las reglas configurables para items bloqueados por otros
+type DependencyRules struct {
+	Enforcement string
+}
+
+// DefaultDependencyRules devuelve las reglas por defecto
+func DefaultDependencyRules() DependencyRules {
+	return DependencyRules{
+		Enforcement: EnforcementRefuse,
+	}
+}
+
+// NewDependencyRules crea las reglas desde variables de entorno
+func NewDependencyRules() DependencyRules {
+	rules := DefaultDependencyRules()
+
+	switch value := os.Getenv("DEPENDENCIES_ENFORCEMENT"); value {
+	case "":
+	case EnforcementRefuse, EnforcementWarn, EnforcementOff:
+		rules.Enforcement = value
+	default:
+		log.Printf("WARN: DEPENDENCIES_ENFORCEMENT inválido (%q), se usa %s", value, rules.Enforcement)
+	}
+
+	return rules
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// GraphQLConfig limita el costo de las consultas a /graphql
+type GraphQLConfig struct {
+	// MaxDepth es la profundidad máxima de anidamiento de campos (sin contar la introspección)
+	MaxDepth int
+	// MaxComplexity es el costo máximo estimado: cada campo suma 1 y los campos
+	// de lista multiplican el costo de sus subcampos por la cantidad esperada de elementos
+	MaxComplexity int
+}
+
+// DefaultGraphQLConfig devuelve la configuración por defecto
+func DefaultGraphQLConfig() GraphQLConfig {
+	return GraphQLConfig{
+		MaxDepth:      10,
+		MaxComplexity: 1000,
+	}
+}
+
+// NewGraphQLConfig crea la configuración desde variables de entorno, usando los
+// valores por defecto para las que no estén definidas o sean inválidas
+func NewGraphQLConfig() GraphQLConfig {
+	graphql := DefaultGraphQLConfig()
+
+	limits := map[string]*int{
+		"GRAPHQL_MAX_DEPTH":      &graphql.MaxDepth,
+		"GRAPHQL_MAX_COMPLEXITY": &graphql.MaxComplexity,
+	}
+	for name, target := range limits {
+		if value := os.Getenv(name); value != "" {
+			if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+				*target = parsed
+			} else {
+				log.Printf("WARN: %s inválido (%q), se usa %d", name, value, *target)
+			}
+		}
+	}
+
+	return graphql
+}
@@ -0,0 +1,24 @@
+package config
+
+import "os"
+
+// GRPCConfig configura la API gRPC, que se sirve junto a la API REST
+type GRPCConfig struct {
+	// Addr es la dirección en la que escucha; vacía deshabilita la API gRPC
+	Addr string
+}
+
+// DefaultGRPCConfig devuelve la configuración por defecto
+func DefaultGRPCConfig() GRPCConfig {
+	return GRPCConfig{Addr: ":9090"}
+}
+
+// NewGRPCConfig crea la configuración desde variables de entorno. GRPC_ADDR
+// definida y vacía deshabilita la API gRPC.
+func NewGRPCConfig() GRPCConfig {
+	grpc := DefaultGRPCConfig()
+	if value, ok := os.LookupEnv("GRPC_ADDR"); ok {
+		grpc.Addr = value
+	}
+	return grpc
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// HierarchyRules son las reglas configurables para items con subtareas
+type HierarchyRules struct {
+	// BlockParentCompletion impide completar un item mientras tenga subtareas sin completar
+	BlockParentCompletion bool
+	// MaxDepth limita la cantidad de niveles del árbol (1 = sin subtareas); 0 es sin límite
+	MaxDepth int
+}
+
+// DefaultHierarchyRules devuelve las reglas por defecto
+func DefaultHierarchyRules() HierarchyRules {
+	return HierarchyRules{
+		BlockParentCompletion: true,
+		MaxDepth:              0,
+	}
+}
+
+// NewHierarchyRules crea las reglas desde variables de entorno, usando los valores
+// por defecto para las que no estén definidas o sean inválidas
+func NewHierarchyRules() HierarchyRules {
+	rules := DefaultHierarchyRules()
+
+	if value := os.Getenv("SUBTASKS_BLOCK_PARENT_COMPLETION"); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			rules.BlockParentCompletion = parsed
+		} else {
+			log.Printf("WARN: SUBTASKS_BLOCK_PARENT_COMPLETION inválido (%q), se usa %v", value, rules.BlockParentCompletion)
+		}
+	}
+
+	if value := os.Getenv("SUBTASKS_MAX_DEPTH"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			rules.MaxDepth = parsed
+		} else {
+			log.Printf("WARN: SUBTASKS_MAX_DEPTH inválido (%q), se usa %d", value, rules.MaxDepth)
+		}
+	}
+
+	return rules
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// OIDCConfig configura la validación de los tokens de un proveedor de identidad
+// OpenID Connect. Sin Issuer la integración está deshabilitada.
+type OIDCConfig struct {
+	// Issuer es la URL del emisor; su documento de descubrimiento está en
+	// <Issuer>/.well-known/openid-configuration
+	Issuer string
+	// Audience es el aud que deben tener los tokens (normalmente el client ID); vacío no se valida
+	Audience string
+	// EmailClaim y NameClaim son los claims con el email y el nombre del usuario
+	EmailClaim string
+	NameClaim  string
+	// RolesClaim es el claim con los roles; admite rutas con puntos (realm_access.roles)
+	RolesClaim string
+	// TenantClaim es el claim con el slug del tenant del usuario; admite rutas con
+	// puntos. Vacío, los usuarios nuevos del proveedor se crean en el tenant por
+	// defecto, si admite el registro.
+	TenantClaim string
+	// RoleScopes otorga scopes según los roles. Vacío, todo usuario del proveedor
+	// tiene los scopes de un usuario local (items:read e items:write).
+	RoleScopes map[string][]string
+	// JWKSCacheTTL es cuánto se usan las claves del JWKS antes de volver a pedirlas
+	JWKSCacheTTL time.Duration
+	// JWKSMinRefresh es la espera mínima entre dos pedidos del JWKS, para que
+	// tokens con un kid desconocido no saturen al proveedor
+	JWKSMinRefresh time.Duration
+	// HTTPTimeout es el tiempo máximo de cada petición al proveedor
+	HTTPTimeout time.Duration
+}
+
+// DefaultOIDCConfig devuelve la configuración por defecto (deshabilitada)
+func DefaultOIDCConfig() OIDCConfig {
+	return OIDCConfig{
+		EmailClaim:     "email",
+		NameClaim:      "name",
+		RolesClaim:     "roles",
+		RoleScopes:     map[string][]string{},
+		JWKSCacheTTL:   time.Hour,
+		JWKSMinRefresh: 10 * time.Second,
+		HTTPTimeout:    5 * time.Second,
+	}
+}
+
+// NewOIDCConfig crea la configuración desde variables de entorno, usando los
+// valores por defecto para las que no estén definidas o sean inválidas.
+// OIDC_ROLE_SCOPES tiene la forma rol=scope,scope;otro-rol=scope.
+func NewOIDCConfig() OIDCConfig {
+	oidc := DefaultOIDCConfig()
+
+	oidc.Issuer = strings.TrimSuffix(os.Getenv("OIDC_ISSUER"), "/")
+	oidc.Audience = os.Getenv("OIDC_AUDIENCE")
+
+	claims := map[string]*string{
+		"OIDC_EMAIL_CLAIM":  &oidc.EmailClaim,
+		"OIDC_NAME_CLAIM":   &oidc.NameClaim,
+		"OIDC_ROLES_CLAIM":  &oidc.RolesClaim,
+		"OIDC_TENANT_CLAIM": &oidc.TenantClaim,
+	}
+	for name, target := range claims {
+		if value := os.Getenv(name); value != "" {
+			*target = value
+		}
+	}
+
+	if value := os.Getenv("OIDC_ROLE_SCOPES"); value != "" {
+		for _, mapping := range strings.Split(value, ";") {
+			role, scopes, found := strings.Cut(mapping, "=")
+			role = strings.TrimSpace(role)
+			if !found || role == "" {
+				log.Printf("WARN: OIDC_ROLE_SCOPES inválido (%q), se ignora", mapping)
+				continue
+			}
+			for _, scope := range strings.Split(scopes, ",") {
+				scope = strings.TrimSpace(scope)
+				if !constants.IsValidScope(scope) {
+					log.Printf("WARN: OIDC_ROLE_SCOPES tiene un scope inválido (%q) para %s, se ignora", scope, role)
+					continue
+				}
+				oidc.RoleScopes[role] = append(oidc.RoleScopes[role], scope)
+			}
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"OIDC_JWKS_CACHE_TTL":   &oidc.JWKSCacheTTL,
+		"OIDC_JWKS_MIN_REFRESH": &oidc.JWKSMinRefresh,
+		"OIDC_HTTP_TIMEOUT":     &oidc.HTTPTimeout,
+	}
+	for name, target := range durations {
+		if value := os.Getenv(name); value != "" {
+			if parsed, err := time.ParseDuration(value); err == nil && parsed >= 0 {
+				*target = parsed
+			} else {
+				log.Printf("WARN: %s inválido (%q), se usa %s", name, value, *target)
+			}
+		}
+	}
+
+	return oidc
+}
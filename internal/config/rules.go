@@ -0,0 +1,32 @@
+package config
+
+// ItemRules agrupa las reglas de negocio configurables que aplica el ItemHandler
+type ItemRules struct {
+	Workflow     Workflow
+	Hierarchy    HierarchyRules
+	Dependencies DependencyRules
+}
+
+// DefaultItemRules devuelve todas las reglas con sus valores por defecto
+func DefaultItemRules() ItemRules {
+	return ItemRules{
+		Workflow:     DefaultWorkflow(),
+		Hierarchy:    DefaultHierarchyRules(),
+		Dependencies: DefaultDependencyRules(),
+	}
+}
+
+// NewItemRules crea todas las reglas desde variables de entorno. Falla solo si
+// el flujo de estados configurado no se puede cargar.
+func NewItemRules() (ItemRules, error) {
+	workflow, err := LoadWorkflow()
+	if err != nil {
+		return ItemRules{}, err
+	}
+
+	return ItemRules{
+		Workflow:     workflow,
+		Hierarchy:    NewHierarchyRules(),
+		Dependencies: NewDependencyRules(),
+	}, nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// Modos de almacenamiento de los items
+const (
+	// StorageCRUD guarda los items directamente en todo_items
+	StorageCRUD = "crud"
+	// StorageEventSourced deriva los items de un flujo de eventos y proyecta el resultado en todo_items
+	StorageEventSourced = "event_sourced"
+)
+
+// StorageConfig define cómo se guardan los items
+type StorageConfig struct {
+	Mode string
+	// SnapshotEvery es cada cuántos eventos de un item se guarda un snapshot (solo event_sourced)
+	SnapshotEvery int
+	// RebuildProjections reconstruye todo_items desde el flujo de eventos al iniciar (solo event_sourced)
+	RebuildProjections bool
+}
+
+// DefaultStorageConfig devuelve la configuración por defecto
+func DefaultStorageConfig() StorageConfig {
+	return StorageConfig{
+		Mode:               StorageCRUD,
+		SnapshotEvery:      50,
+		RebuildProjections: false,
+	}
+}
+
+// NewStorageConfig crea la configuración desde variables de entorno
+func NewStorageConfig() StorageConfig {
+	storage := DefaultStorageConfig()
+
+	switch value := os.Getenv("STORAGE_MODE"); value {
+	case "":
+	case StorageCRUD, StorageEventSourced:
+		storage.Mode = value
+	default:
+		log.Printf("WARN: STORAGE_MODE inválido (%q), se usa %s", value, storage.Mode)
+	}
+
+	if value := os.Getenv("EVENT_SNAPSHOT_EVERY"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			storage.SnapshotEvery = parsed
+		} else {
+			log.Printf("WARN: EVENT_SNAPSHOT_EVERY inválido (%q), se usa %d", value, storage.SnapshotEvery)
+		}
+	}
+
+	if value := os.Getenv("REBUILD_PROJECTIONS"); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			storage.RebuildProjections = parsed
+		} else {
+			log.Printf("WARN: REBUILD_PROJECTIONS inválido (%q), se usa %v", value, storage.RebuildProjections)
+		}
+	}
+
+	return storage
+}
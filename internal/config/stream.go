@@ -0,0 +1,78 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Transportes del stream de items entre instancias
+const (
+	// StreamPubSubMemory reparte los eventos solo dentro de la instancia
+	StreamPubSubMemory = "memory"
+	// StreamPubSubMySQL comparte los eventos entre instancias a través de MySQL
+	StreamPubSubMySQL = "mysql"
+)
+
+// StreamConfig configura el stream de cambios de items (GET /items/stream)
+type StreamConfig struct {
+	PubSub string
+	// ReplayBuffer es cuántos eventos se guardan para reenviar al reconectarse con Last-Event-ID
+	ReplayBuffer int
+	// Heartbeat es cada cuánto se envía un comentario para mantener viva la conexión
+	Heartbeat time.Duration
+	// PollInterval y Retention solo aplican al transporte mysql
+	PollInterval time.Duration
+	Retention    time.Duration
+}
+
+// DefaultStreamConfig devuelve la configuración por defecto
+func DefaultStreamConfig() StreamConfig {
+	return StreamConfig{
+		PubSub:       StreamPubSubMemory,
+		ReplayBuffer: 1000,
+		Heartbeat:    15 * time.Second,
+		PollInterval: time.Second,
+		Retention:    24 * time.Hour,
+	}
+}
+
+// NewStreamConfig crea la configuración desde variables de entorno, usando los
+// valores por defecto para las que no estén definidas o sean inválidas
+func NewStreamConfig() StreamConfig {
+	stream := DefaultStreamConfig()
+
+	switch value := os.Getenv("STREAM_PUBSUB"); value {
+	case "":
+	case StreamPubSubMemory, StreamPubSubMySQL:
+		stream.PubSub = value
+	default:
+		log.Printf("WARN: STREAM_PUBSUB inválido (%q), se usa %s", value, stream.PubSub)
+	}
+
+	if value := os.Getenv("STREAM_REPLAY_BUFFER"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			stream.ReplayBuffer = parsed
+		} else {
+			log.Printf("WARN: STREAM_REPLAY_BUFFER inválido (%q), se usa %d", value, stream.ReplayBuffer)
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"STREAM_HEARTBEAT":     &stream.Heartbeat,
+		"STREAM_POLL_INTERVAL": &stream.PollInterval,
+		"STREAM_RETENTION":     &stream.Retention,
+	}
+	for name, target := range durations {
+		if value := os.Getenv(name); value != "" {
+			if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+				*target = parsed
+			} else {
+				log.Printf("WARN: %s inválido (%q), se usa %s", name, value, *target)
+			}
+		}
+	}
+
+	return stream
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// TenantConfig indica cómo se elige el tenant de una petición sin credenciales
+// que lo fijen (o para verificar que coincida): la cabecera X-Tenant o, si
+// BaseDomain no está vacío, el subdominio de BaseDomain (acme.todo.example.com
+// es el tenant acme)
+type TenantConfig struct {
+	BaseDomain string
+}
+
+// DefaultTenantConfig devuelve la configuración por defecto: solo la cabecera X-Tenant
+func DefaultTenantConfig() TenantConfig {
+	return TenantConfig{}
+}
+
+// NewTenantConfig crea la configuración desde TENANT_BASE_DOMAIN
+func NewTenantConfig() TenantConfig {
+	tenants := DefaultTenantConfig()
+	tenants.BaseDomain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(os.Getenv("TENANT_BASE_DOMAIN")), "."))
+	return tenants
+}
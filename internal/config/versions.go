@@ -0,0 +1,45 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// VersionConfig indica cuándo se deprecó la versión 1 de la API de items y cuándo
+// se retira; se informan en las cabeceras Deprecation y Sunset de sus respuestas
+type VersionConfig struct {
+	V1Deprecation time.Time
+	V1Sunset      time.Time
+}
+
+// DefaultVersionConfig devuelve la configuración por defecto: v1 deprecada desde
+// la publicación de v2 y retirada seis meses después
+func DefaultVersionConfig() VersionConfig {
+	return VersionConfig{
+		V1Deprecation: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		V1Sunset:      time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// NewVersionConfig crea la configuración desde variables de entorno (fechas
+// RFC3339), usando los valores por defecto para las que no estén definidas o sean inválidas
+func NewVersionConfig() VersionConfig {
+	versions := DefaultVersionConfig()
+
+	dates := map[string]*time.Time{
+		"API_V1_DEPRECATION": &versions.V1Deprecation,
+		"API_V1_SUNSET":      &versions.V1Sunset,
+	}
+	for name, target := range dates {
+		if value := os.Getenv(name); value != "" {
+			if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+				*target = parsed
+			} else {
+				log.Printf("WARN: %s inválido (%q), se usa %s", name, value, target.Format(time.RFC3339))
+			}
+		}
+	}
+
+	return versions
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// WebhookConfig configura el despacho de webhooks desde el outbox
+type WebhookConfig struct {
+	// PollInterval es cada cuánto se revisan el outbox y las entregas pendientes
+	PollInterval time.Duration
+	// Timeout es el tiempo máximo de cada petición al receptor
+	Timeout time.Duration
+	// MaxAttempts es la cantidad de intentos antes de pasar la entrega a dead-letter
+	MaxAttempts int
+	// BaseBackoff es la espera tras el primer fallo; se duplica en cada intento hasta MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultWebhookConfig devuelve la configuración por defecto
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		PollInterval: 2 * time.Second,
+		Timeout:      10 * time.Second,
+		MaxAttempts:  8,
+		BaseBackoff:  10 * time.Second,
+		MaxBackoff:   time.Hour,
+	}
+}
+
+// NewWebhookConfig crea la configuración desde variables de entorno, usando los
+// valores por defecto para las que no estén definidas o sean inválidas
+func NewWebhookConfig() WebhookConfig {
+	webhooks := DefaultWebhookConfig()
+
+	durations := map[string]*time.Duration{
+		"WEBHOOK_POLL_INTERVAL": &webhooks.PollInterval,
+		"WEBHOOK_TIMEOUT":       &webhooks.Timeout,
+		"WEBHOOK_BACKOFF_BASE":  &webhooks.BaseBackoff,
+		"WEBHOOK_BACKOFF_MAX":   &webhooks.MaxBackoff,
+	}
+	for name, target := range durations {
+		if value := os.Getenv(name); value != "" {
+			if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+				*target = parsed
+			} else {
+				log.Printf("WARN: %s inválido (%q), se usa %s", name, value, *target)
+			}
+		}
+	}
+
+	if value := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			webhooks.MaxAttempts = parsed
+		} else {
+			log.Printf("WARN: WEBHOOK_MAX_ATTEMPTS inválido (%q), se usa %d", value, webhooks.MaxAttempts)
+		}
+	}
+
+	return webhooks
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebSocketConfig configura el canal colaborativo en vivo (GET /ws)
+type WebSocketConfig struct {
+	// PingInterval es cada cuánto se envía un ping; si el cliente no responde
+	// con un pong en dos intervalos se lo desconecta
+	PingInterval time.Duration
+	// WriteTimeout es el tiempo máximo para escribir un mensaje al cliente
+	WriteTimeout time.Duration
+	// SendBuffer es cuántos mensajes puede tener pendientes un cliente antes de
+	// considerarlo lento y desconectarlo
+	SendBuffer int
+	// MaxMessageSize es el tamaño máximo, en bytes, de un mensaje del cliente
+	MaxMessageSize int64
+	// AllowedOrigins son los orígenes aceptados además del propio; "*" acepta cualquiera
+	AllowedOrigins []string
+}
+
+// DefaultWebSocketConfig devuelve la configuración por defecto
+func DefaultWebSocketConfig() WebSocketConfig {
+	return WebSocketConfig{
+		PingInterval:   30 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		SendBuffer:     64,
+		MaxMessageSize: 64 << 10,
+	}
+}
+
+// NewWebSocketConfig crea la configuración desde variables de entorno, usando
+// los valores por defecto para las que no estén definidas o sean inválidas
+func NewWebSocketConfig() WebSocketConfig {
+	ws := DefaultWebSocketConfig()
+
+	durations := map[string]*time.Duration{
+		"WS_PING_INTERVAL": &ws.PingInterval,
+		"WS_WRITE_TIMEOUT": &ws.WriteTimeout,
+	}
+	for name, target := range durations {
+		if value := os.Getenv(name); value != "" {
+			if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+				*target = parsed
+			} else {
+				log.Printf("WARN: %s inválido (%q), se usa %s", name, value, *target)
+			}
+		}
+	}
+
+	if value := os.Getenv("WS_SEND_BUFFER"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			ws.SendBuffer = parsed
+		} else {
+			log.Printf("WARN: WS_SEND_BUFFER inválido (%q), se usa %d", value, ws.SendBuffer)
+		}
+	}
+
+	if value := os.Getenv("WS_ALLOWED_ORIGINS"); value != "" {
+		for _, origin := range strings.Split(value, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				ws.AllowedOrigins = append(ws.AllowedOrigins, origin)
+			}
+		}
+	}
+
+	return ws
+}
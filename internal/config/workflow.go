@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// Workflow es la definición declarativa de estados y transiciones de los items.
+// Se carga desde un archivo JSON con el mismo formato, por ejemplo:
+//
+//	{
+//	  "initial": "pending",
+//	  "states": ["pending", "in_progress", "review", "completed"],
+//	  "transitions": {
+//	    "pending": ["in_progress"],
+//	    "in_progress": ["pending", "review"],
+//	    "review": ["in_progress", "completed"],
+//	    "completed": ["in_progress"]
+//	  }
+//	}
+//
+// El estado "completed" es obligatorio porque lo usan los vencimientos, el progreso
+// de subtareas y las dependencias.
+type Workflow struct {
+	Initial     string              `json:"initial"`
+	States      []string            `json:"states"`
+	Transitions map[string][]string `json:"transitions"`
+}
+
+// DefaultWorkflow devuelve el flujo por defecto: se puede avanzar y retroceder entre
+// pending e in_progress, completar desde ambos, y reabrir un item completado
+// solo pasándolo a in_progress
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Initial: constants.StatePending,
+		States:  slices.Clone(constants.ValidStates),
+		Transitions: map[string][]string{
+			constants.StatePending:    {constants.StateInProgress, constants.StateCompleted},
+			constants.StateInProgress: {constants.StatePending, constants.StateCompleted},
+			constants.StateCompleted:  {constants.StateInProgress},
+		},
+	}
+}
+
+// LoadWorkflow lee el flujo desde el archivo indicado en WORKFLOW_FILE,
+// o devuelve el flujo por defecto si la variable no está definida
+func LoadWorkflow() (Workflow, error) {
+	path := os.Getenv("WORKFLOW_FILE")
+	if path == "" {
+		return DefaultWorkflow(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Workflow{}, fmt.Errorf("no se pudo leer WORKFLOW_FILE: %w", err)
+	}
+
+	var workflow Workflow
+	if err := json.Unmarshal(data, &workflow); err != nil {
+		return Workflow{}, fmt.Errorf("WORKFLOW_FILE no es un JSON válido: %w", err)
+	}
+	if err := workflow.Validate(); err != nil {
+		return Workflow{}, err
+	}
+	return workflow, nil
+}
+
+// Validate verifica que la definición sea coherente
+func (w Workflow) Validate() error {
+	if len(w.States) == 0 {
+		return fmt.Errorf("el flujo debe definir al menos un estado")
+	}
+	for _, state := range w.States {
+		if state == "" || len(state) > 32 {
+			return fmt.Errorf("estado inválido en el flujo: %q", state)
+		}
+	}
+	if !w.IsValidState(constants.StateCompleted) {
+		return fmt.Errorf("el flujo debe incluir el estado %q", constants.StateCompleted)
+	}
+	if !w.IsValidState(w.Initial) {
+		return fmt.Errorf("el estado inicial %q no está entre los estados del flujo", w.Initial)
+	}
+	for from, targets := range w.Transitions {
+		if !w.IsValidState(from) {
+			return fmt.Errorf("transición desde un estado desconocido: %q", from)
+		}
+		for _, to := range targets {
+			if !w.IsValidState(to) {
+				return fmt.Errorf("transición de %q a un estado desconocido: %q", from, to)
+			}
+		}
+	}
+	return nil
+}
+
+// IsValidState verifica si el estado pertenece al flujo
+func (w Workflow) IsValidState(state string) bool {
+	return slices.Contains(w.States, state)
+}
+
+// CanTransition indica si se puede pasar de from a to. Quedarse en el mismo estado
+// siempre está permitido.
+func (w Workflow) CanTransition(from string, to string) bool {
+	if from == to {
+		return w.IsValidState(to)
+	}
+	return slices.Contains(w.Transitions[from], to)
+}
+
+// NextStates devuelve los estados a los que se puede pasar desde from
+func (w Workflow) NextStates(from string) []string {
+	next := w.Transitions[from]
+	if next == nil {
+		return []string{}
+	}
+	return next
+}
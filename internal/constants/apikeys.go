@@ -0,0 +1,49 @@
+package constants
+
+// Scopes que se le pueden otorgar a una API key. admin incluye a todos los demás.
+const (
+	ScopeItemsRead  = "items:read"
+	ScopeItemsWrite = "items:write"
+	ScopeAdmin      = "admin"
+)
+
+// ValidScopes contiene todos los scopes válidos
+var ValidScopes = []string{ScopeItemsRead, ScopeItemsWrite, ScopeAdmin}
+
+// IsValidScope verifica si un scope es válido
+func IsValidScope(scope string) bool {
+	for _, validScope := range ValidScopes {
+		if scope == validScope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyHeader es la cabecera con la que los clientes envían su API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyPrefix antecede a las API keys generadas, para reconocerlas (por ejemplo
+// en los escáneres de secretos)
+const APIKeyPrefix = "tdk_"
+
+// Mensajes de respuesta relacionados con API keys
+const (
+	// Mensajes de éxito
+	APIKeysObtenidas = "API keys obtenidas exitosamente"
+	APIKeyObtenida   = "API key obtenida exitosamente"
+	APIKeyCreada     = "API key creada exitosamente"
+	APIKeyRevocada   = "API key revocada exitosamente"
+	APIKeyRotada     = "API key rotada exitosamente"
+
+	// Mensajes de error
+	IDAPIKeyInvalido      = "ID de API key inválido"
+	APIKeyNoEncontrada    = "API key no encontrada"
+	APIKeyInactiva        = "la API key está revocada"
+	NombreAPIKeyRequerido = "el nombre de la API key es requerido"
+	ScopesRequeridos      = "la API key necesita al menos un scope"
+	ScopeInvalido         = "scope inválido"
+	APIKeyRequerida       = "Se requiere una API key (" + APIKeyHeader + ") o un token de acceso (Authorization: Bearer) válido"
+	ScopeInsuficiente     = "La API key no tiene el scope requerido"
+	ScopeRequerido        = "se requiere el scope %s"
+)
@@ -0,0 +1,22 @@
+package constants
+
+// Valores especiales de los filtros assignee y created_by: me es el usuario de
+// la petición y none (solo en assignee) los items sin responsable
+const (
+	FilterMe   = "me"
+	FilterNone = "none"
+)
+
+// Mensajes de respuesta relacionados con los dueños y responsables de los items
+const (
+	// Mensajes de éxito
+	ItemAsignado    = "Item asignado exitosamente"
+	ItemDesasignado = "Responsable del item quitado exitosamente"
+
+	// Mensajes de error
+	IDUsuarioInvalido       = "ID de usuario inválido"
+	ResponsableNoEncontrado = "Usuario responsable no encontrado"
+	ItemDeOtroUsuario       = "Solo el creador del item, su responsable o un administrador pueden modificarlo"
+	UsuarioRequerido        = "Se requiere el token de acceso de un usuario"
+	FiltroMeSinUsuario      = "%s=me requiere el token de acceso de un usuario"
+)
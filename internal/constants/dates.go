@@ -0,0 +1,8 @@
+package constants
+
+// Mensajes de error relacionados con fechas
+const (
+	FechaInicioInvalida      = "la fecha de inicio (start_at) debe tener formato RFC3339."
+	FechaVencimientoInvalida = "la fecha de vencimiento (due_at) debe tener formato RFC3339."
+	RangoFechasInvalido      = "la fecha de inicio no puede ser posterior a la fecha de vencimiento."
+)
@@ -0,0 +1,15 @@
+package constants
+
+// Mensajes de respuesta relacionados con dependencias entre items
+const (
+	// Mensajes de éxito
+	DependenciaAgregada  = "Dependencia agregada exitosamente"
+	DependenciaEliminada = "Dependencia eliminada exitosamente"
+	BloqueantesObtenidos = "Bloqueantes obtenidos exitosamente"
+
+	// Mensajes de error
+	BloqueanteNoEncontrado  = "Item bloqueante no encontrado"
+	DependenciaNoEncontrada = "Dependencia no encontrada"
+	CicloDependencias       = "La dependencia generaría un ciclo"
+	ItemBloqueado           = "El item tiene bloqueantes abiertos"
+)
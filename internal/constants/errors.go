@@ -0,0 +1,71 @@
+package constants
+
+// ProblemContentType es el tipo de las respuestas de error (RFC 9457)
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix antecede al código del error en el campo type de la respuesta
+const ProblemTypePrefix = "urn:devops-todo:problem:"
+
+// Códigos estables de error de la API de items (y de compartidos); a diferencia
+// de los mensajes, no cambian y los clientes pueden compararlos
+const (
+	ErrCodeInvalidID          = "invalid_id"
+	ErrCodeInvalidBody        = "invalid_body"
+	ErrCodeInvalidFilter      = "invalid_filter"
+	ErrCodeInvalidPagination  = "invalid_pagination"
+	ErrCodeInvalidAsOf        = "invalid_as_of"
+	ErrCodeUnsupportedVersion = "unsupported_version"
+	ErrCodeValidationFailed   = "validation_failed"
+	ErrCodeInvalidState       = "invalid_state"
+	ErrCodeInvalidTransition  = "invalid_transition"
+	ErrCodeNotInitialState    = "not_initial_state"
+	ErrCodeItemNotFound       = "item_not_found"
+	ErrCodeProjectNotFound    = "project_not_found"
+	ErrCodeProjectArchived    = "project_archived"
+	ErrCodeParentNotFound     = "parent_not_found"
+	ErrCodeBlockerNotFound    = "blocker_not_found"
+	ErrCodeDependencyNotFound = "dependency_not_found"
+	ErrCodeItemNotDeleted     = "item_not_deleted"
+	ErrCodeHierarchyCycle     = "hierarchy_cycle"
+	ErrCodeMaxDepth           = "max_depth_exceeded"
+	ErrCodePendingSubtasks    = "pending_subtasks"
+	ErrCodeDependencyCycle    = "dependency_cycle"
+	ErrCodeItemBlocked        = "item_blocked"
+	ErrCodeAssigneeNotFound   = "assignee_not_found"
+	ErrCodeItemForbidden      = "item_forbidden"
+	ErrCodeUserRequired       = "user_required"
+	ErrCodePermissionDenied   = "permission_denied"
+	ErrCodeInsufficientScope  = "insufficient_scope"
+	ErrCodeAuthRequired       = "authentication_required"
+	ErrCodeInvalidToken       = "invalid_token"
+	ErrCodeTenantMismatch     = "tenant_mismatch"
+	ErrCodeTenantNotFound     = "tenant_not_found"
+	ErrCodeRegistrationClosed = "registration_closed"
+	ErrCodeQuotaExceeded      = "quota_exceeded"
+	ErrCodeUserNotFound       = "user_not_found"
+	ErrCodeShareNotFound      = "share_not_found"
+	ErrCodeShareRevoked       = "share_revoked"
+	ErrCodeShareLinkNotFound  = "share_link_not_found"
+	ErrCodeShareReadOnly      = "share_read_only"
+	ErrCodeInternal           = "internal_error"
+)
+
+// Códigos de los errores de validación por campo
+const (
+	FieldRequired         = "required"
+	FieldInvalidState     = "invalid_state"
+	FieldInvalidPriority  = "invalid_priority"
+	FieldInvalidDate      = "invalid_date"
+	FieldInvalidDateRange = "invalid_date_range"
+	FieldInvalidTag       = "invalid_tag"
+	FieldInvalidType      = "invalid_type"
+)
+
+// Mensajes de error
+const (
+	DatosInvalidos        = "Los datos del item no son válidos"
+	TipoInvalido          = "tipo de dato inválido, se esperaba %s"
+	TituloRequerido       = "el título es requerido"
+	ModoEtiquetasInvalido = "modo de etiquetas inválido: %s"
+	OrdenamientoInvalido  = "campo de ordenamiento inválido: %s"
+)
@@ -0,0 +1,16 @@
+package constants
+
+// Mensajes de la API GraphQL (/graphql)
+const (
+	ConsultaRequerida      = "Falta la consulta (query)"
+	ConsultaMuyProfunda    = "La consulta supera la profundidad máxima"
+	ConsultaMuyCompleja    = "La consulta supera la complejidad máxima"
+	SuscripcionRequiereSSE = "Las suscripciones requieren Accept: text/event-stream"
+	MutacionPorGET         = "Las mutaciones solo se aceptan por POST"
+)
+
+// Eventos del stream de una suscripción GraphQL por Server-Sent Events
+const (
+	GraphQLEventNext     = "next"
+	GraphQLEventComplete = "complete"
+)
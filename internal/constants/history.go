@@ -0,0 +1,53 @@
+package constants
+
+// Tipos de eventos del historial de un item
+const (
+	EventCreated  = "created"
+	EventUpdated  = "updated"
+	EventDeleted  = "deleted"
+	EventRestored = "restored"
+)
+
+// Paginación del historial
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Mensajes de respuesta relacionados con el historial
+const (
+	// Mensajes de éxito
+	HistorialObtenido = "Historial del item obtenido exitosamente"
+	ItemRestaurado    = "Item restaurado exitosamente"
+
+	// Mensajes de error
+	ItemNoEliminado    = "El item no está eliminado"
+	PaginacionInvalida = "Parámetros de paginación inválidos"
+	AsOfInvalido       = "Fecha as_of inválida, use formato RFC3339"
+	PaginaInvalida     = "page debe ser un entero mayor a 0"
+	PageSizeInvalido   = "page_size debe ser un entero entre 1 y %d"
+)
+
+// Eventos del flujo del que se derivan los items en el modo event sourcing
+const (
+	StreamItemCreated        = "ItemCreated"
+	StreamItemStateChanged   = "ItemStateChanged"
+	StreamItemRetitled       = "ItemRetitled"
+	StreamItemDetailsChanged = "ItemDetailsChanged"
+	StreamItemTagsChanged    = "ItemTagsChanged"
+	StreamItemMoved          = "ItemMoved"
+	StreamItemReparented     = "ItemReparented"
+	StreamItemAssigned       = "ItemAssigned"
+	StreamItemDeleted        = "ItemDeleted"
+	StreamItemRestored       = "ItemRestored"
+)
+
+// Stream de cambios de items
+const (
+	// StreamReset es el evento que indica que se perdieron eventos desde Last-Event-ID
+	// y el cliente debe volver a cargar los items
+	StreamReset         = "reset"
+	LastEventIDHeader   = "Last-Event-ID"
+	LastEventIDInvalido = "Last-Event-ID inválido"
+	StreamIncompleto    = "Se perdieron eventos desde Last-Event-ID; vuelva a cargar los items"
+)
@@ -8,7 +8,12 @@ const (
 	StatusCreated             = http.StatusCreated             // 201
 	StatusNoContent           = http.StatusNoContent           // 204
 	StatusBadRequest          = http.StatusBadRequest          // 400
+	StatusUnauthorized        = http.StatusUnauthorized        // 401
+	StatusForbidden           = http.StatusForbidden           // 403
 	StatusNotFound            = http.StatusNotFound            // 404
+	StatusMethodNotAllowed    = http.StatusMethodNotAllowed    // 405
+	StatusConflict            = http.StatusConflict            // 409
+	StatusUnprocessableEntity = http.StatusUnprocessableEntity // 422
 	StatusInternalServerError = http.StatusInternalServerError // 500
 )
 
@@ -28,4 +33,5 @@ const (
 	ErrorInterno       = "Error interno del servidor"
 	ErrorBaseDatos     = "Error en la base de datos"
 	CamposRequeridos   = "Faltan campos requeridos"
+	FiltroInvalido     = "Parámetro de filtro inválido"
 )
@@ -0,0 +1,35 @@
+package constants
+
+// Prioridades válidas para un item TODO, de menor a mayor urgencia
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// DefaultPriority es la prioridad asignada cuando no se indica ninguna al crear
+const DefaultPriority = PriorityMedium
+
+// Mensajes de error relacionados con prioridades
+const (
+	PrioridadInvalida = "prioridad inválida."
+)
+
+// ValidPriorities contiene todas las prioridades válidas, ordenadas de menor a mayor
+var ValidPriorities = []string{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// IsValidPriority verifica si una prioridad es válida
+func IsValidPriority(priority string) bool {
+	return PriorityRank(priority) >= 0
+}
+
+// PriorityRank devuelve la posición de la prioridad en ValidPriorities, o -1 si no es válida
+func PriorityRank(priority string) int {
+	for i, validPriority := range ValidPriorities {
+		if priority == validPriority {
+			return i
+		}
+	}
+	return -1
+}
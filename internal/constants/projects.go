@@ -0,0 +1,21 @@
+package constants
+
+// Mensajes de respuesta relacionados con proyectos
+const (
+	// Mensajes de éxito
+	ProyectosObtenidos   = "Proyectos obtenidos exitosamente"
+	ProyectoObtenido     = "Proyecto obtenido exitosamente"
+	ProyectoCreado       = "Proyecto creado exitosamente"
+	ProyectoActualizado  = "Proyecto actualizado exitosamente"
+	ProyectoEliminado    = "Proyecto eliminado exitosamente"
+	ProyectoArchivado    = "Proyecto archivado exitosamente"
+	ProyectoDesarchivado = "Proyecto desarchivado exitosamente"
+	ItemMovido           = "Item movido exitosamente"
+
+	// Mensajes de error
+	IDProyectoInvalido      = "ID de proyecto inválido"
+	ProyectoNoEncontrado    = "Proyecto no encontrado"
+	ProyectoArchivadoErr    = "El proyecto está archivado y no admite nuevos items"
+	ProyectoConItems        = "El proyecto tiene items; muévalos o archive el proyecto"
+	NombreProyectoRequerido = "el nombre del proyecto es requerido"
+)
@@ -0,0 +1,48 @@
+package constants
+
+// Roles de los usuarios, globales o en un proyecto. viewer solo lee, member además
+// crea y modifica, y admin además elimina y administra.
+const (
+	RoleViewer = "viewer"
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+)
+
+// DefaultRole es el rol de los usuarios nuevos
+const DefaultRole = RoleMember
+
+// ValidRoles contiene los roles válidos, de menor a mayor
+var ValidRoles = []string{RoleViewer, RoleMember, RoleAdmin}
+
+// IsValidRole verifica si un rol es válido
+func IsValidRole(role string) bool {
+	for _, validRole := range ValidRoles {
+		if role == validRole {
+			return true
+		}
+	}
+	return false
+}
+
+// Permisos que otorgan los roles, ver policy
+const (
+	PermissionRead   = "read"
+	PermissionWrite  = "write"
+	PermissionDelete = "delete"
+	PermissionManage = "manage"
+)
+
+// Mensajes de respuesta relacionados con roles
+const (
+	// Mensajes de éxito
+	RolActualizado    = "Rol actualizado exitosamente"
+	MiembrosObtenidos = "Miembros del proyecto obtenidos exitosamente"
+	MiembroGuardado   = "Miembro del proyecto guardado exitosamente"
+	MiembroEliminado  = "Miembro del proyecto eliminado exitosamente"
+
+	// Mensajes de error
+	RolInvalido         = "rol inválido"
+	PermisoInsuficiente = "Tu rol no tiene el permiso requerido"
+	PermisoRequerido    = "se requiere el permiso %s"
+	MiembroNoEncontrado = "El usuario no es miembro del proyecto"
+)
@@ -0,0 +1,47 @@
+package constants
+
+// Permisos de un compartido: read solo permite ver y edit además modificar los
+// items compartidos (nunca eliminarlos)
+const (
+	SharePermissionRead = "read"
+	SharePermissionEdit = "edit"
+)
+
+// ValidSharePermissions contiene los permisos válidos, de menor a mayor
+var ValidSharePermissions = []string{SharePermissionRead, SharePermissionEdit}
+
+// IsValidSharePermission verifica si un permiso de compartido es válido
+func IsValidSharePermission(permission string) bool {
+	for _, validPermission := range ValidSharePermissions {
+		if permission == validPermission {
+			return true
+		}
+	}
+	return false
+}
+
+// ShareTokenPrefix antecede a los tokens de los enlaces compartidos, para
+// reconocerlos (por ejemplo en los escáneres de secretos)
+const ShareTokenPrefix = "tds_"
+
+// Mensajes de respuesta relacionados con compartidos
+const (
+	// Mensajes de éxito
+	CompartidosObtenidos = "Compartidos obtenidos exitosamente"
+	CompartidoObtenido   = "Contenido compartido obtenido exitosamente"
+	CompartidoCreado     = "Compartido creado exitosamente"
+	CompartidoRevocado   = "Compartido revocado exitosamente"
+
+	// Mensajes de error
+	IDCompartidoInvalido          = "ID de compartido inválido"
+	CompartidoNoEncontrado        = "Compartido no encontrado"
+	EnlaceCompartidoNoEncontrado  = "El enlace compartido no existe, venció o fue revocado"
+	CompartidoYaRevocado          = "el compartido ya está revocado"
+	PermisoCompartidoInvalido     = "permiso de compartido inválido"
+	DestinoCompartidoRequerido    = "indique item_id o project_id (solo uno)"
+	LecturaCompartidaConUsuario   = "los usuarios del tenant ya pueden leer todos los items: compártalos con permiso edit"
+	VencimientoCompartidoInvalido = "expires_at debe ser una fecha RFC3339 futura"
+	CompartidoSoloLectura         = "El enlace compartido es de solo lectura"
+	FiltroCompartidosRequerido    = "indique item_id o project_id"
+	DatosCompartidoInvalidos      = "Los datos del compartido no son válidos"
+)
@@ -7,9 +7,13 @@ const (
 	StateCompleted  = "completed"
 )
 
-// Mensajes de error relacionados con estados
+// Mensajes relacionados con estados
 const (
-	EstadoInvalido = "estado inválido."
+	EstadoInvalido     = "estado inválido."
+	EstadoRequerido    = "el estado es requerido"
+	TransicionInvalida = "Transición de estado no permitida"
+	ItemTransicionado  = "Estado del item actualizado exitosamente"
+	EstadoNoInicial    = "Los items se crean en el estado inicial del flujo"
 )
 
 // ValidStates contiene todos los estados válidos
@@ -0,0 +1,15 @@
+package constants
+
+// Mensajes de respuesta relacionados con subtareas
+const (
+	// Mensajes de éxito
+	SubtareasObtenidas = "Subtareas obtenidas exitosamente"
+	ArbolObtenido      = "Árbol de subtareas obtenido exitosamente"
+	PadreActualizado   = "Item padre actualizado exitosamente"
+
+	// Mensajes de error
+	PadreNoEncontrado   = "Item padre no encontrado"
+	CicloJerarquia      = "El item padre no puede ser el mismo item ni una de sus subtareas"
+	ProfundidadMaxima   = "Se superó la profundidad máxima de subtareas"
+	SubtareasPendientes = "No se puede completar un item con subtareas pendientes"
+)
@@ -0,0 +1,29 @@
+package constants
+
+// Límites para los nombres de etiquetas
+const (
+	MaxTagLength = 64
+)
+
+// Modos de coincidencia para el filtro /items?tag=a,b
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+// Mensajes de respuesta relacionados con etiquetas
+const (
+	// Mensajes de éxito
+	EtiquetasObtenidas  = "Etiquetas obtenidas exitosamente"
+	EtiquetaCreada      = "Etiqueta creada exitosamente"
+	EtiquetaRenombrada  = "Etiqueta renombrada exitosamente"
+	EtiquetasFusionadas = "Etiquetas fusionadas exitosamente"
+	EtiquetaEliminada   = "Etiqueta eliminada exitosamente"
+
+	// Mensajes de error
+	EtiquetaInvalida     = "etiqueta inválida: debe tener entre 1 y 64 caracteres y no contener comas."
+	EtiquetaNoEncontrada = "Etiqueta no encontrada"
+	EtiquetaDuplicada    = "Ya existe una etiqueta con ese nombre"
+	IDEtiquetaInvalido   = "ID de etiqueta inválido"
+	FusionMismaEtiqueta  = "No se puede fusionar una etiqueta consigo misma"
+)
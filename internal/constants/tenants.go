@@ -0,0 +1,23 @@
+package constants
+
+// DefaultTenantID es el tenant de los datos anteriores a la multi-tenencia y de
+// las peticiones que no indican ninguno
+const DefaultTenantID = "1"
+
+// TenantHeader es la cabecera con el slug del tenant de la petición; también
+// se puede indicar con el subdominio (ver config.TenantConfig)
+const TenantHeader = "X-Tenant"
+
+// Mensajes de respuesta relacionados con tenants
+const (
+	// Mensajes de éxito
+	TenantObtenido = "Tenant obtenido exitosamente"
+
+	// Mensajes de error
+	TenantNoEncontrado     = "El tenant no existe"
+	TenantNoCoincide       = "Las credenciales pertenecen a otro tenant"
+	RegistroNoPermitido    = "El tenant no admite el registro de usuarios"
+	TenantOIDCDesconocido  = "El tenant del token no existe"
+	CuotaItemsExcedida     = "El tenant alcanzó su cuota de items"
+	CuotaProyectosExcedida = "El tenant alcanzó su cuota de proyectos"
+)
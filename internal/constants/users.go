@@ -0,0 +1,34 @@
+package constants
+
+// MinPasswordLength es el largo mínimo de la contraseña de un usuario
+const MinPasswordLength = 8
+
+// UserScopes son los scopes de un usuario autenticado con un token de acceso
+var UserScopes = []string{ScopeItemsRead, ScopeItemsWrite}
+
+// TokenTypeBearer es el tipo de los tokens de acceso (cabecera Authorization: Bearer)
+const TokenTypeBearer = "Bearer"
+
+// Mensajes de respuesta relacionados con usuarios y sesiones
+const (
+	// Mensajes de éxito
+	UsuarioRegistrado = "Usuario registrado exitosamente"
+	UsuarioObtenido   = "Usuario obtenido exitosamente"
+	SesionIniciada    = "Sesión iniciada exitosamente"
+	TokenRenovado     = "Token renovado exitosamente"
+	SesionCerrada     = "Sesión cerrada exitosamente"
+
+	// Mensajes de error
+	EmailInvalido            = "email inválido"
+	EmailDuplicado           = "ya existe un usuario con ese email"
+	ContrasenaCorta          = "la contraseña debe tener al menos 8 caracteres"
+	ContrasenaLarga          = "la contraseña no puede tener más de 72 bytes"
+	CredencialesInvalidas    = "Email o contraseña incorrectos"
+	RefreshTokenInvalido     = "Refresh token inválido, vencido o revocado"
+	RefreshTokenReutilizado  = "refresh token ya usado"
+	TokenAccesoInvalido      = "Token de acceso inválido, vencido o revocado"
+	UsuarioNoEncontrado      = "Usuario no encontrado"
+	SesionDeUsuarioRequerida = "Se requiere un token de acceso de usuario"
+	TokenOIDCSinEmail        = "El token del proveedor de identidad no tiene email"
+	IdentidadOIDCEnConflicto = "El email del token ya pertenece a otro usuario y el proveedor no lo verificó"
+)
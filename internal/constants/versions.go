@@ -0,0 +1,14 @@
+package constants
+
+// Versiones de la API REST. Las rutas sin prefijo usan la versión de la cabecera
+// API-Version y, si no se envía, la 1.
+const (
+	APIVersion1      = "1"
+	APIVersion2      = "2"
+	APIVersionHeader = "API-Version"
+)
+
+// Mensajes de error relacionados con las versiones de la API
+const (
+	VersionNoSoportada = "Versión de API no soportada"
+)
@@ -0,0 +1,69 @@
+package constants
+
+// Eventos a los que se puede suscribir un webhook
+const (
+	WebhookItemCreated   = "item.created"
+	WebhookItemUpdated   = "item.updated"
+	WebhookItemCompleted = "item.completed"
+	WebhookItemDeleted   = "item.deleted"
+	WebhookItemRestored  = "item.restored"
+)
+
+// ValidWebhookEvents contiene todos los eventos de webhook válidos
+var ValidWebhookEvents = []string{WebhookItemCreated, WebhookItemUpdated, WebhookItemCompleted, WebhookItemDeleted, WebhookItemRestored}
+
+// IsValidWebhookEvent verifica si un evento de webhook es válido
+func IsValidWebhookEvent(event string) bool {
+	for _, validEvent := range ValidWebhookEvents {
+		if event == validEvent {
+			return true
+		}
+	}
+	return false
+}
+
+// Estados de una entrega de webhook
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	// DeliveryDead indica que se agotaron los reintentos (dead-letter)
+	DeliveryDead = "dead"
+)
+
+// Cabeceras de las peticiones de webhook
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Mensajes de respuesta relacionados con webhooks
+const (
+	// Mensajes de éxito
+	WebhooksObtenidos   = "Webhooks obtenidos exitosamente"
+	WebhookObtenido     = "Webhook obtenido exitosamente"
+	WebhookCreado       = "Webhook creado exitosamente"
+	WebhookActualizado  = "Webhook actualizado exitosamente"
+	WebhookEliminado    = "Webhook eliminado exitosamente"
+	EntregasObtenidas   = "Entregas obtenidas exitosamente"
+	EntregaReprogramada = "Entrega reprogramada exitosamente"
+
+	// Mensajes de error
+	IDWebhookInvalido     = "ID de webhook inválido"
+	WebhookNoEncontrado   = "Webhook no encontrado"
+	IDEntregaInvalido     = "ID de entrega inválido"
+	EntregaNoEncontrada   = "Entrega no encontrada"
+	URLWebhookInvalida    = "la URL del webhook debe ser http o https"
+	URLWebhookInterna     = "la URL del webhook no puede apuntar a la red interna"
+	EventoWebhookInvalido = "evento de webhook inválido"
+	EstadoEntregaInvalido = "estado de entrega inválido"
+)
+
+// Errores de las entregas que se guardan en last_error; el detalle de la
+// conexión solo va al log
+const (
+	EntregaRespuestaFallida = "respuesta %d del receptor"
+	EntregaDestinoInterno   = "el receptor está en una dirección de la red interna"
+	EntregaSinRespuesta     = "el receptor no respondió a tiempo"
+	EntregaSinConexion      = "no se pudo conectar con el receptor"
+)
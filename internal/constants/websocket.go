@@ -0,0 +1,45 @@
+package constants
+
+// WSProtocol es el subprotocolo del WebSocket. Los navegadores no pueden enviar
+// cabeceras al conectarse, así que envían las credenciales como subprotocolos
+// junto a él: new WebSocket(url, ["todo.v1", "bearer.<token>"]) o
+// "apikey.<key>". El servidor solo elige WSProtocol, nunca las credenciales.
+const (
+	WSProtocol             = "todo.v1"
+	WSBearerProtocolPrefix = "bearer."
+	WSAPIKeyProtocolPrefix = "apikey."
+)
+
+// Tipos de mensaje que envía el cliente por el WebSocket (GET /ws)
+const (
+	// WSSubscribe reemplaza el conjunto de items del que el cliente recibe cambios
+	WSSubscribe   = "subscribe"
+	WSUnsubscribe = "unsubscribe"
+	// WSView y WSLeave indican que el cliente empezó o dejó de ver un item
+	WSView  = "view"
+	WSLeave = "leave"
+	WSPing  = "ping"
+
+	// Mutaciones, que pasan por las mismas validaciones que la API REST
+	WSCreate     = "create"
+	WSUpdate     = "update"
+	WSDelete     = "delete"
+	WSTransition = "transition"
+)
+
+// Tipos de mensaje que envía el servidor por el WebSocket
+const (
+	WSWelcome  = "welcome"
+	WSEvent    = "event"
+	WSPresence = "presence"
+	WSResult   = "result"
+	WSError    = "error"
+	WSPong     = "pong"
+)
+
+// Mensajes del WebSocket
+const (
+	WSMensajeInvalido = "Mensaje inválido"
+	WSTipoDesconocido = "Tipo de mensaje desconocido"
+	WSClienteLento    = "El cliente no consume los mensajes a tiempo"
+)
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// clientBuffer es cuántos eventos puede tener pendientes un cliente antes de
+// considerarlo lento y desconectarlo
+const clientBuffer = 64
+
+// Hub recibe los eventos del PubSub, guarda los últimos en un buffer para
+// reenviarlos a quien se reconecta con Last-Event-ID y los reparte a los
+// clientes del stream conectados a esta instancia
+type Hub struct {
+	pubsub     PubSub
+	bufferSize int
+
+	mu      sync.Mutex
+	buffer  []Event
+	clients map[*Subscription]struct{}
+}
+
+// Subscription es un cliente conectado al stream
+type Subscription struct {
+	// Events se cierra si el cliente no consume a tiempo o al llamar a Close
+	Events <-chan Event
+	events chan Event
+	hub    *Hub
+	filter func(Event) bool
+}
+
+func NewHub(pubsub PubSub, bufferSize int) *Hub {
+	return &Hub{
+		pubsub:     pubsub,
+		bufferSize: bufferSize,
+		clients:    make(map[*Subscription]struct{}),
+	}
+}
+
+// Run recibe los eventos del PubSub hasta que se cancele ctx
+func (h *Hub) Run(ctx context.Context) {
+	if err := h.pubsub.Subscribe(ctx, h.dispatch); err != nil {
+		log.Printf("WARN: el stream de items dejó de recibir eventos: %v", err)
+	}
+}
+
+// Publish publica un evento para todas las instancias
+func (h *Hub) Publish(ctx context.Context, event Event) error {
+	return h.pubsub.Publish(ctx, event)
+}
+
+func (h *Hub) dispatch(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buffer = append(h.buffer, event)
+	if len(h.buffer) > h.bufferSize {
+		h.buffer = h.buffer[len(h.buffer)-h.bufferSize:]
+	}
+
+	for sub := range h.clients {
+		if !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribe conecta un cliente que recibe los eventos que cumplen filter.
+// Con lastEventID distinto de 0 devuelve además los eventos posteriores que
+// siguen en el buffer; complete es false si el buffer ya no tiene todos los
+// eventos desde lastEventID y el cliente debería volver a cargar los items.
+func (h *Hub) Subscribe(lastEventID uint64, filter func(Event) bool) (sub *Subscription, replay []Event, complete bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	complete = true
+	if lastEventID != 0 {
+		if len(h.buffer) > 0 && h.buffer[0].ID > lastEventID+1 {
+			complete = false
+		}
+		for _, event := range h.buffer {
+			if event.ID > lastEventID && filter(event) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	events := make(chan Event, clientBuffer)
+	sub = &Subscription{Events: events, events: events, hub: h, filter: filter}
+	h.clients[sub] = struct{}{}
+	return sub, replay, complete
+}
+
+// Close desconecta al cliente
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// remove quita un cliente y cierra su canal. Requiere h.mu tomado.
+func (h *Hub) remove(sub *Subscription) {
+	if _, ok := h.clients[sub]; ok {
+		delete(h.clients, sub)
+		close(sub.events)
+	}
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// cleanupEvery es cada cuántas consultas se borran los eventos viejos
+const cleanupEvery = 100
+
+// MySQLPubSub comparte los eventos entre instancias a través de la tabla
+// item_change_feed: cada instancia publica insertando y consulta periódicamente
+// las filas nuevas. El ID del evento es el de la fila.
+type MySQLPubSub struct {
+	db           *sql.DB
+	pollInterval time.Duration
+	// retention es cuánto se conservan los eventos en la tabla
+	retention time.Duration
+}
+
+func NewMySQLPubSub(db *sql.DB, pollInterval time.Duration, retention time.Duration) *MySQLPubSub {
+	return &MySQLPubSub{db: db, pollInterval: pollInterval, retention: retention}
+}
+
+func (p *MySQLPubSub) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = p.db.ExecContext(ctx, "INSERT INTO item_change_feed (tenant_id, payload) VALUES (?, ?)", event.TenantID, payload)
+	return err
+}
+
+func (p *MySQLPubSub) Subscribe(ctx context.Context, handler func(Event)) error {
+	// Solo interesan los eventos publicados desde que esta instancia se suscribe
+	var lastID uint64
+	if err := p.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM item_change_feed").Scan(&lastID); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for polls := 1; ; polls++ {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		var err error
+		if lastID, err = p.poll(ctx, lastID, handler); err != nil {
+			return err
+		}
+		if polls%cleanupEvery == 0 {
+			if _, err := p.db.ExecContext(ctx, "DELETE FROM item_change_feed WHERE created_at < ?", time.Now().Add(-p.retention).UTC()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// poll entrega los eventos posteriores a lastID y devuelve el último entregado
+func (p *MySQLPubSub) poll(ctx context.Context, lastID uint64, handler func(Event)) (uint64, error) {
+	rows, err := p.db.QueryContext(ctx, "SELECT id, tenant_id, payload FROM item_change_feed WHERE id > ? ORDER BY id LIMIT 500", lastID)
+	if err != nil {
+		return lastID, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint64
+		var tenantID string
+		var payload []byte
+		if err := rows.Scan(&id, &tenantID, &payload); err != nil {
+			return lastID, err
+		}
+		var event Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return lastID, err
+		}
+		event.ID = id
+		event.TenantID = tenantID
+		handler(event)
+		lastID = id
+	}
+	return lastID, rows.Err()
+}
@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+)
+
+// Publisher publica los eventos del stream (lo implementa Hub)
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// PublishingRepository envuelve un IRepository y publica un evento en el stream
+// después de cada cambio exitoso. Un error al publicar solo se registra, ya que
+// el cambio ya quedó guardado.
+type PublishingRepository struct {
+	repository.IRepository
+	publisher Publisher
+	tenantID  string
+}
+
+func NewPublishingRepository(repo repository.IRepository, publisher Publisher) *PublishingRepository {
+	return &PublishingRepository{IRepository: repo, publisher: publisher, tenantID: constants.DefaultTenantID}
+}
+
+func (r *PublishingRepository) WithMeta(meta models.RequestMeta) repository.IRepository {
+	return &PublishingRepository{IRepository: r.IRepository.WithMeta(meta), publisher: r.publisher, tenantID: meta.Tenant()}
+}
+
+func (r *PublishingRepository) publish(eventType string, item models.TodoItem) {
+	event := Event{Type: eventType, ItemID: item.ID, TenantID: r.tenantID, Item: item}
+	if err := r.publisher.Publish(context.Background(), event); err != nil {
+		log.Printf("WARN: no se pudo publicar %s del item %s: %v", eventType, item.ID, err)
+	}
+}
+
+// publishCurrent publica el estado actual del item tras una actualización
+func (r *PublishingRepository) publishCurrent(id int) {
+	item, err := r.IRepository.Get(id)
+	if err != nil {
+		log.Printf("WARN: no se pudo leer el item %d para publicarlo: %v", id, err)
+		return
+	}
+	r.publish(constants.WebhookItemUpdated, item)
+}
+
+func (r *PublishingRepository) Create(item models.TodoItem) (models.TodoItem, error) {
+	created, err := r.IRepository.Create(item)
+	if err == nil {
+		r.publish(constants.WebhookItemCreated, created)
+	}
+	return created, err
+}
+
+func (r *PublishingRepository) Update(item models.TodoItem) error {
+	err := r.IRepository.Update(item)
+	if err == nil {
+		id, _ := strconv.Atoi(item.ID)
+		r.publishCurrent(id)
+	}
+	return err
+}
+
+func (r *PublishingRepository) Delete(id string) error {
+	// El evento lleva el último estado conocido, para poder filtrarlo por estado o proyecto
+	numericID, _ := strconv.Atoi(id)
+	before, getErr := r.IRepository.Get(numericID)
+
+	err := r.IRepository.Delete(id)
+	if err == nil {
+		if getErr != nil {
+			before = models.TodoItem{ID: id}
+		}
+		r.publish(constants.WebhookItemDeleted, before)
+	}
+	return err
+}
+
+func (r *PublishingRepository) Restore(id int) error {
+	err := r.IRepository.Restore(id)
+	if err == nil {
+		if item, getErr := r.IRepository.Get(id); getErr == nil {
+			r.publish(constants.WebhookItemRestored, item)
+		}
+	}
+	return err
+}
+
+func (r *PublishingRepository) MoveItem(id int, projectID *string) error {
+	err := r.IRepository.MoveItem(id, projectID)
+	if err == nil {
+		r.publishCurrent(id)
+	}
+	return err
+}
+
+func (r *PublishingRepository) SetParent(id int, parentID *string, maxDepth int) error {
+	err := r.IRepository.SetParent(id, parentID, maxDepth)
+	if err == nil {
+		r.publishCurrent(id)
+	}
+	return err
+}
+
+func (r *PublishingRepository) AssignItem(id int, assigneeID *string) error {
+	err := r.IRepository.AssignItem(id, assigneeID)
+	if err == nil {
+		r.publishCurrent(id)
+	}
+	return err
+}
+
+func (r *PublishingRepository) AddDependency(id int, blockerID int) error {
+	err := r.IRepository.AddDependency(id, blockerID)
+	if err == nil {
+		r.publishCurrent(id)
+	}
+	return err
+}
+
+func (r *PublishingRepository) RemoveDependency(id int, blockerID int) error {
+	err := r.IRepository.RemoveDependency(id, blockerID)
+	if err == nil {
+		r.publishCurrent(id)
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+// Event es un cambio de un item que se envía por el stream. Item es el estado
+// del item después del cambio (o el último conocido, si se eliminó).
+// TenantID es el tenant del item: cada suscriptor solo recibe los de su tenant.
+type Event struct {
+	ID       uint64          `json:"id"`
+	Type     string          `json:"type"`
+	ItemID   string          `json:"item_id"`
+	TenantID string          `json:"-"`
+	Item     models.TodoItem `json:"item"`
+}
+
+// PubSub transporta los eventos entre las instancias de la API. Publish asigna
+// el ID del evento, que debe crecer de forma monótona entre todas las
+// instancias para que Last-Event-ID sirva al reconectarse a cualquiera.
+type PubSub interface {
+	Publish(ctx context.Context, event Event) error
+	// Subscribe llama a handler con cada evento publicado, en orden, hasta que se cancele ctx
+	Subscribe(ctx context.Context, handler func(Event)) error
+}
+
+// MemoryPubSub es el PubSub en memoria, válido para una sola instancia
+type MemoryPubSub struct {
+	mu       sync.Mutex
+	lastID   uint64
+	handlers map[int]func(Event)
+	nextKey  int
+}
+
+func NewMemoryPubSub() *MemoryPubSub {
+	return &MemoryPubSub{handlers: make(map[int]func(Event))}
+}
+
+func (p *MemoryPubSub) Publish(ctx context.Context, event Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lastID++
+	event.ID = p.lastID
+	for _, handler := range p.handlers {
+		handler(event)
+	}
+	return nil
+}
+
+func (p *MemoryPubSub) Subscribe(ctx context.Context, handler func(Event)) error {
+	p.mu.Lock()
+	key := p.nextKey
+	p.nextKey++
+	p.handlers[key] = handler
+	p.mu.Unlock()
+
+	<-ctx.Done()
+
+	p.mu.Lock()
+	delete(p.handlers, key)
+	p.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,145 @@
+package gql
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Handler atiende /graphql: consultas por GET o POST, mutaciones solo por POST
+// y suscripciones como stream de Server-Sent Events
+type Handler struct {
+	server    *Server
+	heartbeat time.Duration
+}
+
+func NewHandler(server *Server, heartbeat time.Duration) *Handler {
+	return &Handler{
+		server:    server,
+		heartbeat: heartbeat,
+	}
+}
+
+// requestMeta arma los datos de la petición que registra el historial
+func requestMeta(c *gin.Context) models.RequestMeta {
+	actor := c.GetString(middleware.ActorKey)
+	if actor == "" {
+		actor = c.GetHeader(middleware.ActorHeader)
+	}
+	meta := models.RequestMeta{
+		Actor:     actor,
+		RequestID: c.GetString(middleware.RequestIDKey),
+		TenantID:  middleware.TenantID(c),
+	}
+	middleware.SetUserAccess(c, &meta)
+	return meta
+}
+
+// respondError responde con el formato de errores de GraphQL
+func respondError(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{
+		"errors": []gin.H{{"message": message}},
+	})
+}
+
+// parseRequest lee la operación del cuerpo JSON (POST) o de los query params
+// query, operationName y variables (GET)
+func parseRequest(c *gin.Context) (Request, error) {
+	var req Request
+	if c.Request.Method != "GET" {
+		err := c.ShouldBindJSON(&req)
+		return req, err
+	}
+	req.Query = c.Query("query")
+	req.OperationName = c.Query("operationName")
+	if value := c.Query("variables"); value != "" {
+		if err := json.Unmarshal([]byte(value), &req.Variables); err != nil {
+			return req, err
+		}
+	}
+	return req, nil
+}
+
+func (h *Handler) Serve(c *gin.Context) {
+	req, err := parseRequest(c)
+	if err != nil {
+		respondError(c, constants.StatusBadRequest, constants.CuerpoInvalido+": "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		respondError(c, constants.StatusBadRequest, constants.ConsultaRequerida)
+		return
+	}
+
+	operation, err := h.server.Check(req)
+	if err != nil {
+		respondError(c, constants.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch operation {
+	case ast.Mutation:
+		if c.Request.Method == "GET" {
+			respondError(c, constants.StatusMethodNotAllowed, constants.MutacionPorGET)
+			return
+		}
+		// La ruta exige items:read; las mutaciones necesitan además items:write
+		if scopes, ok := middleware.Scopes(c); ok && !models.HasScope(scopes, constants.ScopeItemsWrite) {
+			respondError(c, constants.StatusForbidden, constants.ScopeInsuficiente+": "+constants.ScopeItemsWrite)
+			return
+		}
+	case ast.Subscription:
+		if !strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			respondError(c, constants.StatusBadRequest, constants.SuscripcionRequiereSSE)
+			return
+		}
+		h.subscribe(c, req)
+		return
+	}
+
+	c.JSON(constants.StatusOK, h.server.Exec(c.Request.Context(), requestMeta(c), req))
+}
+
+// subscribe envía cada resultado de la suscripción como evento "next" y, al
+// terminar (por ejemplo si el cliente no consume a tiempo), un evento "complete"
+func (h *Handler) subscribe(c *gin.Context, req Request) {
+	responses, err := h.server.Subscribe(c.Request.Context(), requestMeta(c), req)
+	if err != nil {
+		respondError(c, constants.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(constants.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case response, ok := <-responses:
+			if !ok {
+				c.Render(-1, sse.Event{Event: constants.GraphQLEventComplete, Data: ""})
+				c.Writer.Flush()
+				return
+			}
+			c.Render(-1, sse.Event{Event: constants.GraphQLEventNext, Data: response})
+		case <-heartbeat.C:
+			c.Writer.WriteString(": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
@@ -0,0 +1,135 @@
+package gql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// listEstimate es cuántos elementos se estima que devuelve un campo de lista sin paginación
+const listEstimate = 10
+
+// listFields son los campos de lista sin paginación del esquema
+var listFields = map[string]bool{
+	"subtasks":  true,
+	"blockedBy": true,
+	"projects":  true,
+	"tags":      true,
+}
+
+// analysis es el resultado de analizar una operación antes de ejecutarla
+type analysis struct {
+	Operation  ast.Operation
+	Depth      int
+	Complexity int
+}
+
+// analyze calcula el tipo, la profundidad y la complejidad de la operación. Si
+// la consulta no se puede interpretar devuelve ok false y se deja que la
+// ejecución informe el error de sintaxis.
+func analyze(query, operationName string, variables map[string]any) (result analysis, ok bool) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return result, false
+	}
+
+	var operation *ast.OperationDefinition
+	if operationName != "" {
+		operation = doc.Operations.ForName(operationName)
+	} else if len(doc.Operations) == 1 {
+		operation = doc.Operations[0]
+	}
+	if operation == nil {
+		return result, false
+	}
+
+	walker := costWalker{doc: doc, variables: variables, visiting: map[string]bool{}}
+	result.Operation = operation.Operation
+	result.Complexity, result.Depth = walker.walk(operation.SelectionSet, 1, 1)
+	return result, true
+}
+
+// check devuelve un error si la operación supera los límites configurados
+func (a analysis) check(maxDepth, maxComplexity int) error {
+	if a.Depth > maxDepth {
+		return fmt.Errorf("%s: profundidad %d, máximo %d", constants.ConsultaMuyProfunda, a.Depth, maxDepth)
+	}
+	if a.Complexity > maxComplexity {
+		return fmt.Errorf("%s: complejidad %d, máximo %d", constants.ConsultaMuyCompleja, a.Complexity, maxComplexity)
+	}
+	return nil
+}
+
+type costWalker struct {
+	doc       *ast.QueryDocument
+	variables map[string]any
+	// visiting evita recorrer en ciclo los fragmentos que se incluyen a sí mismos
+	visiting map[string]bool
+}
+
+// walk devuelve el costo y la profundidad máxima de un conjunto de campos.
+// pageSize es el tamaño de página del listado que contiene los campos, que
+// multiplica el costo de nodes. Los campos de introspección (__schema, __type,
+// __typename) no cuentan.
+func (w *costWalker) walk(selections ast.SelectionSet, depth int, pageSize int) (cost int, maxDepth int) {
+	for _, selection := range selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name, "__") {
+				continue
+			}
+			childCost, childDepth := w.walk(selection.SelectionSet, depth+1, w.pageSize(selection))
+			cost += 1 + w.multiplier(selection, pageSize)*childCost
+			maxDepth = max(maxDepth, depth, childDepth)
+		case *ast.InlineFragment:
+			childCost, childDepth := w.walk(selection.SelectionSet, depth, pageSize)
+			cost += childCost
+			maxDepth = max(maxDepth, childDepth)
+		case *ast.FragmentSpread:
+			fragment := w.doc.Fragments.ForName(selection.Name)
+			if fragment == nil || w.visiting[selection.Name] {
+				continue
+			}
+			w.visiting[selection.Name] = true
+			childCost, childDepth := w.walk(fragment.SelectionSet, depth, pageSize)
+			w.visiting[selection.Name] = false
+			cost += childCost
+			maxDepth = max(maxDepth, childDepth)
+		}
+	}
+	return cost, maxDepth
+}
+
+// pageSize es el tamaño de página pedido en un listado paginado (items), acotado
+// al máximo permitido; 1 para los demás campos
+func (w *costWalker) pageSize(field *ast.Field) int {
+	if field.Name != "items" {
+		return 1
+	}
+	pageSize := constants.DefaultPageSize
+	if argument := field.Arguments.ForName("pageSize"); argument != nil {
+		if value, err := argument.Value.Value(w.variables); err == nil {
+			switch value := value.(type) {
+			case int64:
+				pageSize = int(value)
+			case float64:
+				pageSize = int(value)
+			}
+		}
+	}
+	return min(max(pageSize, 1), constants.MaxPageSize)
+}
+
+// multiplier es la cantidad de elementos que se espera que devuelva el campo
+func (w *costWalker) multiplier(field *ast.Field, pageSize int) int {
+	if field.Name == "nodes" {
+		return pageSize
+	}
+	if listFields[field.Name] {
+		return listEstimate
+	}
+	return 1
+}
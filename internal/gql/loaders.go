@@ -0,0 +1,134 @@
+package gql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/graph-gophers/dataloader"
+)
+
+// loaders agrupa las cargas de una petición: los campos que piden items,
+// subtareas o proyectos de varios items se resuelven con una consulta por tipo
+// en lugar de una por item (N+1)
+type loaders struct {
+	items    *dataloader.Loader
+	subtasks *dataloader.Loader
+	projects *dataloader.Loader
+}
+
+type loadersKey struct{}
+
+// newLoaders crea los loaders de una petición. Las suscripciones, que viven
+// mucho tiempo, no guardan los resultados para no devolver datos viejos.
+func newLoaders(repo repository.IRepository, projects repository.IProjectRepository, cache bool) *loaders {
+	var opts []dataloader.Option
+	if !cache {
+		opts = append(opts, dataloader.WithCache(&dataloader.NoCache{}))
+	}
+	return &loaders{
+		items:    dataloader.NewBatchedLoader(itemsBatch(repo), opts...),
+		subtasks: dataloader.NewBatchedLoader(subtasksBatch(repo), opts...),
+		projects: dataloader.NewBatchedLoader(projectsBatch(projects), opts...),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// failed devuelve el mismo error para todas las claves del lote
+func failed(keys dataloader.Keys, err error) []*dataloader.Result {
+	results := make([]*dataloader.Result, len(keys))
+	for i := range keys {
+		results[i] = &dataloader.Result{Error: err}
+	}
+	return results
+}
+
+// itemsBatch carga items por ID; los que no existen resultan en nil
+func itemsBatch(repo repository.IRepository) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		items, err := repo.GetAll(models.ItemFilter{IDs: keys.Keys()})
+		if err != nil {
+			return failed(keys, err)
+		}
+		byID := make(map[string]models.TodoItem, len(items))
+		for _, item := range items {
+			byID[item.ID] = item
+		}
+
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			result := &dataloader.Result{}
+			if item, ok := byID[key.String()]; ok {
+				result.Data = item
+			}
+			results[i] = result
+		}
+		return results
+	}
+}
+
+// subtasksBatch carga las subtareas directas de varios items
+func subtasksBatch(repo repository.IRepository) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		items, err := repo.GetAll(models.ItemFilter{ParentIDs: keys.Keys()})
+		if err != nil {
+			return failed(keys, err)
+		}
+		byParent := make(map[string][]models.TodoItem, len(keys))
+		for _, item := range items {
+			byParent[*item.ParentID] = append(byParent[*item.ParentID], item)
+		}
+
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result{Data: byParent[key.String()]}
+		}
+		return results
+	}
+}
+
+// projectsBatch carga proyectos por ID; los que no existen resultan en nil
+func projectsBatch(repo repository.IProjectRepository) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		ids := make([]int, 0, len(keys))
+		for _, key := range keys {
+			if id, err := strconv.Atoi(key.String()); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		projects, err := repo.GetProjectsByIDs(ids)
+		if err != nil {
+			return failed(keys, err)
+		}
+		byID := make(map[string]models.Project, len(projects))
+		for _, project := range projects {
+			byID[project.ID] = project
+		}
+
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			result := &dataloader.Result{}
+			if project, ok := byID[key.String()]; ok {
+				result.Data = project
+			}
+			results[i] = result
+		}
+		return results
+	}
+}
+
+func key(id string) dataloader.Key {
+	return dataloader.StringKey(id)
+}
+
+func keys(ids []string) dataloader.Keys {
+	return dataloader.NewKeysFromStrings(ids)
+}
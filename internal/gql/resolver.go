@@ -0,0 +1,365 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/handlers"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// Resolver es la raíz del esquema: consultas, mutaciones y suscripciones sobre
+// los mismos repositorios y reglas de negocio que la API REST
+type Resolver struct {
+	repo     repository.IRepository
+	projects repository.IProjectRepository
+	tags     repository.ITagRepository
+	items    *handlers.ItemHandler
+	rules    config.ItemRules
+	hub      *events.Hub
+}
+
+// resolverError es un error de la API con el código HTTP equivalente en extensions
+type resolverError struct {
+	message string
+	status  int
+}
+
+func (e resolverError) Error() string {
+	return e.message
+}
+
+func (e resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"status": e.status}
+}
+
+// itemError convierte los errores de las operaciones de items con los mismos
+// criterios que los códigos HTTP de la API REST
+func itemError(err error) error {
+	status, message := handlers.ItemErrorStatus(err)
+	if status == constants.StatusInternalServerError {
+		log.Printf("ERROR: API GraphQL: %v", err)
+	}
+	return resolverError{message: message, status: status}
+}
+
+func badRequest(message string) error {
+	return resolverError{message: message, status: constants.StatusBadRequest}
+}
+
+type metaKey struct{}
+
+func withMeta(ctx context.Context, meta models.RequestMeta) context.Context {
+	return context.WithValue(ctx, metaKey{}, meta)
+}
+
+func metaFrom(ctx context.Context) models.RequestMeta {
+	meta, _ := ctx.Value(metaKey{}).(models.RequestMeta)
+	return meta
+}
+
+// newItem completa los campos calculados, como la API REST
+func (r *Resolver) newItem(item models.TodoItem) *itemResolver {
+	item.RefreshOverdue(time.Now())
+	item.AllowedTransitions = r.rules.Workflow.NextStates(item.State)
+	return &itemResolver{root: r, item: item}
+}
+
+func (r *Resolver) newItems(items []models.TodoItem) []*itemResolver {
+	resolvers := make([]*itemResolver, 0, len(items))
+	for _, item := range items {
+		resolvers = append(resolvers, r.newItem(item))
+	}
+	return resolvers
+}
+
+func (r *Resolver) Item(ctx context.Context, args struct{ ID graphql.ID }) (*itemResolver, error) {
+	if _, err := strconv.Atoi(string(args.ID)); err != nil {
+		return nil, badRequest(constants.IDInvalido)
+	}
+	data, err := loadersFrom(ctx).items.Load(ctx, key(string(args.ID)))()
+	if err != nil {
+		return nil, itemError(err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return r.newItem(data.(models.TodoItem)), nil
+}
+
+type itemFilterInput struct {
+	ProjectID   *graphql.ID
+	Overdue     *bool
+	DueBefore   *graphql.Time
+	Priorities  *[]string
+	Tags        *[]string
+	TagMatchAll *bool
+	AssigneeID  *graphql.ID
+	CreatedBy   *graphql.ID
+	Sort        *string
+}
+
+// filter arma el filtro con las mismas validaciones que GET /items
+func (input *itemFilterInput) filter() (models.ItemFilter, error) {
+	var filter models.ItemFilter
+	if input == nil {
+		return filter, nil
+	}
+	filter.Overdue = input.Overdue
+
+	if input.ProjectID != nil {
+		projectID := string(*input.ProjectID)
+		if _, err := strconv.Atoi(projectID); err != nil {
+			return filter, errors.New(constants.IDProyectoInvalido)
+		}
+		filter.ProjectID = &projectID
+	}
+
+	if input.DueBefore != nil {
+		dueBefore := input.DueBefore.Time
+		filter.DueBefore = &dueBefore
+	}
+
+	if input.Priorities != nil {
+		for _, priority := range *input.Priorities {
+			if !constants.IsValidPriority(priority) {
+				return filter, errors.New(constants.PrioridadInvalida)
+			}
+			filter.Priorities = append(filter.Priorities, priority)
+		}
+	}
+
+	if input.Tags != nil {
+		for _, tag := range *input.Tags {
+			tag = models.NormalizeTagName(tag)
+			if err := models.ValidateTagName(tag); err != nil {
+				return filter, err
+			}
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	filter.TagMatchAll = input.TagMatchAll != nil && *input.TagMatchAll
+
+	if input.AssigneeID != nil {
+		assigneeID := string(*input.AssigneeID)
+		if _, err := strconv.Atoi(assigneeID); err != nil {
+			return filter, errors.New(constants.IDUsuarioInvalido)
+		}
+		filter.AssigneeID = &assigneeID
+	}
+
+	if input.CreatedBy != nil {
+		createdBy := string(*input.CreatedBy)
+		if _, err := strconv.Atoi(createdBy); err != nil {
+			return filter, errors.New(constants.IDUsuarioInvalido)
+		}
+		filter.CreatedBy = &createdBy
+	}
+
+	if input.Sort != nil && *input.Sort != "" {
+		field := strings.TrimPrefix(*input.Sort, "-")
+		if !models.IsValidSortField(field) {
+			return filter, fmt.Errorf("campo de ordenamiento inválido: %s", field)
+		}
+		filter.SortBy = field
+		filter.SortDesc = strings.HasPrefix(*input.Sort, "-")
+	}
+
+	return filter, nil
+}
+
+func (r *Resolver) Items(ctx context.Context, args struct {
+	Filter   *itemFilterInput
+	Page     int32
+	PageSize int32
+}) (*itemPageResolver, error) {
+	filter, err := args.Filter.filter()
+	if err != nil {
+		return nil, badRequest(fmt.Sprintf("%s: %v", constants.FiltroInvalido, err))
+	}
+
+	page, pageSize := int(args.Page), int(args.PageSize)
+	if page < 1 || pageSize < 1 || pageSize > constants.MaxPageSize {
+		return nil, badRequest(constants.PaginacionInvalida)
+	}
+
+	items, err := r.repo.WithMeta(metaFrom(ctx)).GetAll(filter)
+	if err != nil {
+		return nil, itemError(err)
+	}
+
+	start := min((page-1)*pageSize, len(items))
+	end := min(start+pageSize, len(items))
+	return &itemPageResolver{
+		nodes:    r.newItems(items[start:end]),
+		total:    len(items),
+		page:     page,
+		pageSize: pageSize,
+	}, nil
+}
+
+func (r *Resolver) Projects(ctx context.Context, args struct{ IncludeArchived bool }) ([]*projectResolver, error) {
+	projects, err := r.projects.ProjectsForTenant(metaFrom(ctx).Tenant()).GetAllProjects(args.IncludeArchived)
+	if err != nil {
+		return nil, itemError(err)
+	}
+	resolvers := make([]*projectResolver, 0, len(projects))
+	for _, project := range projects {
+		resolvers = append(resolvers, &projectResolver{project: project})
+	}
+	return resolvers, nil
+}
+
+func (r *Resolver) Tags(ctx context.Context) ([]*tagResolver, error) {
+	tags, err := r.tags.TagsForTenant(metaFrom(ctx).Tenant()).GetAllTags()
+	if err != nil {
+		return nil, itemError(err)
+	}
+	resolvers := make([]*tagResolver, 0, len(tags))
+	for _, tag := range tags {
+		resolvers = append(resolvers, &tagResolver{tag: tag})
+	}
+	return resolvers, nil
+}
+
+type itemInput struct {
+	Title       string
+	Description *string
+	State       string
+	Priority    *string
+	Tags        *[]string
+	ProjectID   *graphql.ID
+	ParentID    *graphql.ID
+	StartAt     *graphql.Time
+	DueAt       *graphql.Time
+}
+
+// item convierte la entrada en un item del modelo
+func (input itemInput) item(id string) models.TodoItem {
+	item := models.TodoItem{
+		ID:    id,
+		Title: input.Title,
+		State: input.State,
+	}
+	if input.Description != nil {
+		item.Description = *input.Description
+	}
+	if input.Priority != nil {
+		item.Priority = *input.Priority
+	}
+	if input.Tags != nil {
+		item.Tags = *input.Tags
+	}
+	if input.ProjectID != nil {
+		projectID := string(*input.ProjectID)
+		item.ProjectID = &projectID
+	}
+	if input.ParentID != nil {
+		parentID := string(*input.ParentID)
+		item.ParentID = &parentID
+	}
+	item.StartAt = fromTime(input.StartAt)
+	item.DueAt = fromTime(input.DueAt)
+	return item
+}
+
+func fromTime(value *graphql.Time) *string {
+	if value == nil {
+		return nil
+	}
+	formatted := value.UTC().Format(time.RFC3339)
+	return &formatted
+}
+
+func (r *Resolver) CreateItem(ctx context.Context, args struct{ Input itemInput }) (*itemResolver, error) {
+	item, err := r.items.Create(metaFrom(ctx), args.Input.item(""))
+	if err != nil {
+		return nil, itemError(err)
+	}
+	return r.newItem(item), nil
+}
+
+func (r *Resolver) UpdateItem(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input itemInput
+}) (*itemResolver, error) {
+	item, _, err := r.items.Update(metaFrom(ctx), args.Input.item(string(args.ID)))
+	if err != nil {
+		return nil, itemError(err)
+	}
+	return r.newItem(item), nil
+}
+
+func (r *Resolver) DeleteItem(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	if _, err := strconv.Atoi(string(args.ID)); err != nil {
+		return false, badRequest(constants.IDInvalido)
+	}
+	if err := r.repo.WithMeta(metaFrom(ctx)).Delete(string(args.ID)); err != nil {
+		return false, itemError(err)
+	}
+	return true, nil
+}
+
+type itemChangeFilterInput struct {
+	ItemIDs   *[]graphql.ID
+	ProjectID *graphql.ID
+	States    *[]string
+}
+
+// matches indica si el evento cumple el filtro de la suscripción
+func (input *itemChangeFilterInput) matches(event events.Event) bool {
+	if input == nil {
+		return true
+	}
+	if input.ItemIDs != nil && len(*input.ItemIDs) > 0 && !slices.Contains(*input.ItemIDs, graphql.ID(event.ItemID)) {
+		return false
+	}
+	if input.States != nil && len(*input.States) > 0 && !slices.Contains(*input.States, event.Item.State) {
+		return false
+	}
+	if input.ProjectID != nil && (event.Item.ProjectID == nil || *event.Item.ProjectID != string(*input.ProjectID)) {
+		return false
+	}
+	return true
+}
+
+// ItemChanged envía los cambios de items hasta que el cliente se desconecta o
+// el hub lo corta por no consumir a tiempo
+func (r *Resolver) ItemChanged(ctx context.Context, args struct{ Filter *itemChangeFilterInput }) <-chan *itemChangeResolver {
+	tenantID := metaFrom(ctx).Tenant()
+	sub, _, _ := r.hub.Subscribe(0, func(event events.Event) bool {
+		return event.TenantID == tenantID && args.Filter.matches(event)
+	})
+	changes := make(chan *itemChangeResolver)
+	go func() {
+		defer close(changes)
+		defer sub.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				select {
+				case changes <- &itemChangeResolver{root: r, event: event}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return changes
+}
@@ -0,0 +1,74 @@
+// Package gql sirve la API GraphQL de items (/graphql) junto a la API REST.
+package gql
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/handlers"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Request es una operación GraphQL, por POST (JSON) o por GET (query params)
+type Request struct {
+	Query         string         `json:"query" form:"query"`
+	OperationName string         `json:"operationName" form:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Server ejecuta las operaciones sobre el esquema con los límites configurados
+type Server struct {
+	schema   *graphql.Schema
+	repo     repository.IRepository
+	projects repository.IProjectRepository
+	config   config.GraphQLConfig
+}
+
+func NewServer(repo repository.IRepository, projects repository.IProjectRepository, tags repository.ITagRepository, rules config.ItemRules, hub *events.Hub, cfg config.GraphQLConfig) *Server {
+	resolver := &Resolver{
+		repo:     repo,
+		projects: projects,
+		tags:     tags,
+		items:    handlers.NewItemHandler(repo, rules),
+		rules:    rules,
+		hub:      hub,
+	}
+	return &Server{
+		schema:   graphql.MustParseSchema(schemaSDL, resolver, graphql.UseStringDescriptions()),
+		repo:     repo,
+		projects: projects,
+		config:   cfg,
+	}
+}
+
+// Check devuelve el tipo de operación y un error si supera los límites de
+// profundidad o complejidad. Si la consulta no se puede interpretar devuelve
+// una operación vacía y deja que la ejecución informe el error.
+func (s *Server) Check(req Request) (ast.Operation, error) {
+	result, ok := analyze(req.Query, req.OperationName, req.Variables)
+	if !ok {
+		return "", nil
+	}
+	return result.Operation, result.check(s.config.MaxDepth, s.config.MaxComplexity)
+}
+
+// Exec ejecuta una consulta o mutación. Cada ejecución tiene sus propios
+// loaders, de modo que los lotes y la caché no se comparten entre peticiones.
+func (s *Server) Exec(ctx context.Context, meta models.RequestMeta, req Request) *graphql.Response {
+	ctx = withLoaders(withMeta(ctx, meta), newLoaders(s.repo.WithMeta(meta), s.projects.ProjectsForTenant(meta.Tenant()), true))
+	return s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
+
+// Subscribe ejecuta una suscripción; el canal se cierra cuando termina o se cancela ctx
+func (s *Server) Subscribe(ctx context.Context, meta models.RequestMeta, req Request) (<-chan interface{}, error) {
+	ctx = withLoaders(withMeta(ctx, meta), newLoaders(s.repo.WithMeta(meta), s.projects.ProjectsForTenant(meta.Tenant()), false))
+	return s.schema.Subscribe(ctx, req.Query, req.OperationName, req.Variables)
+}
@@ -0,0 +1,274 @@
+package gql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingRepository cuenta las consultas de listado para verificar que los
+// loaders agrupan las cargas. Las vistas de WithMeta comparten el contador.
+type countingRepository struct {
+	repository.IRepository
+	getAll *atomic.Int32
+}
+
+func (r *countingRepository) WithMeta(meta models.RequestMeta) repository.IRepository {
+	return &countingRepository{IRepository: r.IRepository.WithMeta(meta), getAll: r.getAll}
+}
+
+func (r *countingRepository) GetAll(filter models.ItemFilter) ([]models.TodoItem, error) {
+	r.getAll.Add(1)
+	return r.IRepository.GetAll(filter)
+}
+
+type graphQLResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func setupGraphQL(t *testing.T, cfg config.GraphQLConfig) (*gin.Engine, *countingRepository, *repository.MockRepository) {
+	gin.SetMode(gin.TestMode)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	hub := events.NewHub(events.NewMemoryPubSub(), 10)
+	go hub.Run(ctx)
+	// Esperamos a que el hub se suscriba antes de publicar
+	time.Sleep(20 * time.Millisecond)
+
+	mock := repository.NewMockRepository()
+	repo := &countingRepository{IRepository: events.NewPublishingRepository(mock, hub), getAll: new(atomic.Int32)}
+	handler := NewHandler(NewServer(repo, mock, mock, config.DefaultItemRules(), hub, cfg), time.Second)
+
+	router := gin.New()
+	router.Use(middleware.RequestMeta())
+	router.GET("/graphql", handler.Serve)
+	router.POST("/graphql", handler.Serve)
+	return router, repo, mock
+}
+
+func postQuery(router *gin.Engine, query string, variables map[string]any) (*httptest.ResponseRecorder, graphQLResponse) {
+	body, _ := json.Marshal(Request{Query: query, Variables: variables})
+	req, _ := http.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.ActorHeader, "ana")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response graphQLResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func TestQueryItemsWithNestedFieldsIsBatched(t *testing.T) {
+	router, repo, mock := setupGraphQL(t, config.DefaultGraphQLConfig())
+	project, _ := mock.CreateProject(models.Project{Name: "Backend"})
+	for _, title := range []string{"A", "B", "C"} {
+		parent, _ := repo.Create(models.TodoItem{Title: title, State: constants.StatePending, Priority: constants.PriorityHigh, ProjectID: &project.ID})
+		repo.Create(models.TodoItem{Title: title + "1", State: constants.StateCompleted, Priority: constants.PriorityLow, ParentID: &parent.ID})
+	}
+	repo.getAll.Store(0)
+
+	w, response := postQuery(router, `query($priorities: [String!]) {
+		items(filter: {priorities: $priorities, sort: "title"}, pageSize: 2) {
+			total
+			nodes { title allowedTransitions project { name } subtasks { title parent { title } } }
+		}
+	}`, map[string]any{"priorities": []string{constants.PriorityHigh}})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, response.Errors)
+	items := response.Data["items"].(map[string]any)
+	assert.Equal(t, float64(3), items["total"])
+	nodes := items["nodes"].([]any)
+	if assert.Len(t, nodes, 2) {
+		first := nodes[0].(map[string]any)
+		assert.Equal(t, "A", first["title"])
+		assert.Equal(t, "Backend", first["project"].(map[string]any)["name"])
+		subtasks := first["subtasks"].([]any)
+		if assert.Len(t, subtasks, 1) {
+			assert.Equal(t, "A", subtasks[0].(map[string]any)["parent"].(map[string]any)["title"])
+		}
+		assert.NotEmpty(t, first["allowedTransitions"])
+	}
+	// Una consulta para la página, una para las subtareas de todos los items y una para sus padres
+	assert.Equal(t, int32(3), repo.getAll.Load())
+}
+
+func TestQueryItem(t *testing.T) {
+	router, repo, _ := setupGraphQL(t, config.DefaultGraphQLConfig())
+	repo.Create(models.TodoItem{Title: "Task", State: constants.StatePending, Priority: constants.PriorityLow})
+
+	_, response := postQuery(router, `{ item(id: "1") { id title state } missing: item(id: "99") { id } }`, nil)
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, "Task", response.Data["item"].(map[string]any)["title"])
+	assert.Nil(t, response.Data["missing"])
+
+	_, response = postQuery(router, `{ item(id: "abc") { id } }`, nil)
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, constants.IDInvalido, response.Errors[0].Message)
+	}
+
+	_, response = postQuery(router, `{ items(pageSize: 1000) { total } }`, nil)
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, constants.PaginacionInvalida, response.Errors[0].Message)
+	}
+}
+
+func TestMutations(t *testing.T) {
+	router, repo, _ := setupGraphQL(t, config.DefaultGraphQLConfig())
+
+	_, response := postQuery(router, `mutation { createItem(input: {title: "Task", state: "pending", tags: ["Backend"], dueAt: "2030-01-02T10:00:00Z"}) { id priority tags dueAt } }`, nil)
+	assert.Empty(t, response.Errors)
+	created := response.Data["createItem"].(map[string]any)
+	assert.Equal(t, "1", created["id"])
+	assert.Equal(t, constants.PriorityMedium, created["priority"])
+	assert.Equal(t, []any{"backend"}, created["tags"])
+	assert.Equal(t, "2030-01-02T10:00:00Z", created["dueAt"])
+
+	history, _, _ := repo.GetHistory(1, 1, 10)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, "ana", history[0].Actor)
+	}
+
+	_, response = postQuery(router, `mutation { updateItem(id: "1", input: {title: "Task 2", state: "completed"}) { title state } }`, nil)
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, "Task 2", response.Data["updateItem"].(map[string]any)["title"])
+
+	// El flujo por defecto solo permite reabrir un item completado pasándolo a in_progress
+	_, response = postQuery(router, `mutation { updateItem(id: "1", input: {title: "Task 2", state: "pending"}) { id } }`, nil)
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, constants.TransicionInvalida, response.Errors[0].Message)
+		assert.Equal(t, float64(http.StatusConflict), response.Errors[0].Extensions["status"])
+	}
+
+	_, response = postQuery(router, `mutation { createItem(input: {title: "", state: "pending"}) { id } }`, nil)
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, float64(http.StatusBadRequest), response.Errors[0].Extensions["status"])
+	}
+
+	_, response = postQuery(router, `mutation { deleteItem(id: "1") }`, nil)
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, true, response.Data["deleteItem"])
+
+	_, response = postQuery(router, `mutation { deleteItem(id: "1") }`, nil)
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, constants.ItemNoEncontrado, response.Errors[0].Message)
+		assert.Equal(t, float64(http.StatusNotFound), response.Errors[0].Extensions["status"])
+	}
+}
+
+func TestMutationByGETIsRejected(t *testing.T) {
+	router, _, _ := setupGraphQL(t, config.DefaultGraphQLConfig())
+
+	query := url.Values{"query": {`mutation { deleteItem(id: "1") }`}}
+	req, _ := http.NewRequest("GET", "/graphql?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Contains(t, w.Body.String(), constants.MutacionPorGET)
+
+	query = url.Values{"query": {`{ items { total } }`}}
+	req, _ = http.NewRequest("GET", "/graphql?"+query.Encode(), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"items":{"total":0}}}`, w.Body.String())
+}
+
+func TestQueryLimits(t *testing.T) {
+	router, _, _ := setupGraphQL(t, config.GraphQLConfig{MaxDepth: 4, MaxComplexity: 100})
+
+	w, response := postQuery(router, `{ items { nodes { parent { parent { parent { id } } } } } }`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if assert.Len(t, response.Errors, 1) {
+		assert.True(t, strings.HasPrefix(response.Errors[0].Message, constants.ConsultaMuyProfunda))
+	}
+
+	// 20 items con 10 subtareas cada uno superan la complejidad máxima
+	w, response = postQuery(router, `{ items { nodes { subtasks { id } } } }`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if assert.Len(t, response.Errors, 1) {
+		assert.True(t, strings.HasPrefix(response.Errors[0].Message, constants.ConsultaMuyCompleja))
+	}
+
+	w, _ = postQuery(router, `query($size: Int) { items(pageSize: $size) { nodes { subtasks { id } } } }`, map[string]any{"size": 2})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// La introspección no cuenta para los límites
+	w, response = postQuery(router, `{ __schema { types { name fields { name type { name ofType { name } } } } } }`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, response.Errors)
+}
+
+func TestSubscriptionOverSSE(t *testing.T) {
+	router, repo, _ := setupGraphQL(t, config.DefaultGraphQLConfig())
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	body, _ := json.Marshal(Request{Query: `subscription { itemChanged(filter: {states: ["pending"]}) { type itemId item { title } } }`})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "POST", server.URL+"/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Esperamos a que el servidor registre la suscripción
+	time.Sleep(50 * time.Millisecond)
+	repo.Create(models.TodoItem{Title: "Completada", State: constants.StateCompleted})
+	repo.Create(models.TodoItem{Title: "Pendiente", State: constants.StatePending})
+
+	reader := bufio.NewReader(resp.Body)
+	var event, data string
+	for data == "" {
+		line, err := reader.ReadString('\n')
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		line = strings.TrimSpace(line)
+		if value, ok := strings.CutPrefix(line, "event:"); ok {
+			event = value
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = value
+		}
+	}
+	assert.Equal(t, constants.GraphQLEventNext, event)
+	assert.JSONEq(t, `{"data":{"itemChanged":{"type":"item.created","itemId":"2","item":{"title":"Pendiente"}}}}`, data)
+}
+
+func TestSubscriptionRequiresSSE(t *testing.T) {
+	router, _, _ := setupGraphQL(t, config.DefaultGraphQLConfig())
+
+	w, response := postQuery(router, `subscription { itemChanged { type } }`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, constants.SuscripcionRequiereSSE, response.Errors[0].Message)
+	}
+}
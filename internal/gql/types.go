@@ -0,0 +1,164 @@
+package gql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// toTime convierte una fecha RFC3339 del modelo; nil si no hay fecha
+func toTime(value *string) *graphql.Time {
+	if value == nil || *value == "" {
+		return nil
+	}
+	parsed, err := models.ParseTimestamp(*value)
+	if err != nil {
+		return nil
+	}
+	return &graphql.Time{Time: parsed}
+}
+
+type itemResolver struct {
+	root *Resolver
+	item models.TodoItem
+}
+
+func (r *itemResolver) ID() graphql.ID           { return graphql.ID(r.item.ID) }
+func (r *itemResolver) Title() string            { return r.item.Title }
+func (r *itemResolver) Description() string      { return r.item.Description }
+func (r *itemResolver) State() string            { return r.item.State }
+func (r *itemResolver) Priority() string         { return r.item.Priority }
+func (r *itemResolver) StartAt() *graphql.Time   { return toTime(r.item.StartAt) }
+func (r *itemResolver) DueAt() *graphql.Time     { return toTime(r.item.DueAt) }
+func (r *itemResolver) Overdue() bool            { return r.item.Overdue }
+func (r *itemResolver) CreatedAt() *graphql.Time { return toTime(&r.item.CreatedAt) }
+func (r *itemResolver) UpdatedAt() *graphql.Time { return toTime(&r.item.UpdatedAt) }
+
+func (r *itemResolver) Tags() []string {
+	if r.item.Tags == nil {
+		return []string{}
+	}
+	return r.item.Tags
+}
+
+func (r *itemResolver) AllowedTransitions() []string {
+	if r.item.AllowedTransitions == nil {
+		return []string{}
+	}
+	return r.item.AllowedTransitions
+}
+
+func (r *itemResolver) Progress() *int32 {
+	if r.item.Progress == nil {
+		return nil
+	}
+	progress := int32(*r.item.Progress)
+	return &progress
+}
+
+func (r *itemResolver) Project(ctx context.Context) (*projectResolver, error) {
+	if r.item.ProjectID == nil {
+		return nil, nil
+	}
+	data, err := loadersFrom(ctx).projects.Load(ctx, key(*r.item.ProjectID))()
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return &projectResolver{project: data.(models.Project)}, nil
+}
+
+func (r *itemResolver) Parent(ctx context.Context) (*itemResolver, error) {
+	if r.item.ParentID == nil {
+		return nil, nil
+	}
+	data, err := loadersFrom(ctx).items.Load(ctx, key(*r.item.ParentID))()
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return r.root.newItem(data.(models.TodoItem)), nil
+}
+
+func (r *itemResolver) CreatedBy() *graphql.ID  { return toID(r.item.CreatedBy) }
+func (r *itemResolver) AssigneeID() *graphql.ID { return toID(r.item.AssigneeID) }
+
+// toID convierte un ID opcional del modelo
+func toID(value *string) *graphql.ID {
+	if value == nil {
+		return nil
+	}
+	id := graphql.ID(*value)
+	return &id
+}
+
+func (r *itemResolver) Subtasks(ctx context.Context) ([]*itemResolver, error) {
+	data, err := loadersFrom(ctx).subtasks.Load(ctx, key(r.item.ID))()
+	if err != nil {
+		return nil, err
+	}
+	return r.root.newItems(data.([]models.TodoItem)), nil
+}
+
+func (r *itemResolver) BlockedBy(ctx context.Context) ([]*itemResolver, error) {
+	blockers := []*itemResolver{}
+	if len(r.item.BlockedBy) == 0 {
+		return blockers, nil
+	}
+	data, errs := loadersFrom(ctx).items.LoadMany(ctx, keys(r.item.BlockedBy))()
+	for i, value := range data {
+		if len(errs) > i && errs[i] != nil {
+			return nil, errs[i]
+		}
+		if value != nil {
+			blockers = append(blockers, r.root.newItem(value.(models.TodoItem)))
+		}
+	}
+	return blockers, nil
+}
+
+type itemPageResolver struct {
+	nodes    []*itemResolver
+	total    int
+	page     int
+	pageSize int
+}
+
+func (r *itemPageResolver) Nodes() []*itemResolver { return r.nodes }
+func (r *itemPageResolver) Total() int32           { return int32(r.total) }
+func (r *itemPageResolver) Page() int32            { return int32(r.page) }
+func (r *itemPageResolver) PageSize() int32        { return int32(r.pageSize) }
+
+type projectResolver struct {
+	project models.Project
+}
+
+func (r *projectResolver) ID() graphql.ID           { return graphql.ID(r.project.ID) }
+func (r *projectResolver) Name() string             { return r.project.Name }
+func (r *projectResolver) Description() string      { return r.project.Description }
+func (r *projectResolver) Archived() bool           { return r.project.Archived }
+func (r *projectResolver) CreatedAt() *graphql.Time { return toTime(&r.project.CreatedAt) }
+func (r *projectResolver) UpdatedAt() *graphql.Time { return toTime(&r.project.UpdatedAt) }
+
+type tagResolver struct {
+	tag models.Tag
+}
+
+func (r *tagResolver) ID() graphql.ID   { return graphql.ID(r.tag.ID) }
+func (r *tagResolver) Name() string     { return r.tag.Name }
+func (r *tagResolver) ItemCount() int32 { return int32(r.tag.ItemCount) }
+
+type itemChangeResolver struct {
+	root  *Resolver
+	event events.Event
+}
+
+func (r *itemChangeResolver) EventID() graphql.ID {
+	return graphql.ID(strconv.FormatUint(r.event.ID, 10))
+}
+func (r *itemChangeResolver) Type() string       { return r.event.Type }
+func (r *itemChangeResolver) ItemID() graphql.ID { return graphql.ID(r.event.ItemID) }
+func (r *itemChangeResolver) Item() *itemResolver {
+	return r.root.newItem(r.event.Item)
+}
@@ -0,0 +1,141 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	todov1 "github.com/Milagrosgzmn/devops_todo_go.git/api/todo/v1"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// readMethods son los métodos de TodoService que solo leen y exigen
+// items:read; el resto exige items:write, como las rutas REST de items
+var readMethods = map[string]bool{
+	todov1.TodoService_Get_FullMethodName:   true,
+	todov1.TodoService_List_FullMethodName:  true,
+	todov1.TodoService_Watch_FullMethodName: true,
+}
+
+// session es lo que guarda el interceptor en el contexto de cada llamada: las
+// credenciales y el tenant con el que trabaja
+type session struct {
+	credentials middleware.Credentials
+	tenant      models.Tenant
+}
+
+type sessionKey struct{}
+
+// sessionFrom devuelve la sesión de la llamada, si pasó por el interceptor
+func sessionFrom(ctx context.Context) (session, bool) {
+	value, ok := ctx.Value(sessionKey{}).(session)
+	return value, ok
+}
+
+// authInterceptor autentica las llamadas a TodoService con las mismas
+// credenciales que la API REST (metadata x-api-key o authorization: Bearer) y
+// exige el scope de cada método. Health checking y reflection no se autentican,
+// como /health y la documentación.
+type authInterceptor struct {
+	authenticator *middleware.Authenticator
+	tenants       repository.ITenantRepository
+}
+
+func (i *authInterceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := i.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (i *authInterceptor) stream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := i.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedStream es el stream de la llamada con la sesión en su contexto
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate devuelve el contexto con la sesión de la llamada, o responde
+// Unauthenticated si las credenciales faltan o no son válidas, NotFound si el
+// tenant de x-tenant no existe y PermissionDenied si las credenciales son de
+// otro tenant o no tienen el scope del método
+func (i *authInterceptor) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if !strings.HasPrefix(method, "/"+todov1.TodoService_ServiceDesc.ServiceName+"/") {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	credentials, err := i.authenticator.Authenticate(ctx, first(md, constants.APIKeyHeader), middleware.BearerToken(first(md, "authorization")))
+	if err != nil {
+		return nil, authStatus(err)
+	}
+
+	var requested models.Tenant
+	slug := strings.ToLower(strings.TrimSpace(first(md, constants.TenantHeader)))
+	if slug != "" {
+		requested, err = i.tenants.GetTenantBySlug(slug)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, constants.TenantNoEncontrado)
+		}
+		if err != nil {
+			return nil, authStatus(err)
+		}
+	}
+	tenant, err := i.authenticator.Tenant(credentials, requested, slug != "")
+	if err != nil {
+		return nil, authStatus(err)
+	}
+
+	scope := constants.ScopeItemsWrite
+	if readMethods[method] {
+		scope = constants.ScopeItemsRead
+	}
+	if !models.HasScope(credentials.Scopes, scope) {
+		return nil, status.Error(codes.PermissionDenied, constants.ScopeInsuficiente)
+	}
+	return context.WithValue(ctx, sessionKey{}, session{credentials: credentials, tenant: tenant}), nil
+}
+
+// authStatus convierte los errores de middleware.Authenticator en errores gRPC,
+// con los mismos criterios que los códigos HTTP de la API REST
+func authStatus(err error) error {
+	code, message := middleware.AuthErrorStatus(err)
+	switch code {
+	case constants.StatusUnauthorized:
+		return status.Error(codes.Unauthenticated, message)
+	case constants.StatusForbidden:
+		return status.Error(codes.PermissionDenied, message)
+	default:
+		log.Printf("ERROR: API gRPC: %v", err)
+		return status.Error(codes.Internal, message)
+	}
+}
+
+// first devuelve el primer valor de la clave en la metadata, o vacío
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
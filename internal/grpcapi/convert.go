@@ -0,0 +1,72 @@
+package grpcapi
+
+import (
+	"time"
+
+	todov1 "github.com/Milagrosgzmn/devops_todo_go.git/api/todo/v1"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toProto convierte un item del modelo al mensaje de la API gRPC
+func toProto(item models.TodoItem) *todov1.TodoItem {
+	message := &todov1.TodoItem{
+		Id:                 item.ID,
+		ProjectId:          item.ProjectID,
+		ParentId:           item.ParentID,
+		Title:              item.Title,
+		Description:        item.Description,
+		State:              item.State,
+		Priority:           item.Priority,
+		Tags:               item.Tags,
+		BlockedBy:          item.BlockedBy,
+		StartAt:            toTimestamp(item.StartAt),
+		DueAt:              toTimestamp(item.DueAt),
+		Overdue:            item.Overdue,
+		CreatedAt:          toTimestamp(&item.CreatedAt),
+		UpdatedAt:          toTimestamp(&item.UpdatedAt),
+		AllowedTransitions: item.AllowedTransitions,
+	}
+	if item.Progress != nil {
+		progress := int32(*item.Progress)
+		message.Progress = &progress
+	}
+	return message
+}
+
+// fromProto convierte el mensaje recibido en un item del modelo; los campos
+// calculados se ignoran
+func fromProto(message *todov1.TodoItem) models.TodoItem {
+	return models.TodoItem{
+		ID:          message.GetId(),
+		ProjectID:   message.ProjectId,
+		ParentID:    message.ParentId,
+		Title:       message.GetTitle(),
+		Description: message.GetDescription(),
+		State:       message.GetState(),
+		Priority:    message.GetPriority(),
+		Tags:        message.GetTags(),
+		StartAt:     fromTimestamp(message.GetStartAt()),
+		DueAt:       fromTimestamp(message.GetDueAt()),
+	}
+}
+
+// toTimestamp convierte una fecha RFC3339 del modelo; nil si no hay fecha
+func toTimestamp(value *string) *timestamppb.Timestamp {
+	if value == nil || *value == "" {
+		return nil
+	}
+	parsed, err := models.ParseTimestamp(*value)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(parsed)
+}
+
+func fromTimestamp(value *timestamppb.Timestamp) *string {
+	if value == nil {
+		return nil
+	}
+	formatted := value.AsTime().UTC().Format(time.RFC3339)
+	return &formatted
+}
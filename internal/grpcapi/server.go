@@ -0,0 +1,30 @@
+// Package grpcapi sirve la API gRPC de items (api/todo/v1) junto a la API REST.
+package grpcapi
+
+import (
+	todov1 "github.com/Milagrosgzmn/devops_todo_go.git/api/todo/v1"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer crea el servidor gRPC con TodoService, que autentica cada llamada
+// con authenticator (ver authInterceptor), el servicio estándar de health
+// checking (grpc.health.v1) y reflection para herramientas como grpcurl
+func NewServer(repo repository.IRepository, tenants repository.ITenantRepository, authenticator *middleware.Authenticator, rules config.ItemRules, hub *events.Hub) *grpc.Server {
+	interceptor := &authInterceptor{authenticator: authenticator, tenants: tenants}
+	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor.unary), grpc.StreamInterceptor(interceptor.stream))
+	todov1.RegisterTodoServiceServer(server, NewTodoService(repo, rules, hub))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(todov1.TodoService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server
+}
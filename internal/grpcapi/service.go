@@ -0,0 +1,320 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	todov1 "github.com/Milagrosgzmn/devops_todo_go.git/api/todo/v1"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/handlers"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TodoService implementa todov1.TodoServiceServer sobre el mismo repositorio y
+// las mismas reglas de negocio que la API REST
+type TodoService struct {
+	todov1.UnimplementedTodoServiceServer
+	repo  repository.IRepository
+	items *handlers.ItemHandler
+	rules config.ItemRules
+	hub   *events.Hub
+}
+
+func NewTodoService(repo repository.IRepository, rules config.ItemRules, hub *events.Hub) *TodoService {
+	return &TodoService{
+		repo:  repo,
+		items: handlers.NewItemHandler(repo, rules),
+		rules: rules,
+		hub:   hub,
+	}
+}
+
+// requestMeta arma los datos de la llamada con la sesión del authInterceptor
+// (el usuario o la API key y su tenant) y la metadata x-actor y x-request-id,
+// las mismas cabeceras que la API REST. Un usuario queda como actor de los
+// cambios que haga. Sin sesión responde Unauthenticated.
+func (s *TodoService) requestMeta(ctx context.Context) (models.RequestMeta, error) {
+	session, ok := sessionFrom(ctx)
+	if !ok {
+		return models.RequestMeta{}, status.Error(codes.Unauthenticated, constants.APIKeyRequerida)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	meta := models.RequestMeta{RequestID: middleware.NewRequestID(), TenantID: session.tenant.ID}
+	if values := md.Get(middleware.ActorHeader); len(values) > 0 {
+		meta.Actor = values[0]
+	}
+	if values := md.Get(middleware.RequestIDHeader); len(values) > 0 && values[0] != "" && len(values[0]) <= 64 {
+		meta.RequestID = values[0]
+	}
+	if user := session.credentials.User; user != nil {
+		meta.Actor = user.Email
+	}
+	session.credentials.SetUserAccess(&meta)
+	return meta, nil
+}
+
+// toStatus convierte los errores de las operaciones de items en errores gRPC,
+// con los mismos criterios que los códigos HTTP de la API REST
+func toStatus(err error) error {
+	code, message := handlers.ItemErrorStatus(err)
+	if errors.Is(err, repository.ErrItemQuotaExceeded) {
+		return status.Error(codes.ResourceExhausted, message)
+	}
+	switch code {
+	case constants.StatusBadRequest, constants.StatusUnprocessableEntity:
+		return status.Error(codes.InvalidArgument, message)
+	case constants.StatusUnauthorized:
+		return status.Error(codes.Unauthenticated, message)
+	case constants.StatusForbidden:
+		return status.Error(codes.PermissionDenied, message)
+	case constants.StatusNotFound:
+		return status.Error(codes.NotFound, message)
+	case constants.StatusConflict:
+		return status.Error(codes.FailedPrecondition, message)
+	default:
+		log.Printf("ERROR: API gRPC: %v", err)
+		return status.Error(codes.Internal, message)
+	}
+}
+
+// parseID valida el ID de un item
+func parseID(id string) (int, error) {
+	parsed, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, constants.IDInvalido)
+	}
+	return parsed, nil
+}
+
+// decorate completa los campos calculados, como la API REST
+func (s *TodoService) decorate(item *models.TodoItem, now time.Time) {
+	item.RefreshOverdue(now)
+	item.AllowedTransitions = s.rules.Workflow.NextStates(item.State)
+}
+
+func (s *TodoService) Get(ctx context.Context, req *todov1.GetRequest) (*todov1.GetResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	meta, err := s.requestMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.repo.WithMeta(meta).Get(id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	s.decorate(&item, time.Now())
+	return &todov1.GetResponse{Item: toProto(item)}, nil
+}
+
+// listFilter arma el filtro con las mismas validaciones que GET /items
+func listFilter(req *todov1.ListRequest) (models.ItemFilter, error) {
+	filter := models.ItemFilter{
+		Overdue:     req.Overdue,
+		TagMatchAll: req.GetTagMatchAll(),
+	}
+
+	if req.ProjectId != nil {
+		if _, err := strconv.Atoi(req.GetProjectId()); err != nil {
+			return filter, errors.New(constants.IDProyectoInvalido)
+		}
+		filter.ProjectID = req.ProjectId
+	}
+
+	if req.GetDueBefore() != nil {
+		dueBefore := req.GetDueBefore().AsTime()
+		filter.DueBefore = &dueBefore
+	}
+
+	for _, priority := range req.GetPriorities() {
+		if !constants.IsValidPriority(priority) {
+			return filter, errors.New(constants.PrioridadInvalida)
+		}
+		filter.Priorities = append(filter.Priorities, priority)
+	}
+
+	for _, tag := range req.GetTags() {
+		tag = models.NormalizeTagName(tag)
+		if err := models.ValidateTagName(tag); err != nil {
+			return filter, err
+		}
+		filter.Tags = append(filter.Tags, tag)
+	}
+
+	if value := req.GetSort(); value != "" {
+		field := strings.TrimPrefix(value, "-")
+		if !models.IsValidSortField(field) {
+			return filter, fmt.Errorf("campo de ordenamiento inválido: %s", field)
+		}
+		filter.SortBy = field
+		filter.SortDesc = strings.HasPrefix(value, "-")
+	}
+
+	return filter, nil
+}
+
+func (s *TodoService) List(ctx context.Context, req *todov1.ListRequest) (*todov1.ListResponse, error) {
+	filter, err := listFilter(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: %v", constants.FiltroInvalido, err)
+	}
+
+	page, pageSize := int(req.GetPage()), int(req.GetPageSize())
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = constants.DefaultPageSize
+	}
+	if page < 1 || pageSize < 1 || pageSize > constants.MaxPageSize {
+		return nil, status.Error(codes.InvalidArgument, constants.PaginacionInvalida)
+	}
+
+	meta, err := s.requestMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.WithMeta(meta).GetAll(filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &todov1.ListResponse{Total: int32(len(items))}
+	start := min((page-1)*pageSize, len(items))
+	end := min(start+pageSize, len(items))
+	now := time.Now()
+	for _, item := range items[start:end] {
+		s.decorate(&item, now)
+		response.Items = append(response.Items, toProto(item))
+	}
+	return response, nil
+}
+
+func (s *TodoService) Create(ctx context.Context, req *todov1.CreateRequest) (*todov1.CreateResponse, error) {
+	if req.GetItem() == nil {
+		return nil, status.Error(codes.InvalidArgument, constants.CuerpoInvalido)
+	}
+	meta, err := s.requestMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.items.Create(meta, fromProto(req.GetItem()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &todov1.CreateResponse{Item: toProto(item)}, nil
+}
+
+func (s *TodoService) Update(ctx context.Context, req *todov1.UpdateRequest) (*todov1.UpdateResponse, error) {
+	if req.GetItem() == nil {
+		return nil, status.Error(codes.InvalidArgument, constants.CuerpoInvalido)
+	}
+	if _, err := parseID(req.GetItem().GetId()); err != nil {
+		return nil, err
+	}
+	meta, err := s.requestMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	item, warning, err := s.items.Update(meta, fromProto(req.GetItem()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &todov1.UpdateResponse{Item: toProto(item), Warning: warning}, nil
+}
+
+func (s *TodoService) Delete(ctx context.Context, req *todov1.DeleteRequest) (*todov1.DeleteResponse, error) {
+	if _, err := parseID(req.GetId()); err != nil {
+		return nil, err
+	}
+	meta, err := s.requestMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.WithMeta(meta).Delete(req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &todov1.DeleteResponse{}, nil
+}
+
+// watchFilter arma el filtro de Watch: los items del tenant indicados, del
+// proyecto y en los estados pedidos
+func watchFilter(req *todov1.WatchRequest, tenantID string) func(events.Event) bool {
+	return func(event events.Event) bool {
+		if event.TenantID != tenantID {
+			return false
+		}
+		if len(req.GetItemIds()) > 0 && !slices.Contains(req.GetItemIds(), event.ItemID) {
+			return false
+		}
+		if len(req.GetStates()) > 0 && !slices.Contains(req.GetStates(), event.Item.State) {
+			return false
+		}
+		if req.ProjectId != nil && (event.Item.ProjectID == nil || *event.Item.ProjectID != req.GetProjectId()) {
+			return false
+		}
+		return true
+	}
+}
+
+// Watch envía los cambios de items hasta que el cliente cancela. Si no consume
+// a tiempo se corta con Unavailable y puede retomar con last_event_id.
+func (s *TodoService) Watch(req *todov1.WatchRequest, stream todov1.TodoService_WatchServer) error {
+	meta, err := s.requestMeta(stream.Context())
+	if err != nil {
+		return err
+	}
+	sub, replay, complete := s.hub.Subscribe(req.GetLastEventId(), watchFilter(req, meta.Tenant()))
+	defer sub.Close()
+
+	if !complete {
+		if err := stream.Send(&todov1.WatchResponse{Resync: true}); err != nil {
+			return err
+		}
+	}
+	for _, event := range replay {
+		if err := s.send(stream, event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-sub.Events:
+			if !ok {
+				return status.Error(codes.Unavailable, constants.WSClienteLento)
+			}
+			if err := s.send(stream, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *TodoService) send(stream todov1.TodoService_WatchServer, event events.Event) error {
+	s.decorate(&event.Item, time.Now())
+	return stream.Send(&todov1.WatchResponse{
+		EventId: event.ID,
+		Type:    event.Type,
+		ItemId:  event.ItemID,
+		Item:    toProto(event.Item),
+	})
+}
@@ -0,0 +1,274 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	todov1 "github.com/Milagrosgzmn/devops_todo_go.git/api/todo/v1"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// bootstrapKey es la key de bootstrap del servidor de prueba, con scope admin
+const bootstrapKey = "bootstrap-secreta"
+
+func setupGRPC(t *testing.T) (*grpc.ClientConn, repository.IRepository) {
+	conn, repo, _, _ := setupGRPCAuth(t)
+	return conn, repo
+}
+
+// setupGRPCAuth arma el servidor y devuelve además el repositorio de usuarios,
+// keys y tenants y el emisor de tokens de acceso
+func setupGRPCAuth(t *testing.T) (*grpc.ClientConn, repository.IRepository, *repository.MockRepository, *auth.TokenIssuer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := events.NewHub(events.NewMemoryPubSub(), 10)
+	go hub.Run(ctx)
+	// Esperamos a que el hub se suscriba antes de publicar
+	time.Sleep(20 * time.Millisecond)
+
+	mock := repository.NewMockRepository()
+	repo := events.NewPublishingRepository(mock, hub)
+	authConfig := config.DefaultAuthConfig()
+	authConfig.BootstrapKey = bootstrapKey
+	tokens := auth.NewTokenIssuer("secreto de prueba", time.Minute)
+	authenticator := middleware.NewAuthenticator(mock, mock, mock, tokens, nil, authConfig)
+	server := NewServer(repo, mock, authenticator, config.DefaultItemRules(), hub)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+		cancel()
+	})
+	return conn, repo, mock, tokens
+}
+
+// withKey agrega la API key a la metadata de la llamada
+func withKey(ctx context.Context, key string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, constants.APIKeyHeader, key)
+}
+
+func TestCreateAndGet(t *testing.T) {
+	conn, repo := setupGRPC(t)
+	client := todov1.NewTodoServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(withKey(context.Background(), bootstrapKey), middleware.ActorHeader, "ana")
+
+	due := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+	created, err := client.Create(ctx, &todov1.CreateRequest{Item: &todov1.TodoItem{
+		Title: "Task 1",
+		State: constants.StatePending,
+		Tags:  []string{"Backend"},
+		DueAt: timestamppb.New(due),
+	}})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "1", created.Item.Id)
+	assert.Equal(t, constants.PriorityMedium, created.Item.Priority)
+	assert.Equal(t, []string{"backend"}, created.Item.Tags)
+	assert.NotEmpty(t, created.Item.AllowedTransitions)
+
+	got, err := client.Get(ctx, &todov1.GetRequest{Id: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Task 1", got.Item.Title)
+	assert.True(t, due.Equal(got.Item.DueAt.AsTime()))
+
+	history, _, _ := repo.GetHistory(1, 1, 10)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, "ana", history[0].Actor)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	conn, _ := setupGRPC(t)
+	client := todov1.NewTodoServiceClient(conn)
+	ctx := withKey(context.Background(), bootstrapKey)
+
+	_, err := client.Create(ctx, &todov1.CreateRequest{Item: &todov1.TodoItem{State: constants.StatePending}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Get(ctx, &todov1.GetRequest{Id: "abc"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Get(ctx, &todov1.GetRequest{Id: "99"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, constants.ItemNoEncontrado, status.Convert(err).Message())
+
+	_, err = client.Delete(ctx, &todov1.DeleteRequest{Id: "99"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	parent := "99"
+	_, err = client.Create(ctx, &todov1.CreateRequest{Item: &todov1.TodoItem{Title: "Sub", State: constants.StatePending, ParentId: &parent}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUpdateAppliesWorkflow(t *testing.T) {
+	conn, repo := setupGRPC(t)
+	client := todov1.NewTodoServiceClient(conn)
+	ctx := withKey(context.Background(), bootstrapKey)
+	repo.Create(models.TodoItem{Title: "Task", State: constants.StateCompleted, Priority: constants.PriorityLow})
+
+	updated, err := client.Update(ctx, &todov1.UpdateRequest{Item: &todov1.TodoItem{Id: "1", Title: "Task 2", State: constants.StateCompleted}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Task 2", updated.Item.Title)
+	}
+
+	// El flujo por defecto solo permite reabrir un item completado pasándolo a in_progress
+	_, err = client.Update(ctx, &todov1.UpdateRequest{Item: &todov1.TodoItem{Id: "1", Title: "Task 2", State: constants.StatePending}})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, constants.TransicionInvalida, status.Convert(err).Message())
+}
+
+func TestListWithFiltersAndPagination(t *testing.T) {
+	conn, repo := setupGRPC(t)
+	client := todov1.NewTodoServiceClient(conn)
+	ctx := withKey(context.Background(), bootstrapKey)
+	for _, title := range []string{"A", "B", "C"} {
+		repo.Create(models.TodoItem{Title: title, State: constants.StatePending, Priority: constants.PriorityHigh})
+	}
+	repo.Create(models.TodoItem{Title: "D", State: constants.StatePending, Priority: constants.PriorityLow})
+
+	list, err := client.List(ctx, &todov1.ListRequest{Priorities: []string{constants.PriorityHigh}, Sort: "-title", Page: 1, PageSize: 2})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int32(3), list.Total)
+		if assert.Len(t, list.Items, 2) {
+			assert.Equal(t, "C", list.Items[0].Title)
+			assert.Equal(t, "B", list.Items[1].Title)
+		}
+	}
+
+	_, err = client.List(ctx, &todov1.ListRequest{Priorities: []string{"altísima"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.List(ctx, &todov1.ListRequest{PageSize: constants.MaxPageSize + 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWatch(t *testing.T) {
+	conn, repo := setupGRPC(t)
+	client := todov1.NewTodoServiceClient(conn)
+	ctx, cancel := context.WithTimeout(withKey(context.Background(), bootstrapKey), 2*time.Second)
+	defer cancel()
+
+	repo.Create(models.TodoItem{Title: "Antes", State: constants.StatePending})
+
+	stream, err := client.Watch(ctx, &todov1.WatchRequest{States: []string{constants.StatePending}})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	// Esperamos a que el servidor registre la suscripción
+	time.Sleep(50 * time.Millisecond)
+	repo.Create(models.TodoItem{Title: "Completada", State: constants.StateCompleted})
+	repo.Create(models.TodoItem{Title: "Después", State: constants.StatePending})
+
+	event, err := stream.Recv()
+	if assert.NoError(t, err) {
+		assert.Equal(t, constants.WebhookItemCreated, event.Type)
+		assert.Equal(t, "Después", event.Item.Title)
+		assert.Equal(t, uint64(3), event.EventId)
+	}
+
+	// Retomando desde el primer evento se reciben los posteriores que siguen en el buffer
+	replay, err := client.Watch(ctx, &todov1.WatchRequest{LastEventId: 1})
+	if assert.NoError(t, err) {
+		event, err = replay.Recv()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Completada", event.Item.Title)
+		}
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	conn, _ := setupGRPC(t)
+
+	response, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: todov1.TodoService_ServiceDesc.ServiceName,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	conn, repo, mock, tokens := setupGRPCAuth(t)
+	client := todov1.NewTodoServiceClient(conn)
+	ctx := context.Background()
+	repo.Create(models.TodoItem{Title: "Tarea", State: constants.StatePending})
+
+	// Sin credenciales o con una key inválida no hay acceso, tampoco a Watch
+	_, err := client.Get(ctx, &todov1.GetRequest{Id: "1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.List(withKey(ctx, "inventada"), &todov1.ListRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	stream, err := client.Watch(ctx, &todov1.WatchRequest{})
+	if assert.NoError(t, err) {
+		_, err = stream.Recv()
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+
+	// Los métodos exigen el scope de su ruta REST: items:read para leer
+	reader := "clave-de-lectura"
+	mock.CreateAPIKey(models.APIKey{Name: "lectura", Scopes: []string{constants.ScopeItemsRead}, Hash: auth.HashToken(reader)})
+	_, err = client.List(withKey(ctx, reader), &todov1.ListRequest{})
+	assert.NoError(t, err)
+	_, err = client.Create(withKey(ctx, reader), &todov1.CreateRequest{Item: &todov1.TodoItem{Title: "Otra"}})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Con el token de acceso de un usuario, que queda como actor y creador
+	user, _ := mock.CreateUser(models.User{Email: "ana@example.com", Name: "Ana", Role: constants.RoleMember})
+	token, _ := tokens.Issue(user)
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	_, err = client.Create(userCtx, &todov1.CreateRequest{Item: &todov1.TodoItem{Title: "De Ana"}})
+	assert.NoError(t, err)
+	if item, err := repo.Get(2); assert.NoError(t, err) && assert.NotNil(t, item.CreatedBy) {
+		assert.Equal(t, user.ID, *item.CreatedBy)
+	}
+	history, _, _ := repo.GetHistory(2, 1, 10)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, "ana@example.com", history[0].Actor)
+	}
+	// Un member no puede modificar el item de otro usuario
+	other, _ := mock.CreateUser(models.User{Email: "beto@example.com", Name: "Beto", Role: constants.RoleMember})
+	otherToken, _ := tokens.Issue(other)
+	otherCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+otherToken)
+	_, err = client.Update(otherCtx, &todov1.UpdateRequest{Item: &todov1.TodoItem{Id: "2", Title: "De Beto", State: constants.StatePending}})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.Delete(otherCtx, &todov1.DeleteRequest{Id: "2"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.Get(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer otro"), &todov1.GetRequest{Id: "1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// x-tenant debe ser el tenant de las credenciales
+	mock.AddTenant(models.Tenant{Slug: "acme", Name: "Acme"})
+	_, err = client.Get(metadata.AppendToOutgoingContext(userCtx, "x-tenant", "acme"), &todov1.GetRequest{Id: "1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.Get(metadata.AppendToOutgoingContext(userCtx, "x-tenant", "initech"), &todov1.GetRequest{Id: "1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.Get(metadata.AppendToOutgoingContext(userCtx, "x-tenant", "default"), &todov1.GetRequest{Id: "1"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	repo repository.IAPIKeyRepository
+}
+
+func NewAPIKeyHandler(repo repository.IAPIKeyRepository) *APIKeyHandler {
+	return &APIKeyHandler{
+		repo: repo,
+	}
+}
+
+// repoFor devuelve el repositorio limitado a las API keys del tenant de la petición
+func (h *APIKeyHandler) repoFor(c *gin.Context) repository.IAPIKeyRepository {
+	return h.repo.APIKeysForTenant(middleware.TenantID(c))
+}
+
+// apiKeyRequest es el cuerpo de POST /api-keys
+type apiKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// respondAPIKeyError responde según el tipo de error devuelto por el repositorio
+func respondAPIKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(constants.StatusNotFound, gin.H{
+			"error": constants.APIKeyNoEncontrada,
+		})
+	case errors.Is(err, repository.ErrAPIKeyRevoked):
+		c.JSON(constants.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(constants.StatusInternalServerError, gin.H{
+			"error":   constants.ErrorBaseDatos,
+			"details": err.Error(),
+		})
+	}
+}
+
+// apiKeyID lee el parámetro :id; responde 400 y devuelve false si no es válido
+func apiKeyID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.IDAPIKeyInvalido,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.repoFor(c).GetAllAPIKeys()
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.APIKeysObtenidas,
+		"data":    keys,
+	})
+}
+
+func (h *APIKeyHandler) GetAPIKeyByID(c *gin.Context) {
+	id, ok := apiKeyID(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.repoFor(c).GetAPIKey(id)
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.APIKeyObtenida,
+		"data":    key,
+	})
+}
+
+// CreateAPIKey emite una key con los scopes pedidos. La key en claro solo se
+// devuelve en esta respuesta; después solo se ve su prefijo.
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var body apiKeyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.CuerpoInvalido,
+			"details": err.Error(),
+		})
+		return
+	}
+
+	key := models.APIKey{Name: body.Name, Scopes: body.Scopes}
+	if err := key.Validate(); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	plain, prefix, hash := auth.NewAPIKey()
+	key.Prefix = prefix
+	key.Hash = hash
+
+	createdKey, err := h.repoFor(c).CreateAPIKey(key)
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+	createdKey.Key = plain
+
+	c.JSON(constants.StatusCreated, gin.H{
+		"message": constants.APIKeyCreada,
+		"data":    createdKey,
+	})
+}
+
+// RevokeAPIKey revoca la key; se conserva para auditar su uso
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, ok := apiKeyID(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.repoFor(c).RevokeAPIKey(id)
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.APIKeyRevocada,
+		"data":    key,
+	})
+}
+
+// RotateAPIKey reemplaza la key por una nueva con los mismos scopes; la anterior
+// deja de funcionar. La nueva key en claro solo se devuelve en esta respuesta.
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	id, ok := apiKeyID(c)
+	if !ok {
+		return
+	}
+
+	plain, prefix, hash := auth.NewAPIKey()
+	key, err := h.repoFor(c).RotateAPIKey(id, prefix, hash)
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+	key.Key = plain
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.APIKeyRotada,
+		"data":    key,
+	})
+}
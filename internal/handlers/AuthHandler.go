@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPasswordBytes es el máximo que admite bcrypt; lo que sigue se ignoraría
+const maxPasswordBytes = 72
+
+type AuthHandler struct {
+	repo       repository.IUserRepository
+	tenants    repository.ITenantRepository
+	tokens     *auth.TokenIssuer
+	refreshTTL time.Duration
+}
+
+func NewAuthHandler(repo repository.IUserRepository, tenants repository.ITenantRepository, tokens *auth.TokenIssuer, refreshTTL time.Duration) *AuthHandler {
+	return &AuthHandler{
+		repo:       repo,
+		tenants:    tenants,
+		tokens:     tokens,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// registerRequest es el cuerpo de POST /auth/register
+type registerRequest struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// loginRequest es el cuerpo de POST /auth/login
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// refreshRequest es el cuerpo de POST /auth/refresh y, opcional, de POST /auth/logout
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// tokenResponse son los tokens de una sesión
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func respondUserError(c *gin.Context, err error) {
+	c.JSON(constants.StatusInternalServerError, gin.H{
+		"error":   constants.ErrorBaseDatos,
+		"details": err.Error(),
+	})
+}
+
+// bindAuthBody lee el cuerpo JSON; responde 400 y devuelve false si no es válido
+func bindAuthBody(c *gin.Context, body any) bool {
+	if err := c.ShouldBindJSON(body); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.CuerpoInvalido,
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// issueTokens emite un token de acceso y un refresh token para el usuario. Con
+// previous no vacío rota ese refresh token en lugar de crear una sesión nueva.
+func (h *AuthHandler) issueTokens(user models.User, previous string) (tokenResponse, error) {
+	accessToken, err := h.tokens.Issue(user)
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	refreshToken, hash := auth.NewRefreshToken()
+	next := models.RefreshToken{
+		UserID:    user.ID,
+		Hash:      hash,
+		ExpiresAt: time.Now().Add(h.refreshTTL).UTC().Format(time.RFC3339),
+	}
+	if previous != "" {
+		err = h.repo.RotateRefreshToken(previous, next)
+	} else {
+		err = h.repo.CreateRefreshToken(next)
+	}
+	if err != nil {
+		return tokenResponse{}, err
+	}
+
+	return tokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    constants.TokenTypeBearer,
+		ExpiresIn:    int(h.tokens.TTL().Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// Register crea un usuario con email y contraseña en el tenant que pide la
+// petición (o el tenant por defecto); responde 403 si el tenant no admite el
+// registro (ver models.Tenant.AllowRegistration)
+func (h *AuthHandler) Register(c *gin.Context) {
+	var body registerRequest
+	if !bindAuthBody(c, &body) {
+		return
+	}
+
+	tenant, ok := middleware.RequestedTenant(c)
+	if !ok {
+		var err error
+		if tenant, err = h.tenants.GetTenant(constants.DefaultTenantID); err != nil {
+			respondUserError(c, err)
+			return
+		}
+	}
+	if !tenant.AllowRegistration {
+		c.JSON(constants.StatusForbidden, gin.H{
+			"error":   constants.RegistroNoPermitido,
+			"details": tenant.Slug,
+		})
+		return
+	}
+
+	user := models.User{TenantID: tenant.ID, Email: body.Email, Name: body.Name}
+	if err := user.Validate(); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if len(body.Password) < constants.MinPasswordLength {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.ContrasenaCorta,
+		})
+		return
+	}
+	if len(body.Password) > maxPasswordBytes {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.ContrasenaLarga,
+		})
+		return
+	}
+
+	hash, err := auth.HashPassword(body.Password)
+	if err != nil {
+		c.JSON(constants.StatusInternalServerError, gin.H{
+			"error":   constants.ErrorInterno,
+			"details": err.Error(),
+		})
+		return
+	}
+	user.PasswordHash = hash
+
+	createdUser, err := h.repo.CreateUser(user)
+	if errors.Is(err, repository.ErrDuplicateEmail) {
+		c.JSON(constants.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusCreated, gin.H{
+		"message": constants.UsuarioRegistrado,
+		"data":    createdUser,
+	})
+}
+
+// Login valida email y contraseña y abre una sesión: un token de acceso de
+// corta duración y un refresh token para renovarlo
+func (h *AuthHandler) Login(c *gin.Context) {
+	var body loginRequest
+	if !bindAuthBody(c, &body) {
+		return
+	}
+
+	user, err := h.repo.GetUserByEmail(strings.ToLower(strings.TrimSpace(body.Email)))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondUserError(c, err)
+		return
+	}
+	// Con un usuario inexistente PasswordHash está vacío y la comparación falla
+	// igual, en el mismo tiempo. Un usuario de otro tenant que el pedido tampoco
+	// puede iniciar sesión, sin revelar que el email existe.
+	valid := auth.CheckPassword(user.PasswordHash, body.Password)
+	if requested, ok := middleware.RequestedTenant(c); ok && requested.ID != user.TenantID {
+		valid = false
+	}
+	if !valid {
+		c.JSON(constants.StatusUnauthorized, gin.H{
+			"error": constants.CredencialesInvalidas,
+		})
+		return
+	}
+
+	tokens, err := h.issueTokens(user, "")
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.SesionIniciada,
+		"data":    tokens,
+	})
+}
+
+// Refresh cambia un refresh token por tokens nuevos; el refresh token usado se
+// revoca. Presentar uno ya usado indica que pudo haber sido robado, así que se
+// revocan todas las sesiones del usuario.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var body refreshRequest
+	if !bindAuthBody(c, &body) {
+		return
+	}
+
+	hash := auth.HashToken(body.RefreshToken)
+	token, err := h.repo.GetRefreshToken(hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondInvalidRefreshToken(c)
+		return
+	}
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+	if token.Revoked() {
+		if err := h.repo.RevokeUserRefreshTokens(token.UserID); err != nil {
+			respondUserError(c, err)
+			return
+		}
+		respondInvalidRefreshToken(c)
+		return
+	}
+	if expiresAt, err := models.ParseTimestamp(token.ExpiresAt); err != nil || time.Now().After(expiresAt) {
+		respondInvalidRefreshToken(c)
+		return
+	}
+
+	user, err := h.userByID(token.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondInvalidRefreshToken(c)
+		return
+	}
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	tokens, err := h.issueTokens(user, hash)
+	if errors.Is(err, repository.ErrTokenReused) {
+		// Otra petición usó el mismo refresh token al mismo tiempo
+		respondInvalidRefreshToken(c)
+		return
+	}
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.TokenRenovado,
+		"data":    tokens,
+	})
+}
+
+func respondInvalidRefreshToken(c *gin.Context) {
+	c.JSON(constants.StatusUnauthorized, gin.H{
+		"error": constants.RefreshTokenInvalido,
+	})
+}
+
+func (h *AuthHandler) userByID(id string) (models.User, error) {
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		return models.User{}, sql.ErrNoRows
+	}
+	return h.repo.GetUser(userID)
+}
+
+// Logout revoca el token de acceso de la petición y, si se envía, el refresh token
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, ok := sessionClaims(c)
+	if !ok {
+		return
+	}
+
+	// El cuerpo es opcional
+	var body refreshRequest
+	if err := c.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.CuerpoInvalido,
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.repo.RevokeAccessToken(claims.ID, claims.ExpiresAt.Time); err != nil {
+		respondUserError(c, err)
+		return
+	}
+	if body.RefreshToken != "" {
+		if err := h.repo.RevokeRefreshToken(auth.HashToken(body.RefreshToken)); err != nil {
+			respondUserError(c, err)
+			return
+		}
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.SesionCerrada,
+	})
+}
+
+// Me devuelve el usuario de la sesión
+func (h *AuthHandler) Me(c *gin.Context) {
+	user, ok := middleware.CurrentUser(c)
+	if !ok {
+		respondUserRequired(c)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.UsuarioObtenido,
+		"data":    user,
+	})
+}
+
+// sessionClaims devuelve los claims del token de acceso de la petición; responde
+// 401 y devuelve false si no se autenticó como usuario (por ejemplo con una API key)
+func sessionClaims(c *gin.Context) (*auth.Claims, bool) {
+	value, exists := c.Get(middleware.ClaimsKey)
+	claims, ok := value.(*auth.Claims)
+	if !exists || !ok {
+		respondUserRequired(c)
+		return nil, false
+	}
+	return claims, true
+}
+
+func respondUserRequired(c *gin.Context) {
+	c.JSON(constants.StatusUnauthorized, gin.H{
+		"error": constants.SesionDeUsuarioRequerida,
+	})
+}
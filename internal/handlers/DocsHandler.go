@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// swaggerPage es la página de Swagger UI apuntando a /openapi.json; los
+// recursos (js, css) se sirven desde los archivos embebidos de swaggo/files
+const swaggerPage = `<!DOCTYPE html>
+<html lang="es">
+  <head>
+    <meta charset="UTF-8">
+    <title>devops_todo_go API</title>
+    <link rel="stylesheet" type="text/css" href="./swagger-ui.css" />
+    <link rel="icon" type="image/png" href="./favicon-32x32.png" sizes="32x32" />
+  </head>
+  <body>
+    <div id="swagger-ui"></div>
+    <script src="./swagger-ui-bundle.js" charset="UTF-8"></script>
+    <script src="./swagger-ui-standalone-preset.js" charset="UTF-8"></script>
+    <script>
+      window.onload = function () {
+        window.ui = SwaggerUIBundle({
+          url: "/openapi.json",
+          dom_id: "#swagger-ui",
+          deepLinking: true,
+          presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+          layout: "StandaloneLayout"
+        });
+      };
+    </script>
+  </body>
+</html>
+`
+
+type DocsHandler struct {
+	spec   []byte
+	assets http.Handler
+}
+
+func NewDocsHandler(spec []byte) *DocsHandler {
+	return &DocsHandler{
+		spec:   spec,
+		assets: http.StripPrefix("/docs", http.FileServer(http.FS(swaggerFiles.FS))),
+	}
+}
+
+// GetSpec devuelve la especificación OpenAPI
+func (h *DocsHandler) GetSpec(c *gin.Context) {
+	c.Data(constants.StatusOK, "application/json; charset=utf-8", h.spec)
+}
+
+// SwaggerUI sirve la interfaz de Swagger UI en /docs/ y sus recursos en /docs/*filepath
+func (h *DocsHandler) SwaggerUI(c *gin.Context) {
+	switch c.Param("filepath") {
+	case "/", "/index.html":
+		c.Data(constants.StatusOK, "text/html; charset=utf-8", []byte(swaggerPage))
+	default:
+		h.assets.ServeHTTP(c.Writer, c.Request)
+	}
+}
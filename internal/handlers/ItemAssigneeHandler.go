@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// assignItemRequest es el cuerpo de PUT /items/:id/assignee
+type assignItemRequest struct {
+	AssigneeID string `json:"assignee_id" binding:"required"`
+}
+
+func (h *ItemHandler) AssignItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	var body assignItemRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+	if _, err := strconv.Atoi(body.AssigneeID); err != nil {
+		respondProblem(c, Problem{
+			Status: constants.StatusBadRequest,
+			Code:   constants.ErrCodeInvalidID,
+			Title:  constants.IDUsuarioInvalido,
+		})
+		return
+	}
+
+	h.assign(c, id, &body.AssigneeID, constants.ItemAsignado)
+}
+
+func (h *ItemHandler) UnassignItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	h.assign(c, id, nil, constants.ItemDesasignado)
+}
+
+// assign cambia el responsable del item y responde el item actualizado
+func (h *ItemHandler) assign(c *gin.Context, id int, assigneeID *string, message string) {
+	if err := h.repoFor(c).AssignItem(id, assigneeID); err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	item, err := h.repoFor(c).Get(id)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+	h.decorate(&item, time.Now())
+
+	respondData(c, constants.StatusOK, message, item, responseMeta{})
+}
+
+// GetMyItems lista los items que el usuario creó o tiene asignados, con los
+// mismos filtros que GET /items
+func (h *ItemHandler) GetMyItems(c *gin.Context) {
+	user, ok := middleware.CurrentUser(c)
+	if !ok {
+		respondItemUserRequired(c)
+		return
+	}
+
+	filter, err := parseItemFilter(c)
+	if err != nil {
+		respondInvalidFilter(c, err)
+		return
+	}
+	filter.InvolvedUserID = &user.ID
+
+	h.listItems(c, filter)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// errBlockedItem es el error de las reglas de dependencias (ver config.DependencyRules)
+var errBlockedItem = errors.New(constants.ItemBloqueado)
+
+// checkBlockers aplica la regla de dependencias cuando el item pasa a in_progress o
+// completed. En modo warn devuelve el mensaje de advertencia en lugar de un error.
+func (h *ItemHandler) checkBlockers(repo repository.IRepository, current models.TodoItem, newState string) (string, error) {
+	// Un modo vacío (reglas sin configurar) equivale a off
+	enforcement := h.rules.Dependencies.Enforcement
+	if enforcement == config.EnforcementOff || enforcement == "" {
+		return "", nil
+	}
+	if newState != constants.StateInProgress && newState != constants.StateCompleted {
+		return "", nil
+	}
+
+	itemID, err := strconv.Atoi(current.ID)
+	if err != nil {
+		return "", err
+	}
+	blockers, err := repo.GetBlockers(itemID, false)
+	if err != nil {
+		return "", err
+	}
+	for _, blocker := range blockers {
+		if blocker.State != constants.StateCompleted {
+			if enforcement == config.EnforcementWarn {
+				return constants.ItemBloqueado, nil
+			}
+			return "", errBlockedItem
+		}
+	}
+	return "", nil
+}
+
+// addDependencyRequest es el cuerpo de POST /items/:id/dependencies
+type addDependencyRequest struct {
+	BlockerID string `json:"blocker_id"`
+}
+
+// AddDependency registra que el item está bloqueado por blocker_id
+func (h *ItemHandler) AddDependency(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	var body addDependencyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+	blockerID, err := strconv.Atoi(body.BlockerID)
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	if err := h.repoFor(c).AddDependency(id, blockerID); err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	item, err := h.repoFor(c).Get(id)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+	h.decorate(&item, time.Now())
+
+	respondData(c, constants.StatusCreated, constants.DependenciaAgregada, item, responseMeta{})
+}
+
+func (h *ItemHandler) RemoveDependency(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+	blockerID, err := strconv.Atoi(c.Param("blocker_id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	if err := h.repoFor(c).RemoveDependency(id, blockerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondProblem(c, Problem{
+				Status: constants.StatusNotFound,
+				Code:   constants.ErrCodeDependencyNotFound,
+				Title:  constants.DependenciaNoEncontrada,
+			})
+			return
+		}
+		respondItemError(c, err)
+		return
+	}
+
+	respondMessage(c, constants.DependenciaEliminada)
+}
+
+// GetBlockers devuelve el conjunto transitivo de bloqueantes del item.
+// Con ?open=true solo incluye los que no están completados.
+func (h *ItemHandler) GetBlockers(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+	onlyOpen, _ := strconv.ParseBool(c.Query("open"))
+
+	blockers, err := h.repoFor(c).GetBlockers(id, true)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	now := time.Now()
+	result := blockers[:0]
+	for _, blocker := range blockers {
+		if onlyOpen && blocker.State == constants.StateCompleted {
+			continue
+		}
+		h.decorate(&blocker, now)
+		result = append(result, blocker)
+	}
+
+	respondData(c, constants.StatusOK, constants.BloqueantesObtenidos, result, responseMeta{})
+}
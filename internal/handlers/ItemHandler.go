@@ -1,167 +1,398 @@
 package handlers
 
 import (
-	"database/sql"
+	"errors"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/i18n"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
 type ItemHandler struct {
-	repo repository.IRepository
+	repo  repository.IRepository
+	rules config.ItemRules
 }
 
-func NewItemHandler(repo repository.IRepository) *ItemHandler {
+func NewItemHandler(repo repository.IRepository, rules config.ItemRules) *ItemHandler {
 	return &ItemHandler{
-		repo: repo,
+		repo:  repo,
+		rules: rules,
 	}
 }
 
+// decorate completa los campos calculados que dependen del momento y de las reglas
+func (h *ItemHandler) decorate(item *models.TodoItem, now time.Time) {
+	item.RefreshOverdue(now)
+	item.AllowedTransitions = h.rules.Workflow.NextStates(item.State)
+}
+
+// parseItemFilter arma el filtro de listado a partir de los query params
+// soportados: project=<id>, overdue=true|false, due_before=<RFC3339>, priority=high,urgent
+// tag=a,b con tag_mode=any|all (por defecto any), assignee=me|none|<id>,
+// created_by=me|<id> y sort=<campo> (con prefijo "-" para orden descendente)
+func parseItemFilter(c *gin.Context) (models.ItemFilter, error) {
+	var filter models.ItemFilter
+
+	if value := c.Query("project"); value != "" {
+		if _, err := strconv.Atoi(value); err != nil {
+			return filter, errors.New(constants.IDProyectoInvalido)
+		}
+		filter.ProjectID = &value
+	}
+
+	if value := c.Query("overdue"); value != "" {
+		overdue, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Overdue = &overdue
+	}
+
+	if value := c.Query("due_before"); value != "" {
+		dueBefore, err := models.ParseTimestamp(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.DueBefore = &dueBefore
+	}
+
+	if value := c.Query("priority"); value != "" {
+		for _, priority := range strings.Split(value, ",") {
+			priority = strings.TrimSpace(priority)
+			if !constants.IsValidPriority(priority) {
+				return filter, errors.New(constants.PrioridadInvalida)
+			}
+			filter.Priorities = append(filter.Priorities, priority)
+		}
+	}
+
+	if value := c.Query("tag"); value != "" {
+		for _, tag := range strings.Split(value, ",") {
+			tag = models.NormalizeTagName(tag)
+			if err := models.ValidateTagName(tag); err != nil {
+				return filter, err
+			}
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	if value := c.Query("assignee"); value == constants.FilterNone {
+		filter.Unassigned = true
+	} else if value != "" {
+		assigneeID, err := userFilterValue(c, "assignee", value)
+		if err != nil {
+			return filter, err
+		}
+		filter.AssigneeID = &assigneeID
+	}
+
+	if value := c.Query("created_by"); value != "" {
+		createdBy, err := userFilterValue(c, "created_by", value)
+		if err != nil {
+			return filter, err
+		}
+		filter.CreatedBy = &createdBy
+	}
+
+	switch mode := c.DefaultQuery("tag_mode", constants.TagMatchAny); mode {
+	case constants.TagMatchAny:
+	case constants.TagMatchAll:
+		filter.TagMatchAll = true
+	default:
+		return filter, i18n.Errorf(constants.ModoEtiquetasInvalido, mode)
+	}
+
+	if value := c.Query("sort"); value != "" {
+		field := strings.TrimPrefix(value, "-")
+		if !models.IsValidSortField(field) {
+			return filter, i18n.Errorf(constants.OrdenamientoInvalido, field)
+		}
+		filter.SortBy = field
+		filter.SortDesc = strings.HasPrefix(value, "-")
+	}
+
+	return filter, nil
+}
+
+// userFilterValue valida el ID de usuario de un filtro; me es el usuario de la petición
+func userFilterValue(c *gin.Context, param string, value string) (string, error) {
+	if value == constants.FilterMe {
+		user, ok := middleware.CurrentUser(c)
+		if !ok {
+			return "", i18n.Errorf(constants.FiltroMeSinUsuario, param)
+		}
+		return user.ID, nil
+	}
+	if _, err := strconv.Atoi(value); err != nil {
+		return "", errors.New(constants.IDUsuarioInvalido)
+	}
+	return value, nil
+}
+
 func (h *ItemHandler) GetItems(c *gin.Context) {
-	items, err := h.repo.GetAll()
+	filter, err := parseItemFilter(c)
 	if err != nil {
-		c.JSON(constants.StatusInternalServerError, gin.H{
-			"error":   constants.ErrorBaseDatos,
-			"details": err.Error(),
-		})
+		respondInvalidFilter(c, err)
 		return
 	}
 
-	c.JSON(constants.StatusOK, gin.H{
-		"message": constants.ItemsObtenidos,
-		"data":    items,
-	})
+	h.listItems(c, filter)
+}
+
+// listItems responde los items que cumplen el filtro
+func (h *ItemHandler) listItems(c *gin.Context, filter models.ItemFilter) {
+	items, err := h.repoFor(c).GetAll(filter)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	now := time.Now()
+	for i := range items {
+		h.decorate(&items[i], now)
+	}
+
+	respondData(c, constants.StatusOK, constants.ItemsObtenidos, items, responseMeta{})
 }
 
 func (h *ItemHandler) GetItemByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(constants.StatusBadRequest, gin.H{
-			"error": constants.IDInvalido,
-		})
+		respondInvalidID(c)
 		return
 	}
 
-	item, err := h.repo.Get(id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(constants.StatusNotFound, gin.H{
-				"error": constants.ItemNoEncontrado,
+	// ?as_of=<RFC3339> devuelve el item como estaba en ese momento
+	now := time.Now()
+	repo := h.repoFor(c)
+	get := repo.Get
+	if value := c.Query("as_of"); value != "" {
+		asOf, err := models.ParseTimestamp(value)
+		if err != nil {
+			respondProblem(c, Problem{
+				Status: constants.StatusBadRequest,
+				Code:   constants.ErrCodeInvalidAsOf,
+				Title:  constants.AsOfInvalido,
+				detail: err,
 			})
 			return
 		}
-		c.JSON(constants.StatusInternalServerError, gin.H{
-			"error":   constants.ErrorBaseDatos,
-			"details": err.Error(),
-		})
+		now = asOf
+		get = func(id int) (models.TodoItem, error) {
+			return repo.GetAsOf(id, asOf)
+		}
+	}
+
+	item, err := get(id)
+	if err != nil {
+		respondItemError(c, err)
 		return
 	}
 
-	c.JSON(constants.StatusOK, gin.H{
-		"message": constants.ItemObtenido,
-		"data":    item,
-	})
+	h.decorate(&item, now)
+
+	respondData(c, constants.StatusOK, constants.ItemObtenido, item, responseMeta{})
 }
 
 func (h *ItemHandler) CreateItem(c *gin.Context) {
 	var item models.TodoItem
 	if err := c.ShouldBindJSON(&item); err != nil {
-		c.JSON(constants.StatusBadRequest, gin.H{
-			"error":   constants.CuerpoInvalido,
-			"details": err.Error(),
-		})
+		respondInvalidBody(c, err)
 		return
 	}
 
-	// Validar el item usando el método Validate
-	if err := item.Validate(); err != nil {
-		c.JSON(constants.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+	h.createItem(c, item)
+}
+
+// ValidationError es un error en los datos del item, que se responde como petición inválida
+type ValidationError struct {
+	Err error
+}
+
+func (e ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Create completa, valida y guarda un item nuevo con las reglas de negocio
+// configuradas, y lo devuelve con los campos calculados. El creador es el
+// usuario de meta. Lo comparten la API REST y la gRPC.
+func (h *ItemHandler) Create(meta models.RequestMeta, item models.TodoItem) (models.TodoItem, error) {
+	item.CreatedBy = nil
+	if meta.UserID != "" {
+		item.CreatedBy = &meta.UserID
+	}
+	item.ApplyDefaults()
+	item.NormalizeTags()
+	// Sin estado, el item nace en el estado inicial del flujo
+	if item.State == "" {
+		item.State = h.rules.Workflow.Initial
+	}
+
+	// Validar el item usando el método Validate, con los estados del flujo configurado
+	if err := item.ValidateWithStates(h.rules.Workflow.States); err != nil {
+		return models.TodoItem{}, ValidationError{Err: err}
+	}
+
+	repo := h.repo.WithMeta(meta)
+	if item.ParentID != nil {
+		if err := h.validateParent(repo, *item.ParentID); err != nil {
+			return models.TodoItem{}, err
+		}
+	}
+
+	createdItem, err := repo.Create(item)
+	if err != nil {
+		return models.TodoItem{}, err
+	}
+
+	h.decorate(&createdItem, time.Now())
+	return createdItem, nil
+}
+
+// createItem guarda un item nuevo ya leído del cuerpo.
+// Lo comparten POST /items y POST /projects/:id/items.
+func (h *ItemHandler) createItem(c *gin.Context, item models.TodoItem) {
+	// En v2 los items nacen en el estado inicial y los demás se alcanzan con
+	// transiciones; v1 mantiene su contrato y acepta cualquier estado del flujo
+	if apiVersion(c) == constants.APIVersion2 && h.rules.Workflow.IsValidState(item.State) && item.State != h.rules.Workflow.Initial {
+		respondItemError(c, errNotInitialState)
 		return
 	}
 
-	createdItem, err := h.repo.Create(item)
+	createdItem, err := h.Create(requestMeta(c), item)
 	if err != nil {
-		c.JSON(constants.StatusInternalServerError, gin.H{
-			"error":   constants.ErrorBaseDatos,
-			"details": err.Error(),
-		})
+		respondItemError(c, err)
 		return
 	}
 
-	c.JSON(constants.StatusCreated, gin.H{
-		"message": constants.ItemCreado,
-		"data":    createdItem,
-	})
+	respondData(c, constants.StatusCreated, constants.ItemCreado, createdItem, responseMeta{})
+}
+
+// Update valida y guarda los cambios de un item (item.ID indica cuál) con las
+// reglas de negocio configuradas. Devuelve el item actualizado con los campos
+// calculados y, en modo warn de dependencias, la advertencia de bloqueantes abiertos.
+func (h *ItemHandler) Update(meta models.RequestMeta, item models.TodoItem) (models.TodoItem, string, error) {
+	id, err := strconv.Atoi(item.ID)
+	if err != nil {
+		return models.TodoItem{}, "", ValidationError{Err: errors.New(constants.IDInvalido)}
+	}
+	item.NormalizeTags()
+
+	// Validar el item usando el método Validate, con los estados del flujo configurado
+	if err := item.ValidateWithStates(h.rules.Workflow.States); err != nil {
+		return models.TodoItem{}, "", ValidationError{Err: err}
+	}
+
+	repo := h.repo.WithMeta(meta)
+	current, err := repo.Get(id)
+	if err != nil {
+		return models.TodoItem{}, "", err
+	}
+
+	warning, err := h.checkStateChange(repo, current, item.State)
+	if err != nil {
+		return models.TodoItem{}, "", err
+	}
+
+	if err := repo.Update(item); err != nil {
+		return models.TodoItem{}, "", err
+	}
+
+	// Releemos el item para devolver también los campos calculados y los que no se enviaron
+	if item, err = repo.Get(id); err != nil {
+		return models.TodoItem{}, "", err
+	}
+	h.decorate(&item, time.Now())
+	return item, warning, nil
 }
 
 func (h *ItemHandler) UpdateItem(c *gin.Context) {
-	id := c.Param("id")
-	if id == "" {
-		c.JSON(constants.StatusBadRequest, gin.H{
-			"error": constants.IDInvalido,
-		})
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
 		return
 	}
 
 	var item models.TodoItem
 	if err := c.ShouldBindJSON(&item); err != nil {
-		c.JSON(constants.StatusBadRequest, gin.H{
-			"error":   constants.CuerpoInvalido,
-			"details": err.Error(),
-		})
+		respondInvalidBody(c, err)
 		return
 	}
 
-	item.ID = id
-
-	// Validar el item usando el método Validate
-	if err := item.Validate(); err != nil {
-		c.JSON(constants.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
-		return
+	item.ID = strconv.Itoa(id)
+	item, warning, err := h.Update(requestMeta(c), item)
+	if errors.Is(err, errInvalidTransition) {
+		// Releemos el item para informar los estados permitidos desde el actual
+		if current, getErr := h.repoFor(c).Get(id); getErr == nil {
+			h.respondStateChangeError(c, current, err)
+			return
+		}
 	}
-
-	err := h.repo.Update(item)
 	if err != nil {
-		c.JSON(constants.StatusInternalServerError, gin.H{
-			"error":   constants.ErrorBaseDatos,
-			"details": err.Error(),
-		})
+		respondItemError(c, err)
 		return
 	}
 
-	c.JSON(constants.StatusOK, gin.H{
-		"message": constants.ItemActualizado,
-		"data":    item,
-	})
+	respondData(c, constants.StatusOK, constants.ItemActualizado, item, responseMeta{Warning: warning})
 }
 
 func (h *ItemHandler) DeleteItem(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(constants.StatusBadRequest, gin.H{
-			"error": constants.IDInvalido,
-		})
+		respondInvalidID(c)
 		return
 	}
 
-	err := h.repo.Delete(id)
+	err := h.repoFor(c).Delete(id)
 	if err != nil {
-		c.JSON(constants.StatusInternalServerError, gin.H{
-			"error":   constants.ErrorBaseDatos,
-			"details": err.Error(),
-		})
+		respondItemError(c, err)
 		return
 	}
 
-	c.JSON(constants.StatusOK, gin.H{
-		"message": constants.ItemEliminado,
-	})
+	respondMessage(c, constants.ItemEliminado)
 }
 
+
+// moveItemRequest es el cuerpo de PUT /items/:id/project; project_id null deja el item sin proyecto
+type moveItemRequest struct {
+	ProjectID *string `json:"project_id"`
+}
+
+func (h *ItemHandler) MoveItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	var body moveItemRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	if err := h.repoFor(c).MoveItem(id, body.ProjectID); err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	item, err := h.repoFor(c).Get(id)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+	h.decorate(&item, time.Now())
+
+	respondData(c, constants.StatusOK, constants.ItemMovido, item, responseMeta{})
+}
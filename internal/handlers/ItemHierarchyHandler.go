@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// errPendingSubtasks es el error de las reglas de subtareas (ver config.HierarchyRules)
+var errPendingSubtasks = errors.New(constants.SubtareasPendientes)
+
+// ancestors devuelve los IDs desde parentID hasta la raíz, incluido parentID
+func (h *ItemHandler) ancestors(repo repository.IRepository, parentID string) ([]string, error) {
+	var chain []string
+	current := &parentID
+	for current != nil && len(chain) < repository.MaxAncestors {
+		id, err := strconv.Atoi(*current)
+		if err != nil {
+			return nil, repository.ErrParentNotFound
+		}
+		item, err := repo.Get(id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrParentNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, item.ID)
+		current = item.ParentID
+	}
+	return chain, nil
+}
+
+// validateParent verifica que un item nuevo colgado de parentID no supere la
+// profundidad máxima configurada. Al mover un item existente lo verifica el
+// repositorio (ver IRepository.SetParent).
+func (h *ItemHandler) validateParent(repo repository.IRepository, parentID string) error {
+	chain, err := h.ancestors(repo, parentID)
+	if err != nil {
+		return err
+	}
+	if h.rules.Hierarchy.MaxDepth > 0 && len(chain)+1 > h.rules.Hierarchy.MaxDepth {
+		return repository.ErrMaxDepth
+	}
+	return nil
+}
+
+// checkSubtasksCompleted devuelve errPendingSubtasks si alguna subtarea directa no está completada
+func (h *ItemHandler) checkSubtasksCompleted(repo repository.IRepository, id string) error {
+	children, err := repo.GetAll(models.ItemFilter{ParentID: &id})
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if child.State != constants.StateCompleted {
+			return errPendingSubtasks
+		}
+	}
+	return nil
+}
+
+// GetChildren lista las subtareas directas; acepta los mismos filtros que GET /items
+func (h *ItemHandler) GetChildren(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	if _, err := h.repoFor(c).Get(id); err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	filter, err := parseItemFilter(c)
+	if err != nil {
+		respondInvalidFilter(c, err)
+		return
+	}
+	parentID := strconv.Itoa(id)
+	filter.ParentID = &parentID
+
+	items, err := h.repoFor(c).GetAll(filter)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	now := time.Now()
+	for i := range items {
+		h.decorate(&items[i], now)
+	}
+
+	respondData(c, constants.StatusOK, constants.SubtareasObtenidas, items, responseMeta{})
+}
+
+// GetSubtree devuelve el item con todas sus subtareas anidadas
+func (h *ItemHandler) GetSubtree(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	root, err := h.repoFor(c).Get(id)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	descendants, err := h.repoFor(c).GetSubtree(id)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	tree := models.BuildTree(root, descendants)
+	now := time.Now()
+	tree.Each(func(item *models.TodoItem) {
+		h.decorate(item, now)
+	})
+
+	respondData(c, constants.StatusOK, constants.ArbolObtenido, tree, responseMeta{})
+}
+
+// setParentRequest es el cuerpo de PUT /items/:id/parent; parent_id null lo convierte en item raíz
+type setParentRequest struct {
+	ParentID *string `json:"parent_id"`
+}
+
+func (h *ItemHandler) SetParent(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	var body setParentRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	if _, err := h.repoFor(c).Get(id); err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	if err := h.repoFor(c).SetParent(id, body.ParentID, h.rules.Hierarchy.MaxDepth); err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	item, err := h.repoFor(c).Get(id)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+	h.decorate(&item, time.Now())
+
+	respondData(c, constants.StatusOK, constants.PadreActualizado, item, responseMeta{})
+}
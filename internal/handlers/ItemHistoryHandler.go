@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/i18n"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// requestMeta arma los datos del autor del cambio para el historial y el
+// tenant de la petición. Si la petición no pasó por middleware.RequestMeta (ej:
+// en los tests) se usa la cabecera X-Actor directamente.
+func requestMeta(c *gin.Context) models.RequestMeta {
+	actor := c.GetString(middleware.ActorKey)
+	if actor == "" {
+		actor = c.GetHeader(middleware.ActorHeader)
+	}
+	meta := models.RequestMeta{
+		Actor:     actor,
+		RequestID: c.GetString(middleware.RequestIDKey),
+		TenantID:  middleware.TenantID(c),
+	}
+	middleware.SetUserAccess(c, &meta)
+	return meta
+}
+
+// repoFor devuelve el repositorio que registra los cambios de esta petición en
+// el historial y se limita a los items de su tenant
+func (h *ItemHandler) repoFor(c *gin.Context) repository.IRepository {
+	return h.repo.WithMeta(requestMeta(c))
+}
+
+// parsePagination lee page (desde 1) y page_size (hasta constants.MaxPageSize)
+func parsePagination(c *gin.Context) (int, int, error) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		return 0, 0, errors.New(constants.PaginaInvalida)
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(constants.DefaultPageSize)))
+	if err != nil || pageSize < 1 || pageSize > constants.MaxPageSize {
+		return 0, 0, i18n.Errorf(constants.PageSizeInvalido, constants.MaxPageSize)
+	}
+	return page, pageSize, nil
+}
+
+// GetHistory devuelve el historial de cambios de un item, del más reciente al más
+// antiguo, paginado con ?page=N&page_size=M
+func (h *ItemHandler) GetHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		respondInvalidPagination(c, err)
+		return
+	}
+
+	events, total, err := h.repoFor(c).GetHistory(id, page, pageSize)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	respondData(c, constants.StatusOK, constants.HistorialObtenido, events, responseMeta{
+		Pagination: &pagination{Page: page, PageSize: pageSize, Total: total},
+	})
+}
+
+// RestoreItem deshace la eliminación de un item
+func (h *ItemHandler) RestoreItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	if err := h.repoFor(c).Restore(id); err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	item, err := h.repoFor(c).Get(id)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+	h.decorate(&item, time.Now())
+
+	respondData(c, constants.StatusOK, constants.ItemRestaurado, item, responseMeta{})
+}
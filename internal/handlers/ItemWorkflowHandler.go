@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// Errores del flujo de estados (ver config.Workflow)
+var (
+	// errInvalidTransition indica que el flujo configurado no permite el cambio de estado
+	errInvalidTransition = errors.New(constants.TransicionInvalida)
+	// errNotInitialState es un item nuevo en un estado distinto del inicial
+	errNotInitialState = errors.New(constants.EstadoNoInicial)
+)
+
+// checkStateChange aplica las reglas de negocio a un cambio de estado: el flujo
+// configurado, las subtareas pendientes y los bloqueantes abiertos. Si el estado no
+// cambia no hay nada que verificar. En modo warn de dependencias devuelve la advertencia.
+func (h *ItemHandler) checkStateChange(repo repository.IRepository, current models.TodoItem, newState string) (string, error) {
+	if current.State == newState {
+		return "", nil
+	}
+
+	if !h.rules.Workflow.CanTransition(current.State, newState) {
+		return "", errInvalidTransition
+	}
+
+	if newState == constants.StateCompleted && h.rules.Hierarchy.BlockParentCompletion {
+		if err := h.checkSubtasksCompleted(repo, current.ID); err != nil {
+			return "", err
+		}
+	}
+
+	return h.checkBlockers(repo, current, newState)
+}
+
+// respondStateChangeError responde los errores de checkStateChange; en una transición
+// inválida incluye los estados permitidos desde el estado actual
+func (h *ItemHandler) respondStateChangeError(c *gin.Context, current models.TodoItem, err error) {
+	if errors.Is(err, errInvalidTransition) {
+		respondProblem(c, Problem{
+			Status:  constants.StatusConflict,
+			Code:    constants.ErrCodeInvalidTransition,
+			Title:   constants.TransicionInvalida,
+			From:    current.State,
+			Allowed: h.rules.Workflow.NextStates(current.State),
+		})
+		return
+	}
+	respondItemError(c, err)
+}
+
+// transitionRequest es el cuerpo de POST /items/:id/transitions
+type transitionRequest struct {
+	To string `json:"to"`
+}
+
+// TransitionItem cambia solo el estado del item, validando el flujo configurado
+func (h *ItemHandler) TransitionItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+
+	var body transitionRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+	if !h.rules.Workflow.IsValidState(body.To) {
+		respondProblem(c, Problem{
+			Status: constants.StatusBadRequest,
+			Code:   constants.ErrCodeInvalidState,
+			Title:  constants.EstadoInvalido,
+			Errors: []models.FieldError{{Field: "to", Code: constants.FieldInvalidState, Message: constants.EstadoInvalido}},
+		})
+		return
+	}
+
+	current, err := h.repoFor(c).Get(id)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	warning, err := h.checkStateChange(h.repoFor(c), current, body.To)
+	if err != nil {
+		h.respondStateChangeError(c, current, err)
+		return
+	}
+
+	item := current
+	item.State = body.To
+	if err := h.repoFor(c).Update(item); err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	if item, err = h.repoFor(c).Get(id); err != nil {
+		respondItemError(c, err)
+		return
+	}
+	h.decorate(&item, time.Now())
+
+	respondData(c, constants.StatusOK, constants.ItemTransicionado, item, responseMeta{Warning: warning})
+}
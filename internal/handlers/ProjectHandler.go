@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectHandler usa el ItemHandler para las rutas anidadas /projects/:id/items,
+// de modo que los items se validen igual que en /items
+type ProjectHandler struct {
+	repo  repository.IProjectRepository
+	items *ItemHandler
+}
+
+func NewProjectHandler(repo repository.IProjectRepository, items *ItemHandler) *ProjectHandler {
+	return &ProjectHandler{
+		repo:  repo,
+		items: items,
+	}
+}
+
+// repoFor devuelve el repositorio limitado a los proyectos del tenant de la petición
+func (h *ProjectHandler) repoFor(c *gin.Context) repository.IProjectRepository {
+	return h.repo.ProjectsForTenant(middleware.TenantID(c))
+}
+
+// respondProjectError responde según el tipo de error devuelto por el repositorio
+func respondProjectError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, repository.ErrProjectNotFound):
+		c.JSON(constants.StatusNotFound, gin.H{
+			"error": constants.ProyectoNoEncontrado,
+		})
+	case errors.Is(err, repository.ErrProjectNotEmpty):
+		c.JSON(constants.StatusConflict, gin.H{
+			"error": constants.ProyectoConItems,
+		})
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(constants.StatusNotFound, gin.H{
+			"error": constants.UsuarioNoEncontrado,
+		})
+	case errors.Is(err, repository.ErrMemberNotFound):
+		c.JSON(constants.StatusNotFound, gin.H{
+			"error": constants.MiembroNoEncontrado,
+		})
+	case errors.Is(err, repository.ErrProjectArchived):
+		c.JSON(constants.StatusConflict, gin.H{
+			"error": constants.ProyectoArchivadoErr,
+		})
+	case errors.Is(err, repository.ErrProjectQuotaExceeded):
+		c.JSON(constants.StatusForbidden, gin.H{
+			"error": constants.CuotaProyectosExcedida,
+		})
+	default:
+		c.JSON(constants.StatusInternalServerError, gin.H{
+			"error":   constants.ErrorBaseDatos,
+			"details": err.Error(),
+		})
+	}
+}
+
+// projectID lee el parámetro :id; responde 400 y devuelve false si no es válido
+func projectID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.IDProyectoInvalido,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// authorizeProject es authorize sobre los recursos del proyecto id
+func authorizeProject(c *gin.Context, permission string, id int) bool {
+	projectID := strconv.Itoa(id)
+	return authorize(c, permission, &projectID)
+}
+
+// bindProject lee y valida el proyecto del cuerpo de la petición
+func bindProject(c *gin.Context) (models.Project, bool) {
+	var project models.Project
+	if err := c.ShouldBindJSON(&project); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.CuerpoInvalido,
+			"details": err.Error(),
+		})
+		return project, false
+	}
+
+	if err := project.Validate(); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return project, false
+	}
+	return project, true
+}
+
+func (h *ProjectHandler) GetProjects(c *gin.Context) {
+	includeArchived, _ := strconv.ParseBool(c.Query("archived"))
+
+	projects, err := h.repoFor(c).GetAllProjects(includeArchived)
+	if err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.ProyectosObtenidos,
+		"data":    projects,
+	})
+}
+
+func (h *ProjectHandler) GetProjectByID(c *gin.Context) {
+	id, ok := projectID(c)
+	if !ok {
+		return
+	}
+
+	project, err := h.repoFor(c).GetProject(id)
+	if err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.ProyectoObtenido,
+		"data":    project,
+	})
+}
+
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	if !authorize(c, constants.PermissionWrite, nil) {
+		return
+	}
+
+	project, ok := bindProject(c)
+	if !ok {
+		return
+	}
+
+	createdProject, err := h.repoFor(c).CreateProject(project)
+	if err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusCreated, gin.H{
+		"message": constants.ProyectoCreado,
+		"data":    createdProject,
+	})
+}
+
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	id, ok := projectID(c)
+	if !ok {
+		return
+	}
+
+	if !authorizeProject(c, constants.PermissionWrite, id) {
+		return
+	}
+
+	project, ok := bindProject(c)
+	if !ok {
+		return
+	}
+	project.ID = strconv.Itoa(id)
+
+	updatedProject, err := h.repoFor(c).UpdateProject(project)
+	if err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.ProyectoActualizado,
+		"data":    updatedProject,
+	})
+}
+
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	id, ok := projectID(c)
+	if !ok {
+		return
+	}
+
+	if !authorizeProject(c, constants.PermissionDelete, id) {
+		return
+	}
+
+	if err := h.repoFor(c).DeleteProject(id); err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.ProyectoEliminado,
+	})
+}
+
+func (h *ProjectHandler) ArchiveProject(c *gin.Context) {
+	h.setArchived(c, true, constants.ProyectoArchivado)
+}
+
+func (h *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	h.setArchived(c, false, constants.ProyectoDesarchivado)
+}
+
+func (h *ProjectHandler) setArchived(c *gin.Context, archived bool, message string) {
+	id, ok := projectID(c)
+	if !ok {
+		return
+	}
+
+	if !authorizeProject(c, constants.PermissionWrite, id) {
+		return
+	}
+
+	project, err := h.repoFor(c).SetProjectArchived(id, archived)
+	if err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": message,
+		"data":    project,
+	})
+}
+
+// GetProjectItems lista los items del proyecto; acepta los mismos filtros que GET /items
+func (h *ProjectHandler) GetProjectItems(c *gin.Context) {
+	id, ok := projectID(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repoFor(c).GetProject(id); err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	filter, err := parseItemFilter(c)
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.FiltroInvalido,
+			"details": err.Error(),
+		})
+		return
+	}
+	projectIDStr := strconv.Itoa(id)
+	filter.ProjectID = &projectIDStr
+
+	items, err := h.items.repoFor(c).GetAll(filter)
+	if err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	now := time.Now()
+	for i := range items {
+		h.items.decorate(&items[i], now)
+	}
+
+	respondData(c, constants.StatusOK, constants.ItemsObtenidos, items, responseMeta{})
+}
+
+// CreateProjectItem crea un item dentro del proyecto indicado en la ruta
+func (h *ProjectHandler) CreateProjectItem(c *gin.Context) {
+	id, ok := projectID(c)
+	if !ok {
+		return
+	}
+
+	var item models.TodoItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+	projectIDStr := strconv.Itoa(id)
+	item.ProjectID = &projectIDStr
+
+	h.items.createItem(c, item)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// roleRequest es el cuerpo de las rutas que asignan un rol
+type roleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// bindRole lee y valida el rol del cuerpo de la petición
+func bindRole(c *gin.Context) (string, bool) {
+	var body roleRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.CuerpoInvalido,
+			"details": err.Error(),
+		})
+		return "", false
+	}
+
+	if err := models.ValidateRole(body.Role); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   err.Error(),
+			"details": constants.ValidRoles,
+		})
+		return "", false
+	}
+	return body.Role, true
+}
+
+// memberUserID lee el parámetro :user_id; responde 400 y devuelve false si no es válido
+func memberUserID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.IDUsuarioInvalido,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *ProjectHandler) GetProjectMembers(c *gin.Context) {
+	id, ok := projectID(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repoFor(c).GetProject(id); err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	members, err := h.repoFor(c).GetProjectMembers(id)
+	if err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.MiembrosObtenidos,
+		"data":    members,
+	})
+}
+
+// SetProjectMember agrega al usuario al proyecto o cambia su rol en él. Lo
+// pueden hacer los admin globales y los del proyecto.
+func (h *ProjectHandler) SetProjectMember(c *gin.Context) {
+	id, ok := projectID(c)
+	if !ok {
+		return
+	}
+	userID, ok := memberUserID(c)
+	if !ok {
+		return
+	}
+	if !authorizeProject(c, constants.PermissionManage, id) {
+		return
+	}
+
+	role, ok := bindRole(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repoFor(c).GetProject(id); err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	member, err := h.repoFor(c).SetProjectMember(models.ProjectMember{
+		ProjectID: strconv.Itoa(id),
+		UserID:    strconv.Itoa(userID),
+		Role:      role,
+	})
+	if err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.MiembroGuardado,
+		"data":    member,
+	})
+}
+
+func (h *ProjectHandler) RemoveProjectMember(c *gin.Context) {
+	id, ok := projectID(c)
+	if !ok {
+		return
+	}
+	userID, ok := memberUserID(c)
+	if !ok {
+		return
+	}
+	if !authorizeProject(c, constants.PermissionManage, id) {
+		return
+	}
+
+	if err := h.repoFor(c).RemoveProjectMember(id, userID); err != nil {
+		respondProjectError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.MiembroEliminado,
+	})
+}
@@ -0,0 +1,422 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/policy"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// ShareHandler administra los compartidos de items y proyectos y atiende los
+// enlaces compartidos. Usa el ItemHandler para que los items que se modifican
+// con un enlace se validen igual que en /items; los permisos del compartido los
+// aplica el repositorio de items (ver policy.SharedPermission).
+type ShareHandler struct {
+	repo     repository.IShareRepository
+	projects repository.IProjectRepository
+	items    *ItemHandler
+}
+
+func NewShareHandler(repo repository.IShareRepository, projects repository.IProjectRepository, items *ItemHandler) *ShareHandler {
+	return &ShareHandler{
+		repo:     repo,
+		projects: projects,
+		items:    items,
+	}
+}
+
+// repoFor devuelve el repositorio limitado a los compartidos del tenant de la petición
+func (h *ShareHandler) repoFor(c *gin.Context) repository.IShareRepository {
+	return h.repo.SharesForTenant(middleware.TenantID(c))
+}
+
+// shareRequest es el cuerpo de POST /shares: item_id o project_id, y user_id
+// para compartir con un usuario o nada para crear un enlace
+type shareRequest struct {
+	ItemID     *string `json:"item_id"`
+	ProjectID  *string `json:"project_id"`
+	UserID     *string `json:"user_id"`
+	Permission string  `json:"permission"`
+	ExpiresAt  *string `json:"expires_at"`
+}
+
+// shareProblem clasifica un error de los repositorios de compartidos. Los
+// errores internos se registran y no se devuelven.
+func shareProblem(err error) Problem {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return Problem{Status: constants.StatusNotFound, Code: constants.ErrCodeShareNotFound, Title: constants.CompartidoNoEncontrado}
+	case errors.Is(err, repository.ErrShareRevoked):
+		return Problem{Status: constants.StatusConflict, Code: constants.ErrCodeShareRevoked, Title: constants.CompartidoYaRevocado}
+	case errors.Is(err, repository.ErrUserNotFound):
+		return Problem{Status: constants.StatusNotFound, Code: constants.ErrCodeUserNotFound, Title: constants.UsuarioNoEncontrado}
+	case errors.Is(err, repository.ErrProjectNotFound):
+		return Problem{Status: constants.StatusNotFound, Code: constants.ErrCodeProjectNotFound, Title: constants.ProyectoNoEncontrado}
+	default:
+		return Problem{Status: constants.StatusInternalServerError, Code: constants.ErrCodeInternal, Title: constants.ErrorInterno, cause: err}
+	}
+}
+
+// respondShareError responde según el tipo de error devuelto por los repositorios
+func respondShareError(c *gin.Context, err error) {
+	respondProblem(c, shareProblem(err))
+}
+
+// respondLinkNotFound responde 404 sin distinguir si el enlace no existe, venció
+// o fue revocado, ni si el item no está incluido en él
+func respondLinkNotFound(c *gin.Context) {
+	respondProblem(c, Problem{
+		Status: constants.StatusNotFound,
+		Code:   constants.ErrCodeShareLinkNotFound,
+		Title:  constants.EnlaceCompartidoNoEncontrado,
+	})
+}
+
+// shareID lee el parámetro :id; responde 400 y devuelve false si no es válido
+func shareID(c *gin.Context) (int, bool) {
+	return targetID(c, c.Param("id"), constants.IDCompartidoInvalido)
+}
+
+// targetID valida el ID de item, proyecto o usuario; responde 400 y devuelve
+// false si no es válido
+func targetID(c *gin.Context, value string, message string) (int, bool) {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		respondProblem(c, Problem{
+			Status: constants.StatusBadRequest,
+			Code:   constants.ErrCodeInvalidID,
+			Title:  message,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// authorizeTarget verifica que quien hace la petición pueda administrar los
+// compartidos del item o del proyecto de share: de un item, quien puede
+// modificarlo por su rol y por ser su creador o responsable; de un proyecto,
+// quien tiene el permiso manage en él. Lo compartido con el usuario no cuenta:
+// no se puede volver a compartir. Responde el error y devuelve false si no puede.
+func (h *ShareHandler) authorizeTarget(c *gin.Context, share models.Share) bool {
+	meta := requestMeta(c)
+	meta.Shares = nil
+
+	if share.ItemID != nil {
+		id, ok := targetID(c, *share.ItemID, constants.IDInvalido)
+		if !ok {
+			return false
+		}
+		item, err := h.items.repo.WithMeta(meta).Get(id)
+		if err != nil {
+			respondItemError(c, err)
+			return false
+		}
+		if !policy.Can(meta, constants.PermissionWrite, item.ProjectID) {
+			respondPermissionDenied(c, constants.PermissionWrite)
+			return false
+		}
+		if !policy.CanModify(meta, item) {
+			respondItemError(c, repository.ErrItemForbidden)
+			return false
+		}
+		return true
+	}
+
+	id, ok := targetID(c, *share.ProjectID, constants.IDProyectoInvalido)
+	if !ok {
+		return false
+	}
+	if _, err := h.projects.ProjectsForTenant(meta.Tenant()).GetProject(id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = repository.ErrProjectNotFound
+		}
+		respondShareError(c, err)
+		return false
+	}
+	if !policy.Can(meta, constants.PermissionManage, share.ProjectID) {
+		respondPermissionDenied(c, constants.PermissionManage)
+		return false
+	}
+	return true
+}
+
+// GetShares lista los compartidos (también los revocados y vencidos) del item
+// (?item_id=) o del proyecto (?project_id=)
+func (h *ShareHandler) GetShares(c *gin.Context) {
+	var target models.Share
+	if value := c.Query("item_id"); value != "" {
+		target.ItemID = &value
+	} else if value := c.Query("project_id"); value != "" {
+		target.ProjectID = &value
+	} else {
+		respondProblem(c, Problem{
+			Status: constants.StatusBadRequest,
+			Code:   constants.ErrCodeInvalidFilter,
+			Title:  constants.FiltroCompartidosRequerido,
+		})
+		return
+	}
+	if !h.authorizeTarget(c, target) {
+		return
+	}
+
+	var shares []models.Share
+	var err error
+	if target.ItemID != nil {
+		id, _ := strconv.Atoi(*target.ItemID)
+		shares, err = h.repoFor(c).GetItemShares(id)
+	} else {
+		id, _ := strconv.Atoi(*target.ProjectID)
+		shares, err = h.repoFor(c).GetProjectShares(id)
+	}
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.CompartidosObtenidos,
+		"data":    shares,
+	})
+}
+
+// GetReceivedShares lista los compartidos vigentes con el usuario autenticado
+func (h *ShareHandler) GetReceivedShares(c *gin.Context) {
+	user, ok := middleware.CurrentUser(c)
+	if !ok {
+		respondItemUserRequired(c)
+		return
+	}
+
+	shares := user.Shares
+	if shares == nil {
+		shares = []models.Share{}
+	}
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.CompartidosObtenidos,
+		"data":    shares,
+	})
+}
+
+// CreateShare comparte un item o un proyecto con un usuario del tenant o, sin
+// user_id, crea un enlace. El token del enlace solo se devuelve en esta
+// respuesta; después solo se ve su prefijo.
+func (h *ShareHandler) CreateShare(c *gin.Context) {
+	var body shareRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	share := models.Share{
+		ItemID:     body.ItemID,
+		ProjectID:  body.ProjectID,
+		UserID:     body.UserID,
+		Permission: body.Permission,
+		ExpiresAt:  body.ExpiresAt,
+	}
+	if err := share.Validate(time.Now()); err != nil {
+		respondProblem(c, Problem{
+			Status: constants.StatusBadRequest,
+			Code:   constants.ErrCodeValidationFailed,
+			Title:  constants.DatosCompartidoInvalidos,
+			detail: err,
+		})
+		return
+	}
+	if share.UserID != nil {
+		if _, ok := targetID(c, *share.UserID, constants.IDUsuarioInvalido); !ok {
+			return
+		}
+	}
+	if !h.authorizeTarget(c, share) {
+		return
+	}
+
+	if meta := requestMeta(c); meta.UserID != "" {
+		share.CreatedBy = &meta.UserID
+	}
+	var token string
+	if share.IsLink() {
+		token, share.Prefix, share.Hash = auth.NewShareToken()
+	}
+
+	createdShare, err := h.repoFor(c).CreateShare(share)
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+	createdShare.Token = token
+
+	c.JSON(constants.StatusCreated, gin.H{
+		"message": constants.CompartidoCreado,
+		"data":    createdShare,
+	})
+}
+
+// RevokeShare revoca el compartido; se conserva para auditarlo
+func (h *ShareHandler) RevokeShare(c *gin.Context) {
+	id, ok := shareID(c)
+	if !ok {
+		return
+	}
+
+	share, err := h.repoFor(c).GetShare(id)
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+	if !h.authorizeTarget(c, share) {
+		return
+	}
+
+	revokedShare, err := h.repoFor(c).RevokeShare(id)
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.CompartidoRevocado,
+		"data":    revokedShare,
+	})
+}
+
+// linkShare busca el enlace vigente del token de la ruta; responde 404 y
+// devuelve false si no existe, venció, fue revocado o es de otro tenant que el
+// que pidió la petición
+func (h *ShareHandler) linkShare(c *gin.Context) (models.Share, bool) {
+	share, err := h.repo.GetShareByHash(auth.HashToken(c.Param("token")))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		respondShareError(c, err)
+		return models.Share{}, false
+	}
+	tenant, hasRequested := middleware.RequestedTenant(c)
+	if err != nil || !share.IsLink() || !share.Active(time.Now()) || (hasRequested && tenant.ID != share.TenantID) {
+		respondLinkNotFound(c)
+		return models.Share{}, false
+	}
+	return share, true
+}
+
+// guestMeta es la RequestMeta de quien usa el enlace: sin otra identidad que el
+// enlace, que figura como actor de los cambios por su prefijo
+func guestMeta(c *gin.Context, share models.Share) models.RequestMeta {
+	return models.RequestMeta{
+		Actor:     share.Prefix,
+		RequestID: c.GetString(middleware.RequestIDKey),
+		TenantID:  share.TenantID,
+		Shares:    []models.Share{share},
+		Guest:     true,
+	}
+}
+
+// GetSharedContent devuelve lo compartido con el enlace: el item o el proyecto
+// con sus items. No requiere autenticarse; el token es la credencial.
+func (h *ShareHandler) GetSharedContent(c *gin.Context) {
+	share, ok := h.linkShare(c)
+	if !ok {
+		return
+	}
+
+	repo := h.items.repo.WithMeta(guestMeta(c, share))
+	now := time.Now()
+	content := models.SharedContent{Permission: share.Permission, ExpiresAt: share.ExpiresAt}
+	if share.ItemID != nil {
+		id, _ := strconv.Atoi(*share.ItemID)
+		item, err := repo.Get(id)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondLinkNotFound(c)
+			return
+		}
+		if err != nil {
+			respondShareError(c, err)
+			return
+		}
+		h.items.decorate(&item, now)
+		content.Item = &item
+	} else {
+		id, _ := strconv.Atoi(*share.ProjectID)
+		project, err := h.projects.ProjectsForTenant(share.TenantID).GetProject(id)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondLinkNotFound(c)
+			return
+		}
+		if err != nil {
+			respondShareError(c, err)
+			return
+		}
+		items, err := repo.GetAll(models.ItemFilter{ProjectID: share.ProjectID})
+		if err != nil {
+			respondShareError(c, err)
+			return
+		}
+		for i := range items {
+			h.items.decorate(&items[i], now)
+		}
+		content.Project = &project
+		content.Items = items
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.CompartidoObtenido,
+		"data":    content,
+	})
+}
+
+// UpdateSharedItem modifica un item incluido en un enlace con permiso edit,
+// con las mismas validaciones que PUT /items/:id. Un enlace de solo lectura
+// responde 403 y un item que el enlace no incluye, 404.
+func (h *ShareHandler) UpdateSharedItem(c *gin.Context) {
+	share, ok := h.linkShare(c)
+	if !ok {
+		return
+	}
+	if share.Permission != constants.SharePermissionEdit {
+		respondProblem(c, Problem{
+			Status: constants.StatusForbidden,
+			Code:   constants.ErrCodeShareReadOnly,
+			Title:  constants.CompartidoSoloLectura,
+		})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondInvalidID(c)
+		return
+	}
+	var item models.TodoItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		respondInvalidBody(c, err)
+		return
+	}
+
+	meta := guestMeta(c, share)
+	current, err := h.items.repo.WithMeta(meta).Get(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !share.Covers(current)) {
+		respondLinkNotFound(c)
+		return
+	}
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	item.ID = strconv.Itoa(id)
+	item, warning, err := h.items.Update(meta, item)
+	if err != nil {
+		respondItemError(c, err)
+		return
+	}
+
+	respondData(c, constants.StatusOK, constants.ItemActualizado, item, responseMeta{Warning: warning})
+}
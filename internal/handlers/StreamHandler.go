@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+type StreamHandler struct {
+	hub       *events.Hub
+	heartbeat time.Duration
+}
+
+func NewStreamHandler(hub *events.Hub, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{
+		hub:       hub,
+		heartbeat: heartbeat,
+	}
+}
+
+// parseStreamFilter arma el filtro del stream a partir de state=a,b y project=<id>;
+// solo pasan los eventos del tenant de la petición
+func parseStreamFilter(c *gin.Context) (func(events.Event) bool, error) {
+	var states []string
+	if value := c.Query("state"); value != "" {
+		for _, state := range strings.Split(value, ",") {
+			states = append(states, strings.TrimSpace(state))
+		}
+	}
+
+	project := c.Query("project")
+	if project != "" {
+		if _, err := strconv.Atoi(project); err != nil {
+			return nil, errors.New(constants.IDProyectoInvalido)
+		}
+	}
+
+	tenantID := middleware.TenantID(c)
+	return func(event events.Event) bool {
+		if event.TenantID != tenantID {
+			return false
+		}
+		if len(states) > 0 && !slices.Contains(states, event.Item.State) {
+			return false
+		}
+		if project != "" && (event.Item.ProjectID == nil || *event.Item.ProjectID != project) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+// lastEventID lee la cabecera Last-Event-ID (o ?last_event_id, para clientes que
+// no pueden enviar cabeceras); 0 si no se envió
+func lastEventID(c *gin.Context) (uint64, error) {
+	value := c.GetHeader(constants.LastEventIDHeader)
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// StreamItems envía por Server-Sent Events los cambios de items (item.created,
+// item.updated, item.deleted, item.restored) hasta que el cliente se desconecta
+func (h *StreamHandler) StreamItems(c *gin.Context) {
+	filter, err := parseStreamFilter(c)
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.FiltroInvalido,
+			"details": err.Error(),
+		})
+		return
+	}
+	lastID, err := lastEventID(c)
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.LastEventIDInvalido,
+		})
+		return
+	}
+
+	sub, replay, complete := h.hub.Subscribe(lastID, filter)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(constants.StatusOK)
+
+	if !complete {
+		c.Render(-1, sse.Event{Event: constants.StreamReset, Data: gin.H{"message": constants.StreamIncompleto}})
+	}
+	for _, event := range replay {
+		h.send(c, event)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				// El hub desconectó al cliente por no consumir a tiempo; al reconectarse retoma con Last-Event-ID
+				return
+			}
+			h.send(c, event)
+		case <-heartbeat.C:
+			c.Writer.WriteString(": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func (h *StreamHandler) send(c *gin.Context, event events.Event) {
+	event.Item.RefreshOverdue(time.Now())
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatUint(event.ID, 10),
+		Event: event.Type,
+		Data:  event,
+	})
+}
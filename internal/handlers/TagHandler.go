@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	repo repository.ITagRepository
+}
+
+func NewTagHandler(repo repository.ITagRepository) *TagHandler {
+	return &TagHandler{
+		repo: repo,
+	}
+}
+
+// repoFor devuelve el repositorio limitado a las etiquetas del tenant de la petición
+func (h *TagHandler) repoFor(c *gin.Context) repository.ITagRepository {
+	return h.repo.TagsForTenant(middleware.TenantID(c))
+}
+
+// tagRequest es el cuerpo de POST /tags y PUT /tags/:id
+type tagRequest struct {
+	Name string `json:"name"`
+}
+
+// mergeTagsRequest es el cuerpo de POST /tags/:id/merge
+type mergeTagsRequest struct {
+	TargetID int `json:"target_id"`
+}
+
+// respondTagError responde según el tipo de error devuelto por el repositorio
+func respondTagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(constants.StatusNotFound, gin.H{
+			"error": constants.EtiquetaNoEncontrada,
+		})
+	case errors.Is(err, repository.ErrDuplicateTag):
+		c.JSON(constants.StatusConflict, gin.H{
+			"error": constants.EtiquetaDuplicada,
+		})
+	default:
+		c.JSON(constants.StatusInternalServerError, gin.H{
+			"error":   constants.ErrorBaseDatos,
+			"details": err.Error(),
+		})
+	}
+}
+
+// bindTagName lee y valida el nombre de etiqueta del cuerpo de la petición
+func bindTagName(c *gin.Context) (string, bool) {
+	var body tagRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.CuerpoInvalido,
+			"details": err.Error(),
+		})
+		return "", false
+	}
+
+	name := models.NormalizeTagName(body.Name)
+	if err := models.ValidateTagName(name); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return "", false
+	}
+	return name, true
+}
+
+func (h *TagHandler) GetTags(c *gin.Context) {
+	tags, err := h.repoFor(c).GetAllTags()
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.EtiquetasObtenidas,
+		"data":    tags,
+	})
+}
+
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	if !authorize(c, constants.PermissionWrite, nil) {
+		return
+	}
+
+	name, ok := bindTagName(c)
+	if !ok {
+		return
+	}
+
+	tag, err := h.repoFor(c).CreateTag(name)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusCreated, gin.H{
+		"message": constants.EtiquetaCreada,
+		"data":    tag,
+	})
+}
+
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.IDEtiquetaInvalido,
+		})
+		return
+	}
+
+	if !authorize(c, constants.PermissionWrite, nil) {
+		return
+	}
+
+	name, ok := bindTagName(c)
+	if !ok {
+		return
+	}
+
+	tag, err := h.repoFor(c).RenameTag(id, name)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.EtiquetaRenombrada,
+		"data":    tag,
+	})
+}
+
+func (h *TagHandler) MergeTags(c *gin.Context) {
+	sourceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.IDEtiquetaInvalido,
+		})
+		return
+	}
+
+	if !authorize(c, constants.PermissionWrite, nil) {
+		return
+	}
+
+	var body mergeTagsRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.CuerpoInvalido,
+			"details": err.Error(),
+		})
+		return
+	}
+	if body.TargetID == sourceID {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.FusionMismaEtiqueta,
+		})
+		return
+	}
+
+	tag, err := h.repoFor(c).MergeTags(sourceID, body.TargetID)
+	if err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.EtiquetasFusionadas,
+		"data":    tag,
+	})
+}
+
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.IDEtiquetaInvalido,
+		})
+		return
+	}
+
+	if !authorize(c, constants.PermissionDelete, nil) {
+		return
+	}
+
+	if err := h.repoFor(c).DeleteTag(id); err != nil {
+		respondTagError(c, err)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.EtiquetaEliminada,
+	})
+}
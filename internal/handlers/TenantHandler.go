@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantHandler muestra el tenant de la petición con sus cuotas
+type TenantHandler struct {
+	repo repository.ITenantRepository
+}
+
+func NewTenantHandler(repo repository.ITenantRepository) *TenantHandler {
+	return &TenantHandler{
+		repo: repo,
+	}
+}
+
+// GetTenant devuelve el tenant de la petición y cuánto usa de sus cuotas
+func (h *TenantHandler) GetTenant(c *gin.Context) {
+	tenant, ok := middleware.CurrentTenant(c)
+	if !ok {
+		c.JSON(constants.StatusNotFound, gin.H{
+			"error": constants.TenantNoEncontrado,
+		})
+		return
+	}
+
+	usage, err := h.repo.GetTenantUsage(tenant.ID)
+	if err != nil {
+		c.JSON(constants.StatusInternalServerError, gin.H{
+			"error":   constants.ErrorBaseDatos,
+			"details": err.Error(),
+		})
+		return
+	}
+	tenant.Usage = &usage
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.TenantObtenido,
+		"data":    tenant,
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler administra los usuarios; sus rutas exigen el scope admin
+type UserHandler struct {
+	repo repository.IUserRepository
+}
+
+func NewUserHandler(repo repository.IUserRepository) *UserHandler {
+	return &UserHandler{
+		repo: repo,
+	}
+}
+
+// SetUserRole cambia el rol global del usuario; vale desde su próxima petición
+func (h *UserHandler) SetUserRole(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.IDUsuarioInvalido,
+		})
+		return
+	}
+
+	role, ok := bindRole(c)
+	if !ok {
+		return
+	}
+
+	// Los usuarios de otro tenant no existen para esta petición
+	userID, _ := strconv.Atoi(id)
+	user, err := h.repo.GetUser(userID)
+	if err == nil && user.TenantID != middleware.TenantID(c) {
+		err = sql.ErrNoRows
+	}
+	if err == nil {
+		user, err = h.repo.SetUserRole(id, role)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(constants.StatusNotFound, gin.H{
+			"error": constants.UsuarioNoEncontrado,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(constants.StatusInternalServerError, gin.H{
+			"error":   constants.ErrorBaseDatos,
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.RolActualizado,
+		"data":    user,
+	})
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/realtime"
+	"github.com/gin-gonic/gin"
+)
+
+type WebSocketHandler struct {
+	server *realtime.Server
+}
+
+func NewWebSocketHandler(server *realtime.Server) *WebSocketHandler {
+	return &WebSocketHandler{
+		server: server,
+	}
+}
+
+// Connect abre el canal colaborativo en vivo. El cliente se suscribe a items,
+// recibe sus cambios, envía mutaciones y ve quién más está viendo cada item; el
+// actor (X-Actor, o ?actor= para navegadores que no pueden enviar cabeceras)
+// aparece en la presencia y en el historial de sus cambios. Los navegadores
+// envían las credenciales como subprotocolos, ver middleware.WebSocketCredentials.
+func (h *WebSocketHandler) Connect(c *gin.Context) {
+	actor := requestMeta(c).Actor
+	if actor == "" {
+		actor = c.Query("actor")
+	}
+	tenant, ok := middleware.CurrentTenant(c)
+	if !ok {
+		tenant = models.Tenant{ID: middleware.TenantID(c)}
+	}
+	h.server.Serve(c.Writer, c.Request, actor, tenant)
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/webhooks"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	repo repository.IWebhookRepository
+}
+
+func NewWebhookHandler(repo repository.IWebhookRepository) *WebhookHandler {
+	return &WebhookHandler{
+		repo: repo,
+	}
+}
+
+// repoFor devuelve el repositorio limitado a los webhooks del tenant de la petición
+func (h *WebhookHandler) repoFor(c *gin.Context) repository.IWebhookRepository {
+	return h.repo.WebhooksForTenant(middleware.TenantID(c))
+}
+
+// webhookRequest es el cuerpo de POST y PUT /webhooks. Sin secret se genera uno
+// al crear y se conserva el actual al actualizar; active es true por defecto.
+type webhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+// respondWebhookError responde según el tipo de error devuelto por el repositorio
+func respondWebhookError(c *gin.Context, err error, notFound string) {
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(constants.StatusNotFound, gin.H{
+			"error": notFound,
+		})
+		return
+	}
+	c.JSON(constants.StatusInternalServerError, gin.H{
+		"error":   constants.ErrorBaseDatos,
+		"details": err.Error(),
+	})
+}
+
+// webhookID lee el parámetro :id; responde 400 y devuelve false si no es válido
+func webhookID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.IDWebhookInvalido,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// bindWebhook lee y valida el webhook del cuerpo de la petición
+func bindWebhook(c *gin.Context) (models.Webhook, bool) {
+	var body webhookRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.CuerpoInvalido,
+			"details": err.Error(),
+		})
+		return models.Webhook{}, false
+	}
+
+	webhook := models.Webhook{
+		URL:    body.URL,
+		Secret: body.Secret,
+		Events: body.Events,
+		Active: body.Active == nil || *body.Active,
+	}
+	if err := webhook.Validate(); err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return webhook, false
+	}
+	return webhook, true
+}
+
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	webhooks, err := h.repoFor(c).GetAllWebhooks()
+	if err != nil {
+		respondWebhookError(c, err, constants.WebhookNoEncontrado)
+		return
+	}
+
+	// El secreto solo se muestra al crear el webhook
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.WebhooksObtenidos,
+		"data":    webhooks,
+	})
+}
+
+func (h *WebhookHandler) GetWebhookByID(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.repoFor(c).GetWebhook(id)
+	if err != nil {
+		respondWebhookError(c, err, constants.WebhookNoEncontrado)
+		return
+	}
+	webhook.Secret = ""
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.WebhookObtenido,
+		"data":    webhook,
+	})
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	webhook, ok := bindWebhook(c)
+	if !ok {
+		return
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = webhooks.NewSecret()
+	}
+
+	createdWebhook, err := h.repoFor(c).CreateWebhook(webhook)
+	if err != nil {
+		respondWebhookError(c, err, constants.WebhookNoEncontrado)
+		return
+	}
+
+	c.JSON(constants.StatusCreated, gin.H{
+		"message": constants.WebhookCreado,
+		"data":    createdWebhook,
+	})
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, ok := bindWebhook(c)
+	if !ok {
+		return
+	}
+	webhook.ID = strconv.Itoa(id)
+
+	updatedWebhook, err := h.repoFor(c).UpdateWebhook(webhook)
+	if err != nil {
+		respondWebhookError(c, err, constants.WebhookNoEncontrado)
+		return
+	}
+	updatedWebhook.Secret = ""
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.WebhookActualizado,
+		"data":    updatedWebhook,
+	})
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repoFor(c).DeleteWebhook(id); err != nil {
+		respondWebhookError(c, err, constants.WebhookNoEncontrado)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.WebhookEliminado,
+	})
+}
+
+// GetDeliveries lista las entregas, filtrando con ?webhook=<id> y ?status=pending|delivered|dead
+// (status=dead es la lista de dead-letter), paginadas con ?page=N&page_size=M
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	filter := repository.DeliveryFilter{
+		WebhookID: c.Query("webhook"),
+		Status:    c.Query("status"),
+	}
+	if filter.WebhookID != "" {
+		if _, err := strconv.Atoi(filter.WebhookID); err != nil {
+			c.JSON(constants.StatusBadRequest, gin.H{
+				"error": constants.IDWebhookInvalido,
+			})
+			return
+		}
+	}
+	switch filter.Status {
+	case "", constants.DeliveryPending, constants.DeliveryDelivered, constants.DeliveryDead:
+	default:
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.EstadoEntregaInvalido,
+		})
+		return
+	}
+
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error":   constants.PaginacionInvalida,
+			"details": err.Error(),
+		})
+		return
+	}
+
+	deliveries, total, err := h.repoFor(c).GetDeliveries(filter, page, pageSize)
+	if err != nil {
+		respondWebhookError(c, err, constants.EntregaNoEncontrada)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.EntregasObtenidas,
+		"data":    deliveries,
+		"pagination": gin.H{
+			"page":      page,
+			"page_size": pageSize,
+			"total":     total,
+		},
+	})
+}
+
+// RedeliverDelivery vuelve a encolar una entrega para enviarla en el próximo ciclo del despachador
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(constants.StatusBadRequest, gin.H{
+			"error": constants.IDEntregaInvalido,
+		})
+		return
+	}
+
+	delivery, err := h.repoFor(c).Redeliver(id)
+	if err != nil {
+		respondWebhookError(c, err, constants.EntregaNoEncontrada)
+		return
+	}
+
+	c.JSON(constants.StatusOK, gin.H{
+		"message": constants.EntregaReprogramada,
+		"data":    delivery,
+	})
+}
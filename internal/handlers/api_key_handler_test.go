@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAPIKeyRouter() (*gin.Engine, *repository.MockRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	handler := NewAPIKeyHandler(repo)
+
+	router.GET("/api-keys", handler.GetAPIKeys)
+	router.POST("/api-keys", handler.CreateAPIKey)
+	router.GET("/api-keys/:id", handler.GetAPIKeyByID)
+	router.POST("/api-keys/:id/revoke", handler.RevokeAPIKey)
+	router.POST("/api-keys/:id/rotate", handler.RotateAPIKey)
+	return router, repo
+}
+
+func sendAPIKey(router *gin.Engine, method string, path string, body string) (*httptest.ResponseRecorder, models.APIKey) {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Data models.APIKey `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response.Data
+}
+
+func TestCreateAPIKey_StoresOnlyHash(t *testing.T) {
+	router, repo := setupAPIKeyRouter()
+
+	w, key := sendAPIKey(router, "POST", "/api-keys", `{"name": "ci", "scopes": ["items:read", "items:write"]}`)
+
+	assert.Equal(t, constants.StatusCreated, w.Code)
+	assert.True(t, strings.HasPrefix(key.Key, constants.APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(key.Key, key.Prefix))
+	assert.Equal(t, []string{constants.ScopeItemsRead, constants.ScopeItemsWrite}, key.Scopes)
+	assert.NotContains(t, w.Body.String(), auth.HashToken(key.Key))
+
+	stored, err := repo.GetAPIKeyByHash(auth.HashToken(key.Key))
+	assert.NoError(t, err)
+	assert.Equal(t, key.ID, stored.ID)
+	assert.Empty(t, stored.Key)
+
+	// Después de crearla solo se ve el prefijo
+	w, key = sendAPIKey(router, "GET", "/api-keys/1", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Empty(t, key.Key)
+	assert.NotEmpty(t, key.Prefix)
+}
+
+func TestCreateAPIKey_Invalid(t *testing.T) {
+	router, _ := setupAPIKeyRouter()
+
+	w, _ := sendAPIKey(router, "POST", "/api-keys", `{"name": " ", "scopes": ["admin"]}`)
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	w, _ = sendAPIKey(router, "POST", "/api-keys", `{"name": "ci", "scopes": []}`)
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	w, _ = sendAPIKey(router, "POST", "/api-keys", `{"name": "ci", "scopes": ["items:delete"]}`)
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+}
+
+func TestRotateAPIKey_ReplacesKey(t *testing.T) {
+	router, repo := setupAPIKeyRouter()
+	_, created := sendAPIKey(router, "POST", "/api-keys", `{"name": "ci", "scopes": ["items:read"]}`)
+
+	w, rotated := sendAPIKey(router, "POST", "/api-keys/1/rotate", "")
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.NotEqual(t, created.Key, rotated.Key)
+	assert.Equal(t, created.Scopes, rotated.Scopes)
+
+	_, err := repo.GetAPIKeyByHash(auth.HashToken(created.Key))
+	assert.Error(t, err)
+	_, err = repo.GetAPIKeyByHash(auth.HashToken(rotated.Key))
+	assert.NoError(t, err)
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	router, _ := setupAPIKeyRouter()
+	sendAPIKey(router, "POST", "/api-keys", `{"name": "ci", "scopes": ["items:read"]}`)
+
+	w, key := sendAPIKey(router, "POST", "/api-keys/1/revoke", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.NotNil(t, key.RevokedAt)
+
+	// Una key revocada no se puede revocar de nuevo ni rotar
+	w, _ = sendAPIKey(router, "POST", "/api-keys/1/revoke", "")
+	assert.Equal(t, constants.StatusConflict, w.Code)
+	w, _ = sendAPIKey(router, "POST", "/api-keys/1/rotate", "")
+	assert.Equal(t, constants.StatusConflict, w.Code)
+
+	// Se sigue listando, para auditarla
+	req, _ := http.NewRequest("GET", "/api-keys", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var response struct {
+		Data []models.APIKey `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data, 1)
+}
+
+func TestRevokeAPIKey_NotFound(t *testing.T) {
+	router, _ := setupAPIKeyRouter()
+
+	w, _ := sendAPIKey(router, "POST", "/api-keys/99/revoke", "")
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+
+	w, _ = sendAPIKey(router, "POST", "/api-keys/abc/revoke", "")
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+}
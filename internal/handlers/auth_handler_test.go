@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAuthRouter() (*gin.Engine, *repository.MockRepository, *auth.TokenIssuer) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	tokens := auth.NewTokenIssuer("secreto de prueba", 15*time.Minute)
+	handler := NewAuthHandler(repo, repo, tokens, time.Hour)
+
+	router.POST("/auth/register", handler.Register)
+	router.POST("/auth/login", handler.Login)
+	router.POST("/auth/refresh", handler.Refresh)
+	router.POST("/auth/logout", handler.Logout)
+	router.GET("/auth/me", handler.Me)
+	return router, repo, tokens
+}
+
+func sendAuth(router *gin.Engine, method string, path string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// login registra al usuario e inicia sesión
+func login(t *testing.T, router *gin.Engine) tokenResponse {
+	w := sendAuth(router, "POST", "/auth/register", `{"email": "Ana@Example.com", "name": "Ana", "password": "contraseña-segura"}`)
+	assert.Equal(t, constants.StatusCreated, w.Code)
+
+	w = sendAuth(router, "POST", "/auth/login", `{"email": "ana@example.com", "password": "contraseña-segura"}`)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	var response struct {
+		Data tokenResponse `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return response.Data
+}
+
+func TestRegister_HashesPassword(t *testing.T) {
+	router, repo, _ := setupAuthRouter()
+
+	w := sendAuth(router, "POST", "/auth/register", `{"email": " Ana@Example.com ", "name": "Ana", "password": "contraseña-segura"}`)
+
+	assert.Equal(t, constants.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "password")
+	var response struct {
+		Data models.User `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "ana@example.com", response.Data.Email)
+
+	user, _ := repo.GetUser(1)
+	assert.NotEqual(t, "contraseña-segura", user.PasswordHash)
+	assert.True(t, auth.CheckPassword(user.PasswordHash, "contraseña-segura"))
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	router, _, _ := setupAuthRouter()
+
+	w := sendAuth(router, "POST", "/auth/register", `{"email": "no-es-un-email", "password": "contraseña-segura"}`)
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	w = sendAuth(router, "POST", "/auth/register", `{"email": "ana@example.com", "password": "corta"}`)
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	sendAuth(router, "POST", "/auth/register", `{"email": "ana@example.com", "password": "contraseña-segura"}`)
+	w = sendAuth(router, "POST", "/auth/register", `{"email": "ANA@example.com", "password": "otra-contraseña"}`)
+	assert.Equal(t, constants.StatusConflict, w.Code)
+}
+
+func TestLogin_IssuesTokens(t *testing.T) {
+	router, _, tokens := setupAuthRouter()
+
+	session := login(t, router)
+
+	assert.Equal(t, constants.TokenTypeBearer, session.TokenType)
+	assert.Equal(t, 900, session.ExpiresIn)
+	assert.NotEmpty(t, session.RefreshToken)
+	claims, err := tokens.Parse(session.AccessToken)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1", claims.Subject)
+		assert.Equal(t, "ana@example.com", claims.Email)
+	}
+
+	// Un token firmado con otro secreto no es válido
+	_, err = auth.NewTokenIssuer("otro secreto", time.Minute).Parse(session.AccessToken)
+	assert.Error(t, err)
+}
+
+func TestLogin_WrongCredentials(t *testing.T) {
+	router, _, _ := setupAuthRouter()
+	login(t, router)
+
+	w := sendAuth(router, "POST", "/auth/login", `{"email": "ana@example.com", "password": "incorrecta"}`)
+	assert.Equal(t, constants.StatusUnauthorized, w.Code)
+
+	// Un email inexistente responde igual, sin revelar que no está registrado
+	w2 := sendAuth(router, "POST", "/auth/login", `{"email": "nadie@example.com", "password": "incorrecta"}`)
+	assert.Equal(t, constants.StatusUnauthorized, w2.Code)
+	assert.Equal(t, w.Body.String(), w2.Body.String())
+}
+
+func TestRefresh_RotatesAndDetectsReuse(t *testing.T) {
+	router, _, _ := setupAuthRouter()
+	session := login(t, router)
+
+	w := sendAuth(router, "POST", "/auth/refresh", `{"refresh_token": "`+session.RefreshToken+`"}`)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	var response struct {
+		Data tokenResponse `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NotEqual(t, session.RefreshToken, response.Data.RefreshToken)
+
+	// Reusar el refresh token anterior revoca también el nuevo
+	w = sendAuth(router, "POST", "/auth/refresh", `{"refresh_token": "`+session.RefreshToken+`"}`)
+	assert.Equal(t, constants.StatusUnauthorized, w.Code)
+	w = sendAuth(router, "POST", "/auth/refresh", `{"refresh_token": "`+response.Data.RefreshToken+`"}`)
+	assert.Equal(t, constants.StatusUnauthorized, w.Code)
+
+	w = sendAuth(router, "POST", "/auth/refresh", `{"refresh_token": "inventado"}`)
+	assert.Equal(t, constants.StatusUnauthorized, w.Code)
+}
+
+func TestLogoutAndMe_RequireUserSession(t *testing.T) {
+	router, _, _ := setupAuthRouter()
+
+	// Sin el middleware de autenticación no hay usuario en el contexto
+	w := sendAuth(router, "POST", "/auth/logout", "")
+	assert.Equal(t, constants.StatusUnauthorized, w.Code)
+	w = sendAuth(router, "GET", "/auth/me", "")
+	assert.Equal(t, constants.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/policy"
+	"github.com/gin-gonic/gin"
+)
+
+// authorize consulta la política de roles: responde 403 (permission_denied) y
+// devuelve false si el rol del usuario no tiene el permiso (constants.PermissionX)
+// sobre los recursos del proyecto projectID (nil: recursos sin proyecto, como las etiquetas).
+// Ver policy.Can; los items lo verifica el repositorio.
+func authorize(c *gin.Context, permission string, projectID *string) bool {
+	if policy.Can(requestMeta(c), permission, projectID) {
+		return true
+	}
+	respondPermissionDenied(c, permission)
+	return false
+}
+
+// respondPermissionDenied responde que el rol del usuario no tiene el permiso
+func respondPermissionDenied(c *gin.Context, permission string) {
+	respondProblem(c, Problem{
+		Status: constants.StatusForbidden,
+		Code:   constants.ErrCodePermissionDenied,
+		Title:  constants.PermisoInsuficiente,
+		Detail: localizer(c).Message(constants.PermisoRequerido, permission),
+	})
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupAssigneeRouter monta las rutas de items con dos usuarios (1 y 2). La
+// petición se hace como el usuario del header X-Test-User, con el scope admin si
+// además se envía X-Test-Admin; sin header, como una API key.
+func setupAssigneeRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	repo.CreateUser(models.User{Email: "ana@example.com", Name: "Ana"})
+	repo.CreateUser(models.User{Email: "luis@example.com", Name: "Luis"})
+	handler := NewItemHandler(repo, config.DefaultItemRules())
+
+	router.Use(func(c *gin.Context) {
+		if value := c.GetHeader("X-Test-User"); value != "" {
+			id, _ := strconv.Atoi(value)
+			user, _ := repo.GetUser(id)
+			c.Set(middleware.UserKey, user)
+			c.Set(middleware.ScopesKey, constants.UserScopes)
+			if c.GetHeader("X-Test-Admin") != "" {
+				c.Set(middleware.ScopesKey, []string{constants.ScopeAdmin})
+			}
+		}
+	})
+	router.GET("/items", handler.GetItems)
+	router.POST("/items", handler.CreateItem)
+	router.PUT("/items/:id", handler.UpdateItem)
+	router.DELETE("/items/:id", handler.DeleteItem)
+	router.PUT("/items/:id/assignee", handler.AssignItem)
+	router.DELETE("/items/:id/assignee", handler.UnassignItem)
+	router.GET("/me/items", handler.GetMyItems)
+	return router
+}
+
+// sendAs hace la petición como el usuario userID (vacío para una API key)
+func sendAs(router *gin.Engine, userID string, method string, path string, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func decodeItem(w *httptest.ResponseRecorder) models.TodoItem {
+	var response struct {
+		Data models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return response.Data
+}
+
+func itemIDs(w *httptest.ResponseRecorder) []string {
+	var response struct {
+		Data []models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	ids := []string{}
+	for _, item := range response.Data {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestCreateItem_SetsCreator(t *testing.T) {
+	router := setupAssigneeRouter()
+
+	// created_by lo decide el servidor, no el cliente
+	w := sendAs(router, "1", "POST", "/items", `{"title": "Tarea", "state": "pending", "created_by": "2", "assignee_id": "2"}`)
+	assert.Equal(t, constants.StatusCreated, w.Code)
+	item := decodeItem(w)
+	if assert.NotNil(t, item.CreatedBy) && assert.NotNil(t, item.AssigneeID) {
+		assert.Equal(t, "1", *item.CreatedBy)
+		assert.Equal(t, "2", *item.AssigneeID)
+	}
+
+	w = sendAs(router, "", "POST", "/items", `{"title": "Tarea", "state": "pending"}`)
+	assert.Equal(t, constants.StatusCreated, w.Code)
+	assert.Nil(t, decodeItem(w).CreatedBy)
+
+	w = sendAs(router, "1", "POST", "/items", `{"title": "Tarea", "state": "pending", "assignee_id": "99"}`)
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+	decodeProblem(t, w, constants.ErrCodeAssigneeNotFound)
+}
+
+func TestAssignItem(t *testing.T) {
+	router := setupAssigneeRouter()
+	sendAs(router, "1", "POST", "/items", `{"title": "Tarea", "state": "pending"}`)
+
+	w := sendAs(router, "1", "PUT", "/items/1/assignee", `{"assignee_id": "2"}`)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	if item := decodeItem(w); assert.NotNil(t, item.AssigneeID) {
+		assert.Equal(t, "2", *item.AssigneeID)
+	}
+
+	w = sendAs(router, "1", "DELETE", "/items/1/assignee", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Nil(t, decodeItem(w).AssigneeID)
+
+	w = sendAs(router, "1", "PUT", "/items/1/assignee", `{"assignee_id": "99"}`)
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+	decodeProblem(t, w, constants.ErrCodeAssigneeNotFound)
+
+	w = sendAs(router, "1", "PUT", "/items/1/assignee", `{"assignee_id": "ana"}`)
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+	decodeProblem(t, w, constants.ErrCodeInvalidID)
+
+	w = sendAs(router, "1", "PUT", "/items/99/assignee", `{"assignee_id": "2"}`)
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+	decodeProblem(t, w, constants.ErrCodeItemNotFound)
+}
+
+func TestItemOwnership(t *testing.T) {
+	router := setupAssigneeRouter()
+	sendAs(router, "1", "POST", "/items", `{"title": "De Ana", "state": "pending"}`)
+	update := `{"title": "Cambiado", "state": "pending"}`
+
+	// Luis no creó el item ni lo tiene asignado
+	w := sendAs(router, "2", "PUT", "/items/1", update)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	decodeProblem(t, w, constants.ErrCodeItemForbidden)
+	w = sendAs(router, "2", "PUT", "/items/1/assignee", `{"assignee_id": "2"}`)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	w = sendAs(router, "2", "DELETE", "/items/1", "")
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+
+	// Con el item asignado ya puede modificarlo
+	w = sendAs(router, "1", "PUT", "/items/1/assignee", `{"assignee_id": "2"}`)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	w = sendAs(router, "2", "PUT", "/items/1", update)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	if item := decodeItem(w); assert.NotNil(t, item.AssigneeID) {
+		assert.Equal(t, "2", *item.AssigneeID, "PUT /items/:id no cambia el responsable")
+	}
+
+	// Las API keys y los administradores modifican cualquier item
+	sendAs(router, "1", "POST", "/items", `{"title": "Otro de Ana", "state": "pending"}`)
+	w = sendAs(router, "", "PUT", "/items/2", update)
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	req, _ := http.NewRequest("DELETE", "/items/2", nil)
+	req.Header.Set("X-Test-User", "2")
+	req.Header.Set("X-Test-Admin", "true")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, constants.StatusOK, w.Code)
+}
+
+func TestGetItems_FilterByUser(t *testing.T) {
+	router := setupAssigneeRouter()
+	sendAs(router, "1", "POST", "/items", `{"title": "De Ana", "state": "pending"}`)
+	sendAs(router, "1", "POST", "/items", `{"title": "De Ana para Luis", "state": "pending", "assignee_id": "2"}`)
+	sendAs(router, "2", "POST", "/items", `{"title": "De Luis para Ana", "state": "pending", "assignee_id": "1"}`)
+
+	tests := []struct {
+		userID string
+		query  string
+		ids    []string
+	}{
+		{"1", "assignee=me", []string{"3"}},
+		{"", "assignee=2", []string{"2"}},
+		{"", "assignee=none", []string{"1"}},
+		{"1", "created_by=me", []string{"1", "2"}},
+		{"2", "created_by=me&assignee=1", []string{"3"}},
+	}
+	for _, tt := range tests {
+		w := sendAs(router, tt.userID, "GET", "/items?"+tt.query, "")
+		assert.Equal(t, constants.StatusOK, w.Code, tt.query)
+		assert.Equal(t, tt.ids, itemIDs(w), tt.query)
+	}
+
+	for _, query := range []string{"assignee=me", "assignee=ana", "created_by=me"} {
+		w := sendAs(router, "", "GET", "/items?"+query, "")
+		assert.Equal(t, constants.StatusBadRequest, w.Code, query)
+		decodeProblem(t, w, constants.ErrCodeInvalidFilter)
+	}
+}
+
+func TestGetMyItems(t *testing.T) {
+	router := setupAssigneeRouter()
+	sendAs(router, "1", "POST", "/items", `{"title": "De Ana", "state": "pending", "priority": "high"}`)
+	sendAs(router, "2", "POST", "/items", `{"title": "De Luis", "state": "pending"}`)
+	sendAs(router, "2", "POST", "/items", `{"title": "De Luis para Ana", "state": "pending", "assignee_id": "1"}`)
+
+	w := sendAs(router, "1", "GET", "/me/items", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Equal(t, []string{"1", "3"}, itemIDs(w))
+
+	w = sendAs(router, "1", "GET", "/me/items?priority=high", "")
+	assert.Equal(t, []string{"1"}, itemIDs(w))
+
+	w = sendAs(router, "", "GET", "/me/items", "")
+	assert.Equal(t, constants.StatusUnauthorized, w.Code)
+	decodeProblem(t, w, constants.ErrCodeUserRequired)
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupDependencyRouter(enforcement string) (*gin.Engine, *ItemHandler) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	rules := config.DefaultItemRules()
+	rules.Dependencies.Enforcement = enforcement
+	handler := NewItemHandler(repo, rules)
+
+	router.PUT("/items/:id", handler.UpdateItem)
+	router.POST("/items/:id/dependencies", handler.AddDependency)
+	router.DELETE("/items/:id/dependencies/:blocker_id", handler.RemoveDependency)
+	router.GET("/items/:id/blockers", handler.GetBlockers)
+	return router, handler
+}
+
+func addDependency(router *gin.Engine, id string, blockerID string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/items/"+id+"/dependencies", bytes.NewBufferString(`{"blocker_id": "`+blockerID+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAddDependency_Success(t *testing.T) {
+	router, handler := setupDependencyRouter(config.EnforcementRefuse)
+	handler.repo.Create(models.TodoItem{Title: "A", State: constants.StatePending})
+	handler.repo.Create(models.TodoItem{Title: "B", State: constants.StatePending})
+
+	w := addDependency(router, "2", "1")
+
+	assert.Equal(t, constants.StatusCreated, w.Code)
+
+	var response struct {
+		Message string          `json:"message"`
+		Data    models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.DependenciaAgregada, response.Message)
+	assert.Equal(t, []string{"1"}, response.Data.BlockedBy)
+}
+
+func TestAddDependency_BlockerNotFound(t *testing.T) {
+	router, handler := setupDependencyRouter(config.EnforcementRefuse)
+	handler.repo.Create(models.TodoItem{Title: "A", State: constants.StatePending})
+
+	w := addDependency(router, "1", "99")
+
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeBlockerNotFound)
+	assert.Equal(t, constants.BloqueanteNoEncontrado, problem.Title)
+}
+
+func TestAddDependency_DetectsCycle(t *testing.T) {
+	router, handler := setupDependencyRouter(config.EnforcementRefuse)
+	handler.repo.Create(models.TodoItem{Title: "A", State: constants.StatePending})
+	handler.repo.Create(models.TodoItem{Title: "B", State: constants.StatePending})
+	handler.repo.Create(models.TodoItem{Title: "C", State: constants.StatePending})
+
+	// C bloqueado por B, B bloqueado por A: A no puede quedar bloqueado por C
+	assert.Equal(t, constants.StatusCreated, addDependency(router, "3", "2").Code)
+	assert.Equal(t, constants.StatusCreated, addDependency(router, "2", "1").Code)
+
+	for _, blockerID := range []string{"3", "1"} {
+		w := addDependency(router, "1", blockerID)
+
+		assert.Equal(t, constants.StatusConflict, w.Code)
+
+		problem := decodeProblem(t, w, constants.ErrCodeDependencyCycle)
+		assert.Equal(t, constants.CicloDependencias, problem.Title)
+	}
+}
+
+func TestGetBlockers_Transitive(t *testing.T) {
+	router, handler := setupDependencyRouter(config.EnforcementRefuse)
+	handler.repo.Create(models.TodoItem{Title: "A", State: constants.StateCompleted})
+	handler.repo.Create(models.TodoItem{Title: "B", State: constants.StatePending})
+	handler.repo.Create(models.TodoItem{Title: "C", State: constants.StatePending})
+	addDependency(router, "3", "2")
+	addDependency(router, "2", "1")
+
+	req, _ := http.NewRequest("GET", "/items/3/blockers", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data []models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data, 2)
+
+	req, _ = http.NewRequest("GET", "/items/3/blockers?open=true", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, "B", response.Data[0].Title)
+	}
+}
+
+func TestRemoveDependency(t *testing.T) {
+	router, handler := setupDependencyRouter(config.EnforcementRefuse)
+	handler.repo.Create(models.TodoItem{Title: "A", State: constants.StatePending})
+	handler.repo.Create(models.TodoItem{Title: "B", State: constants.StatePending})
+	addDependency(router, "2", "1")
+
+	req, _ := http.NewRequest("DELETE", "/items/2/dependencies/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/items/2/dependencies/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeDependencyNotFound)
+	assert.Equal(t, constants.DependenciaNoEncontrada, problem.Title)
+}
+
+func TestUpdateItem_BlockedTransition(t *testing.T) {
+	body := []byte(`{"title": "B", "state": "in_progress"}`)
+
+	tests := []struct {
+		enforcement string
+		status      int
+		warning     interface{}
+	}{
+		{config.EnforcementRefuse, constants.StatusConflict, nil},
+		{config.EnforcementWarn, constants.StatusOK, constants.ItemBloqueado},
+		{config.EnforcementOff, constants.StatusOK, nil},
+	}
+
+	for _, tt := range tests {
+		router, handler := setupDependencyRouter(tt.enforcement)
+		handler.repo.Create(models.TodoItem{Title: "A", State: constants.StatePending})
+		handler.repo.Create(models.TodoItem{Title: "B", State: constants.StatePending})
+		addDependency(router, "2", "1")
+
+		req, _ := http.NewRequest("PUT", "/items/2", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.status, w.Code, tt.enforcement)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, tt.warning, response["warning"], tt.enforcement)
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
 	"github.com/gin-gonic/gin"
@@ -18,7 +21,7 @@ func setupRouter() (*gin.Engine, *ItemHandler) {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	repo := repository.NewMockRepository()
-	handler := NewItemHandler(repo)
+	handler := NewItemHandler(repo, config.DefaultItemRules())
 	return router, handler
 }
 
@@ -71,9 +74,8 @@ func TestGetItemByID_NotFound(t *testing.T) {
 
 	assert.Equal(t, constants.StatusNotFound, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, constants.ItemNoEncontrado, response["error"])
+	problem := decodeProblem(t, w, constants.ErrCodeItemNotFound)
+	assert.Equal(t, constants.ItemNoEncontrado, problem.Title)
 }
 
 func TestGetItemByID_InvalidID(t *testing.T) {
@@ -86,9 +88,8 @@ func TestGetItemByID_InvalidID(t *testing.T) {
 
 	assert.Equal(t, constants.StatusBadRequest, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, constants.IDInvalido, response["error"])
+	problem := decodeProblem(t, w, constants.ErrCodeInvalidID)
+	assert.Equal(t, constants.IDInvalido, problem.Title)
 }
 
 func TestCreateItem_Success(t *testing.T) {
@@ -131,10 +132,9 @@ func TestCreateItem_MissingFields(t *testing.T) {
 
 	assert.Equal(t, constants.StatusBadRequest, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	// Ahora devuelve el mensaje específico del campo que falta
-	assert.Contains(t, response["error"], "requerido")
+	// Cada campo inválido aparece con su código
+	problem := decodeProblem(t, w, constants.ErrCodeValidationFailed)
+	assert.Contains(t, problem.Errors, models.FieldError{Field: "title", Code: constants.FieldRequired, Message: "el título es requerido"})
 }
 
 func TestCreateItem_InvalidJSON(t *testing.T) {
@@ -148,9 +148,8 @@ func TestCreateItem_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, constants.StatusBadRequest, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, constants.CuerpoInvalido, response["error"])
+	problem := decodeProblem(t, w, constants.ErrCodeInvalidBody)
+	assert.Equal(t, constants.CuerpoInvalido, problem.Title)
 }
 
 func TestUpdateItem_Success(t *testing.T) {
@@ -195,10 +194,9 @@ func TestUpdateItem_MissingFields(t *testing.T) {
 
 	assert.Equal(t, constants.StatusBadRequest, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	// Ahora devuelve el mensaje específico del campo que falta
-	assert.Contains(t, response["error"], "requerido")
+	// Cada campo inválido aparece con su código
+	problem := decodeProblem(t, w, constants.ErrCodeValidationFailed)
+	assert.Contains(t, problem.Errors, models.FieldError{Field: "title", Code: constants.FieldRequired, Message: "el título es requerido"})
 }
 
 func TestDeleteItem_Success(t *testing.T) {
@@ -235,10 +233,9 @@ func TestGetItems_DatabaseError(t *testing.T) {
 
 	assert.Equal(t, constants.StatusInternalServerError, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, constants.ErrorBaseDatos, response["error"])
-	assert.NotNil(t, response["details"])
+	problem := decodeProblem(t, w, constants.ErrCodeInternal)
+	assert.Equal(t, constants.ErrorInterno, problem.Title)
+	assert.Empty(t, problem.Detail)
 }
 
 func TestGetItemByID_DatabaseError(t *testing.T) {
@@ -255,10 +252,9 @@ func TestGetItemByID_DatabaseError(t *testing.T) {
 
 	assert.Equal(t, constants.StatusInternalServerError, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, constants.ErrorBaseDatos, response["error"])
-	assert.NotNil(t, response["details"])
+	problem := decodeProblem(t, w, constants.ErrCodeInternal)
+	assert.Equal(t, constants.ErrorInterno, problem.Title)
+	assert.Empty(t, problem.Detail)
 }
 
 func TestCreateItem_DatabaseError(t *testing.T) {
@@ -283,10 +279,9 @@ func TestCreateItem_DatabaseError(t *testing.T) {
 
 	assert.Equal(t, constants.StatusInternalServerError, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, constants.ErrorBaseDatos, response["error"])
-	assert.NotNil(t, response["details"])
+	problem := decodeProblem(t, w, constants.ErrCodeInternal)
+	assert.Equal(t, constants.ErrorInterno, problem.Title)
+	assert.Empty(t, problem.Detail)
 }
 
 func TestUpdateItem_DatabaseError(t *testing.T) {
@@ -311,10 +306,9 @@ func TestUpdateItem_DatabaseError(t *testing.T) {
 
 	assert.Equal(t, constants.StatusInternalServerError, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, constants.ErrorBaseDatos, response["error"])
-	assert.NotNil(t, response["details"])
+	problem := decodeProblem(t, w, constants.ErrCodeInternal)
+	assert.Equal(t, constants.ErrorInterno, problem.Title)
+	assert.Empty(t, problem.Detail)
 }
 
 func TestDeleteItem_DatabaseError(t *testing.T) {
@@ -331,10 +325,9 @@ func TestDeleteItem_DatabaseError(t *testing.T) {
 
 	assert.Equal(t, constants.StatusInternalServerError, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, constants.ErrorBaseDatos, response["error"])
-	assert.NotNil(t, response["details"])
+	problem := decodeProblem(t, w, constants.ErrCodeInternal)
+	assert.Equal(t, constants.ErrorInterno, problem.Title)
+	assert.Empty(t, problem.Detail)
 }
 
 // Tests de validación de estados
@@ -357,9 +350,8 @@ func TestCreateItem_InvalidState(t *testing.T) {
 
 	assert.Equal(t, constants.StatusBadRequest, w.Code)
 
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, constants.EstadoInvalido, response["error"])
+	problem := decodeProblem(t, w, constants.ErrCodeValidationFailed)
+	assert.Equal(t, []models.FieldError{{Field: "state", Code: constants.FieldInvalidState, Message: constants.EstadoInvalido}}, problem.Errors)
 }
 
 func TestCreateItem_ValidStates(t *testing.T) {
@@ -391,6 +383,39 @@ func TestCreateItem_ValidStates(t *testing.T) {
 	}
 }
 
+func TestCreateItem_InitialState(t *testing.T) {
+	router, handler := setupRouter()
+	router.POST("/items", handler.CreateItem)
+	router.POST("/v2/items", middleware.APIVersion(constants.APIVersion2), handler.CreateItem)
+
+	// Sin estado, el item se crea en el estado inicial del flujo
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBufferString(`{"title": "New Task"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusCreated, w.Code)
+	var response struct {
+		Data models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.StatePending, response.Data.State)
+
+	// En v2 los demás estados del flujo solo se alcanzan con transiciones
+	for _, state := range []string{constants.StateInProgress, constants.StateCompleted} {
+		t.Run("state_"+state, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/v2/items", bytes.NewBufferString(`{"title": "New Task", "state": "`+state+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, constants.StatusUnprocessableEntity, w.Code)
+			problem := decodeProblem(t, w, constants.ErrCodeNotInitialState)
+			assert.Equal(t, constants.EstadoNoInicial, problem.Title)
+		})
+	}
+}
+
 func TestUpdateItem_InvalidState(t *testing.T) {
 	router, handler := setupRouter()
 	router.PUT("/items/:id", handler.UpdateItem)
@@ -412,7 +437,306 @@ func TestUpdateItem_InvalidState(t *testing.T) {
 
 	assert.Equal(t, constants.StatusBadRequest, w.Code)
 
-	var response map[string]interface{}
+	problem := decodeProblem(t, w, constants.ErrCodeValidationFailed)
+	assert.Equal(t, []models.FieldError{{Field: "state", Code: constants.FieldInvalidState, Message: constants.EstadoInvalido}}, problem.Errors)
+}
+
+// Tests de fechas de vencimiento
+
+func TestCreateItem_InvalidDueAt(t *testing.T) {
+	router, handler := setupRouter()
+	router.POST("/items", handler.CreateItem)
+
+	dueAt := "mañana"
+	item := models.TodoItem{
+		Title: "New Task",
+		State: constants.StatePending,
+		DueAt: &dueAt,
+	}
+	jsonData, _ := json.Marshal(item)
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeValidationFailed)
+	assert.Equal(t, []models.FieldError{{Field: "due_at", Code: constants.FieldInvalidDate, Message: constants.FechaVencimientoInvalida}}, problem.Errors)
+}
+
+func TestCreateItem_StartAfterDue(t *testing.T) {
+	router, handler := setupRouter()
+	router.POST("/items", handler.CreateItem)
+
+	startAt := "2025-11-10T10:00:00Z"
+	dueAt := "2025-11-01T10:00:00Z"
+	item := models.TodoItem{
+		Title:   "New Task",
+		State:   constants.StatePending,
+		StartAt: &startAt,
+		DueAt:   &dueAt,
+	}
+	jsonData, _ := json.Marshal(item)
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeValidationFailed)
+	assert.Equal(t, []models.FieldError{{Field: "due_at", Code: constants.FieldInvalidDateRange, Message: constants.RangoFechasInvalido}}, problem.Errors)
+}
+
+func TestGetItems_FilterOverdue(t *testing.T) {
+	router, handler := setupRouter()
+	router.GET("/items", handler.GetItems)
+
+	past := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	future := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	handler.repo.Create(models.TodoItem{Title: "Vencida", State: constants.StatePending, DueAt: &past})
+	handler.repo.Create(models.TodoItem{Title: "Completada", State: constants.StateCompleted, DueAt: &past})
+	handler.repo.Create(models.TodoItem{Title: "A tiempo", State: constants.StatePending, DueAt: &future})
+
+	req, _ := http.NewRequest("GET", "/items?overdue=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data []models.TodoItem `json:"data"`
+	}
 	json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Equal(t, constants.EstadoInvalido, response["error"])
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "Vencida", response.Data[0].Title)
+	assert.True(t, response.Data[0].Overdue)
+}
+
+func TestGetItems_FilterDueBefore(t *testing.T) {
+	router, handler := setupRouter()
+	router.GET("/items", handler.GetItems)
+
+	early := "2025-11-01T10:00:00Z"
+	late := "2025-12-01T10:00:00Z"
+	handler.repo.Create(models.TodoItem{Title: "Temprana", State: constants.StatePending, DueAt: &early})
+	handler.repo.Create(models.TodoItem{Title: "Tardía", State: constants.StatePending, DueAt: &late})
+	handler.repo.Create(models.TodoItem{Title: "Sin fecha", State: constants.StatePending})
+
+	req, _ := http.NewRequest("GET", "/items?due_before=2025-11-15T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data []models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "Temprana", response.Data[0].Title)
+}
+
+func TestGetItems_InvalidFilter(t *testing.T) {
+	router, handler := setupRouter()
+	router.GET("/items", handler.GetItems)
+
+	req, _ := http.NewRequest("GET", "/items?due_before=ayer", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeInvalidFilter)
+	assert.Equal(t, constants.FiltroInvalido, problem.Title)
+}
+
+// Tests de prioridades
+
+func TestCreateItem_DefaultPriority(t *testing.T) {
+	router, handler := setupRouter()
+	router.POST("/items", handler.CreateItem)
+
+	item := models.TodoItem{
+		Title: "New Task",
+		State: constants.StatePending,
+	}
+	jsonData, _ := json.Marshal(item)
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusCreated, w.Code)
+
+	var response struct {
+		Data models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.DefaultPriority, response.Data.Priority)
+}
+
+func TestCreateItem_InvalidPriority(t *testing.T) {
+	router, handler := setupRouter()
+	router.POST("/items", handler.CreateItem)
+
+	item := models.TodoItem{
+		Title:    "New Task",
+		State:    constants.StatePending,
+		Priority: "whenever",
+	}
+	jsonData, _ := json.Marshal(item)
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeValidationFailed)
+	assert.Equal(t, []models.FieldError{{Field: "priority", Code: constants.FieldInvalidPriority, Message: constants.PrioridadInvalida}}, problem.Errors)
+}
+
+func TestGetItems_FilterAndSortByPriority(t *testing.T) {
+	router, handler := setupRouter()
+	router.GET("/items", handler.GetItems)
+
+	handler.repo.Create(models.TodoItem{Title: "Alta", State: constants.StatePending, Priority: constants.PriorityHigh})
+	handler.repo.Create(models.TodoItem{Title: "Baja", State: constants.StatePending, Priority: constants.PriorityLow})
+	handler.repo.Create(models.TodoItem{Title: "Urgente", State: constants.StatePending, Priority: constants.PriorityUrgent})
+
+	req, _ := http.NewRequest("GET", "/items?priority=high,urgent&sort=-priority", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data []models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, "Urgente", response.Data[0].Title)
+	assert.Equal(t, "Alta", response.Data[1].Title)
+}
+
+func TestGetItems_InvalidSort(t *testing.T) {
+	router, handler := setupRouter()
+	router.GET("/items", handler.GetItems)
+
+	req, _ := http.NewRequest("GET", "/items?sort=state", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeInvalidFilter)
+	assert.Equal(t, constants.FiltroInvalido, problem.Title)
+}
+
+// Tests de etiquetas
+
+func TestCreateItem_NormalizesTags(t *testing.T) {
+	router, handler := setupRouter()
+	router.POST("/items", handler.CreateItem)
+
+	jsonData := []byte(`{"title": "New Task", "state": "pending", "tags": ["Backend", " backend", "bug"]}`)
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusCreated, w.Code)
+
+	var response struct {
+		Data models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, []string{"backend", "bug"}, response.Data.Tags)
+}
+
+func TestGetItems_FilterByTags(t *testing.T) {
+	router, handler := setupRouter()
+	router.GET("/items", handler.GetItems)
+
+	handler.repo.Create(models.TodoItem{Title: "Ambas", State: constants.StatePending, Tags: []string{"backend", "bug"}})
+	handler.repo.Create(models.TodoItem{Title: "Solo bug", State: constants.StatePending, Tags: []string{"bug"}})
+	handler.repo.Create(models.TodoItem{Title: "Sin etiquetas", State: constants.StatePending})
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"/items?tag=backend,bug", []string{"Ambas", "Solo bug"}},
+		{"/items?tag=backend,bug&tag_mode=all", []string{"Ambas"}},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", tt.query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, constants.StatusOK, w.Code)
+
+		var response struct {
+			Data []models.TodoItem `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		titles := []string{}
+		for _, item := range response.Data {
+			titles = append(titles, item.Title)
+		}
+		assert.Equal(t, tt.expected, titles, tt.query)
+	}
+}
+
+// Tests de proyectos
+
+func TestMoveItem_Success(t *testing.T) {
+	router, handler := setupRouter()
+	router.PUT("/items/:id/project", handler.MoveItem)
+
+	mockRepo := handler.repo.(*repository.MockRepository)
+	mockRepo.CreateProject(models.Project{Name: "Backend"})
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+
+	req, _ := http.NewRequest("PUT", "/items/1/project", bytes.NewBufferString(`{"project_id": "1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Message string          `json:"message"`
+		Data    models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.ItemMovido, response.Message)
+	if assert.NotNil(t, response.Data.ProjectID) {
+		assert.Equal(t, "1", *response.Data.ProjectID)
+	}
+}
+
+func TestMoveItem_ProjectNotFound(t *testing.T) {
+	router, handler := setupRouter()
+	router.PUT("/items/:id/project", handler.MoveItem)
+
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+
+	req, _ := http.NewRequest("PUT", "/items/1/project", bytes.NewBufferString(`{"project_id": "42"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeProjectNotFound)
+	assert.Equal(t, constants.ProyectoNoEncontrado, problem.Title)
 }
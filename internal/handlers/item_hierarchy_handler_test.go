@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupHierarchyRouter(rules config.HierarchyRules) (*gin.Engine, *ItemHandler) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	itemRules := config.DefaultItemRules()
+	itemRules.Hierarchy = rules
+	handler := NewItemHandler(repo, itemRules)
+	return router, handler
+}
+
+// createChild crea un item colgado de parentID (vacío para un item raíz)
+func createChild(handler *ItemHandler, title string, state string, parentID string) models.TodoItem {
+	item := models.TodoItem{Title: title, State: state}
+	if parentID != "" {
+		item.ParentID = &parentID
+	}
+	created, _ := handler.repo.Create(item)
+	return created
+}
+
+func TestGetChildren_WithProgress(t *testing.T) {
+	router, handler := setupHierarchyRouter(config.DefaultHierarchyRules())
+	router.GET("/items/:id", handler.GetItemByID)
+	router.GET("/items/:id/children", handler.GetChildren)
+
+	parent := createChild(handler, "Padre", constants.StatePending, "")
+	createChild(handler, "Hija 1", constants.StateCompleted, parent.ID)
+	createChild(handler, "Hija 2", constants.StatePending, parent.ID)
+	createChild(handler, "Hija 3", constants.StateInProgress, parent.ID)
+	createChild(handler, "Otra raíz", constants.StatePending, "")
+
+	req, _ := http.NewRequest("GET", "/items/1/children", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var children struct {
+		Data []models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &children)
+	assert.Len(t, children.Data, 3)
+
+	req, _ = http.NewRequest("GET", "/items/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Data models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if assert.NotNil(t, response.Data.Progress) {
+		assert.Equal(t, 33, *response.Data.Progress)
+	}
+}
+
+func TestGetSubtree_Nested(t *testing.T) {
+	router, handler := setupHierarchyRouter(config.DefaultHierarchyRules())
+	router.GET("/items/:id/subtree", handler.GetSubtree)
+
+	root := createChild(handler, "Raíz", constants.StatePending, "")
+	child := createChild(handler, "Hija", constants.StatePending, root.ID)
+	createChild(handler, "Nieta", constants.StatePending, child.ID)
+
+	req, _ := http.NewRequest("GET", "/items/1/subtree", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data models.ItemNode `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "Raíz", response.Data.Title)
+	if assert.Len(t, response.Data.Children, 1) {
+		assert.Equal(t, "Hija", response.Data.Children[0].Title)
+		if assert.Len(t, response.Data.Children[0].Children, 1) {
+			assert.Equal(t, "Nieta", response.Data.Children[0].Children[0].Title)
+		}
+	}
+}
+
+func TestSetParent_PreventsCycle(t *testing.T) {
+	router, handler := setupHierarchyRouter(config.DefaultHierarchyRules())
+	router.PUT("/items/:id/parent", handler.SetParent)
+
+	root := createChild(handler, "Raíz", constants.StatePending, "")
+	child := createChild(handler, "Hija", constants.StatePending, root.ID)
+	createChild(handler, "Nieta", constants.StatePending, child.ID)
+
+	for _, parentID := range []string{"1", "3"} {
+		req, _ := http.NewRequest("PUT", "/items/1/parent", bytes.NewBufferString(`{"parent_id": "`+parentID+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, constants.StatusConflict, w.Code)
+
+		problem := decodeProblem(t, w, constants.ErrCodeHierarchyCycle)
+		assert.Equal(t, constants.CicloJerarquia, problem.Title)
+	}
+}
+
+func TestSetParent_Success(t *testing.T) {
+	router, handler := setupHierarchyRouter(config.DefaultHierarchyRules())
+	router.PUT("/items/:id/parent", handler.SetParent)
+
+	createChild(handler, "Padre", constants.StatePending, "")
+	createChild(handler, "Suelta", constants.StatePending, "")
+
+	req, _ := http.NewRequest("PUT", "/items/2/parent", bytes.NewBufferString(`{"parent_id": "1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if assert.NotNil(t, response.Data.ParentID) {
+		assert.Equal(t, "1", *response.Data.ParentID)
+	}
+}
+
+func TestCreateItem_MaxDepth(t *testing.T) {
+	router, handler := setupHierarchyRouter(config.HierarchyRules{MaxDepth: 2})
+	router.POST("/items", handler.CreateItem)
+
+	root := createChild(handler, "Raíz", constants.StatePending, "")
+	createChild(handler, "Hija", constants.StatePending, root.ID)
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBufferString(`{"title": "Nieta", "state": "pending", "parent_id": "2"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusConflict, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeMaxDepth)
+	assert.Equal(t, constants.ProfundidadMaxima, problem.Title)
+}
+
+func TestCreateItem_ParentNotFound(t *testing.T) {
+	router, handler := setupHierarchyRouter(config.DefaultHierarchyRules())
+	router.POST("/items", handler.CreateItem)
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBufferString(`{"title": "Hija", "state": "pending", "parent_id": "99"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeParentNotFound)
+	assert.Equal(t, constants.PadreNoEncontrado, problem.Title)
+}
+
+func TestUpdateItem_BlockParentCompletion(t *testing.T) {
+	parentJSON := []byte(`{"title": "Padre", "state": "completed"}`)
+
+	// Con la regla activa no se puede completar el padre
+	router, handler := setupHierarchyRouter(config.DefaultHierarchyRules())
+	router.PUT("/items/:id", handler.UpdateItem)
+	parent := createChild(handler, "Padre", constants.StatePending, "")
+	createChild(handler, "Hija", constants.StatePending, parent.ID)
+
+	req, _ := http.NewRequest("PUT", "/items/1", bytes.NewBuffer(parentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusConflict, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodePendingSubtasks)
+	assert.Equal(t, constants.SubtareasPendientes, problem.Title)
+
+	// Con la regla desactivada sí
+	router, handler = setupHierarchyRouter(config.HierarchyRules{BlockParentCompletion: false})
+	router.PUT("/items/:id", handler.UpdateItem)
+	parent = createChild(handler, "Padre", constants.StatePending, "")
+	createChild(handler, "Hija", constants.StatePending, parent.ID)
+
+	req, _ = http.NewRequest("PUT", "/items/1", bytes.NewBuffer(parentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupHistoryRouter() (*gin.Engine, *ItemHandler) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.Use(middleware.RequestMeta())
+	repo := repository.NewMockRepository()
+	handler := NewItemHandler(repo, config.DefaultItemRules())
+
+	router.POST("/items", handler.CreateItem)
+	router.PUT("/items/:id", handler.UpdateItem)
+	router.DELETE("/items/:id", handler.DeleteItem)
+	router.POST("/items/:id/restore", handler.RestoreItem)
+	router.GET("/items/:id/history", handler.GetHistory)
+	router.GET("/items/:id", handler.GetItemByID)
+	return router, handler
+}
+
+type historyResponse struct {
+	Data       []models.ItemEvent `json:"data"`
+	Pagination struct {
+		Page     int `json:"page"`
+		PageSize int `json:"page_size"`
+		Total    int `json:"total"`
+	} `json:"pagination"`
+}
+
+func getHistory(router *gin.Engine, path string) (*httptest.ResponseRecorder, historyResponse) {
+	req, _ := http.NewRequest("GET", path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response historyResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func TestGetHistory_RecordsChanges(t *testing.T) {
+	router, _ := setupHistoryRouter()
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBufferString(`{"title": "Task 1", "state": "pending"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.ActorHeader, "ana")
+	req.Header.Set(middleware.RequestIDHeader, "req-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest("PUT", "/items/1", bytes.NewBufferString(`{"title": "Task 1", "state": "in_progress"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.ActorHeader, "luis")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	w, response := getHistory(router, "/items/1/history")
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Equal(t, 2, response.Pagination.Total)
+	assert.Len(t, response.Data, 2)
+
+	// Del más reciente al más antiguo
+	updated := response.Data[0]
+	assert.Equal(t, constants.EventUpdated, updated.Type)
+	assert.Equal(t, "luis", updated.Actor)
+	assert.NotEmpty(t, updated.RequestID)
+	assert.Equal(t, map[string]models.FieldChange{
+		"state": {Old: constants.StatePending, New: constants.StateInProgress},
+	}, updated.Changes)
+
+	created := response.Data[1]
+	assert.Equal(t, constants.EventCreated, created.Type)
+	assert.Equal(t, "ana", created.Actor)
+	assert.Equal(t, "req-1", created.RequestID)
+	assert.Nil(t, created.Changes["title"].Old)
+	assert.Equal(t, "Task 1", created.Changes["title"].New)
+}
+
+func TestGetHistory_DeleteAndRestore(t *testing.T) {
+	router, handler := setupHistoryRouter()
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+
+	req, _ := http.NewRequest("DELETE", "/items/1", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// El historial sigue disponible con el item eliminado
+	_, response := getHistory(router, "/items/1/history")
+	assert.Equal(t, constants.EventDeleted, response.Data[0].Type)
+
+	req, _ = http.NewRequest("POST", "/items/1/restore", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	_, response = getHistory(router, "/items/1/history")
+	assert.Equal(t, 3, response.Pagination.Total)
+	assert.Equal(t, constants.EventRestored, response.Data[0].Type)
+
+	_, err := handler.repo.Get(1)
+	assert.NoError(t, err)
+}
+
+func TestRestoreItem_NotDeleted(t *testing.T) {
+	router, handler := setupHistoryRouter()
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+
+	req, _ := http.NewRequest("POST", "/items/1/restore", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusConflict, w.Code)
+}
+
+func TestGetHistory_Pagination(t *testing.T) {
+	router, handler := setupHistoryRouter()
+	handler.repo.Create(models.TodoItem{Title: "v0", State: constants.StatePending})
+	for _, title := range []string{"v1", "v2", "v3", "v4"} {
+		handler.repo.Update(models.TodoItem{ID: "1", Title: title, State: constants.StatePending})
+	}
+
+	w, response := getHistory(router, "/items/1/history?page=2&page_size=2")
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Equal(t, 5, response.Pagination.Total)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, "v2", response.Data[0].Changes["title"].New)
+	assert.Equal(t, "v1", response.Data[1].Changes["title"].New)
+
+	_, response = getHistory(router, "/items/1/history?page=3&page_size=2")
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, constants.EventCreated, response.Data[0].Type)
+}
+
+func TestGetHistory_InvalidPagination(t *testing.T) {
+	router, handler := setupHistoryRouter()
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+
+	w, _ := getHistory(router, "/items/1/history?page=0")
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	w, _ = getHistory(router, "/items/1/history?page_size=1000")
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+}
+
+func TestGetHistory_NotFound(t *testing.T) {
+	router, _ := setupHistoryRouter()
+
+	w, _ := getHistory(router, "/items/99/history")
+
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+}
+
+func getItemAsOf(router *gin.Engine, asOf time.Time) (*httptest.ResponseRecorder, models.TodoItem) {
+	req, _ := http.NewRequest("GET", "/items/1?as_of="+asOf.UTC().Format(time.RFC3339Nano), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Data models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response.Data
+}
+
+func TestGetItemByID_AsOf(t *testing.T) {
+	router, handler := setupHistoryRouter()
+	beforeCreate := time.Now().Add(-time.Hour)
+	handler.repo.Create(models.TodoItem{Title: "Original", State: constants.StatePending, Tags: []string{"casa"}})
+	time.Sleep(5 * time.Millisecond)
+	afterCreate := time.Now()
+	time.Sleep(5 * time.Millisecond)
+	handler.repo.Update(models.TodoItem{ID: "1", Title: "Renombrado", State: constants.StateInProgress})
+
+	w, item := getItemAsOf(router, afterCreate)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Equal(t, "Original", item.Title)
+	assert.Equal(t, constants.StatePending, item.State)
+	assert.Equal(t, []string{"casa"}, item.Tags)
+
+	_, item = getItemAsOf(router, time.Now())
+	assert.Equal(t, "Renombrado", item.Title)
+	assert.Equal(t, constants.StateInProgress, item.State)
+
+	// Antes de crearse el item no existía
+	w, _ = getItemAsOf(router, beforeCreate)
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+}
+
+func TestGetItemByID_AsOfDeleted(t *testing.T) {
+	router, handler := setupHistoryRouter()
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+	time.Sleep(5 * time.Millisecond)
+	beforeDelete := time.Now()
+	time.Sleep(5 * time.Millisecond)
+	handler.repo.Delete("1")
+
+	w, item := getItemAsOf(router, beforeDelete)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Equal(t, "Task 1", item.Title)
+
+	w, _ = getItemAsOf(router, time.Now())
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+}
+
+func TestGetItemByID_InvalidAsOf(t *testing.T) {
+	router, handler := setupHistoryRouter()
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+
+	req, _ := http.NewRequest("GET", "/items/1?as_of=ayer", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+}
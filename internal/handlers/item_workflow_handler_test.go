@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupWorkflowRouter(workflow config.Workflow) (*gin.Engine, *ItemHandler) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	rules := config.DefaultItemRules()
+	rules.Workflow = workflow
+	handler := NewItemHandler(repo, rules)
+
+	router.GET("/items/:id", handler.GetItemByID)
+	router.PUT("/items/:id", handler.UpdateItem)
+	router.POST("/items", handler.CreateItem)
+	router.POST("/v2/items", middleware.APIVersion(constants.APIVersion2), handler.CreateItem)
+	router.POST("/items/:id/transitions", handler.TransitionItem)
+	return router, handler
+}
+
+func transition(router *gin.Engine, id string, to string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/items/"+id+"/transitions", bytes.NewBufferString(`{"to": "`+to+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetItemByID_AllowedTransitions(t *testing.T) {
+	router, handler := setupWorkflowRouter(config.DefaultWorkflow())
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StateCompleted})
+
+	req, _ := http.NewRequest("GET", "/items/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Data models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, []string{constants.StateInProgress}, response.Data.AllowedTransitions)
+}
+
+func TestTransitionItem_Success(t *testing.T) {
+	router, handler := setupWorkflowRouter(config.DefaultWorkflow())
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending, Priority: constants.PriorityHigh})
+
+	w := transition(router, "1", constants.StateInProgress)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Message string          `json:"message"`
+		Data    models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.ItemTransicionado, response.Message)
+	assert.Equal(t, constants.StateInProgress, response.Data.State)
+	assert.Equal(t, constants.PriorityHigh, response.Data.Priority)
+}
+
+func TestTransitionItem_Illegal(t *testing.T) {
+	router, handler := setupWorkflowRouter(config.DefaultWorkflow())
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StateCompleted})
+
+	w := transition(router, "1", constants.StatePending)
+
+	assert.Equal(t, constants.StatusConflict, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeInvalidTransition)
+	assert.Equal(t, constants.TransicionInvalida, problem.Title)
+	assert.Equal(t, constants.StateCompleted, problem.From)
+	assert.Equal(t, []string{constants.StateInProgress}, problem.Allowed)
+}
+
+func TestUpdateItem_IllegalTransition(t *testing.T) {
+	router, handler := setupWorkflowRouter(config.DefaultWorkflow())
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StateCompleted})
+
+	req, _ := http.NewRequest("PUT", "/items/1", bytes.NewBufferString(`{"title": "Task 1", "state": "pending"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusConflict, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeInvalidTransition)
+	assert.Equal(t, constants.TransicionInvalida, problem.Title)
+}
+
+func TestCustomWorkflow(t *testing.T) {
+	workflow := config.Workflow{
+		Initial: "backlog",
+		States:  []string{"backlog", "review", constants.StateCompleted},
+		Transitions: map[string][]string{
+			"backlog": {"review"},
+			"review":  {"backlog", constants.StateCompleted},
+		},
+	}
+	assert.NoError(t, workflow.Validate())
+
+	router, _ := setupWorkflowRouter(workflow)
+
+	// En v2 un item nuevo solo puede estar en el estado inicial del flujo
+	req, _ := http.NewRequest("POST", "/v2/items", bytes.NewBufferString(`{"title": "Task 1", "state": "review"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, constants.StatusUnprocessableEntity, w.Code)
+
+	req, _ = http.NewRequest("POST", "/items", bytes.NewBufferString(`{"title": "Task 1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, constants.StatusCreated, w.Code)
+	var response struct {
+		Data models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "backlog", response.Data.State)
+
+	// backlog -> completed no está permitido en este flujo
+	assert.Equal(t, constants.StatusConflict, transition(router, "1", constants.StateCompleted).Code)
+	assert.Equal(t, constants.StatusOK, transition(router, "1", "review").Code)
+	assert.Equal(t, constants.StatusOK, transition(router, "1", constants.StateCompleted).Code)
+	// in_progress no existe en este flujo
+	assert.Equal(t, constants.StatusBadRequest, transition(router, "1", constants.StateInProgress).Code)
+}
+
+func TestWorkflowValidate_Invalid(t *testing.T) {
+	workflow := config.Workflow{
+		Initial:     "draft",
+		States:      []string{constants.StatePending, constants.StateCompleted},
+		Transitions: map[string][]string{},
+	}
+	assert.Error(t, workflow.Validate())
+
+	workflow.Initial = constants.StatePending
+	workflow.Transitions[constants.StatePending] = []string{"archived"}
+	assert.Error(t, workflow.Validate())
+}
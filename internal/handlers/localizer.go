@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/i18n"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// localizer devuelve el traductor del idioma negociado por middleware.Language;
+// sin el middleware los mensajes se responden en español
+func localizer(c *gin.Context) i18n.Localizer {
+	return middleware.Localizer(c)
+}
+
+// localizeError traduce el mensaje de un error; los errores de validación se
+// traducen campo por campo
+func localizeError(localizer i18n.Localizer, err error) string {
+	var fieldErrors models.FieldErrors
+	if errors.As(err, &fieldErrors) {
+		messages := make([]string, len(fieldErrors))
+		for i, fieldError := range fieldErrors {
+			messages[i] = localizer.Message(fieldError.Message)
+		}
+		return strings.Join(messages, "; ")
+	}
+	return localizer.Error(err)
+}
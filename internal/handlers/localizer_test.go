@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/i18n"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupLocalizedRouter() (*gin.Engine, *ItemHandler) {
+	router, handler := setupRouter()
+	router.Use(middleware.Language(i18n.Default))
+	router.GET("/items", handler.GetItems)
+	router.GET("/items/:id", handler.GetItemByID)
+	router.POST("/items", handler.CreateItem)
+	return router, handler
+}
+
+func TestLocalizedSuccessMessage(t *testing.T) {
+	router, handler := setupLocalizedRouter()
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+
+	tests := []struct {
+		query          string
+		acceptLanguage string
+		language       string
+		message        string
+	}{
+		{"/items/1", "", "es", constants.ItemObtenido},
+		{"/items/1", "en-US,en;q=0.9", "en", "Item retrieved successfully"},
+		{"/items/1?lang=es", "en", "es", constants.ItemObtenido},
+		{"/items/1?lang=en", "", "en", "Item retrieved successfully"},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", tt.query, nil)
+		req.Header.Set("Accept-Language", tt.acceptLanguage)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, constants.StatusOK, w.Code)
+		assert.Equal(t, tt.language, w.Header().Get(middleware.ContentLanguageHeader), tt.query)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, tt.message, response["message"], tt.query)
+	}
+}
+
+func TestLocalizedProblem(t *testing.T) {
+	router, _ := setupLocalizedRouter()
+
+	req, _ := http.NewRequest("GET", "/items/999?lang=en", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+	problem := decodeProblem(t, w, constants.ErrCodeItemNotFound)
+	assert.Equal(t, "Item not found", problem.Title)
+
+	req, _ = http.NewRequest("GET", "/items?sort=state&lang=en", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+	problem = decodeProblem(t, w, constants.ErrCodeInvalidFilter)
+	assert.Equal(t, "Invalid filter parameter", problem.Title)
+	assert.Equal(t, "invalid sort field: state", problem.Detail)
+}
+
+func TestLocalizedValidationErrors(t *testing.T) {
+	router, _ := setupLocalizedRouter()
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBufferString(`{"state": "done"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+	problem := decodeProblem(t, w, constants.ErrCodeValidationFailed)
+	assert.Equal(t, "The item data is not valid", problem.Title)
+	assert.Equal(t, "title is required; invalid state.", problem.Detail)
+	assert.Equal(t, []models.FieldError{
+		{Field: "title", Code: constants.FieldRequired, Message: "title is required"},
+		{Field: "state", Code: constants.FieldInvalidState, Message: "invalid state."},
+	}, problem.Errors)
+
+	// El código del campo no depende del idioma
+	req, _ = http.NewRequest("POST", "/items", bytes.NewBufferString(`{"title": 42, "state": "pending"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	problem = decodeProblem(t, w, constants.ErrCodeInvalidBody)
+	if assert.Len(t, problem.Errors, 1) {
+		assert.Equal(t, constants.FieldInvalidType, problem.Errors[0].Code)
+		assert.Equal(t, "invalid data type, expected string", problem.Errors[0].Message)
+	}
+}
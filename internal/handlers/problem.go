@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// Problem es el cuerpo de las respuestas de error de la API de items y de las
+// denegaciones de autorización (RFC 9457, application/problem+json). Code es el código estable que pueden
+// comparar los clientes; Title y Detail son legibles y pueden cambiar.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	Code      string              `json:"code"`
+	RequestID string              `json:"request_id,omitempty"`
+	Errors    []models.FieldError `json:"errors,omitempty"`
+
+	// From y Allowed acompañan a invalid_transition: el estado actual del item
+	// y los estados a los que puede pasar
+	From    string   `json:"from,omitempty"`
+	Allowed []string `json:"allowed,omitempty"`
+
+	// detail es el error del que sale Detail, que se traduce al responder
+	detail error
+	// cause es el error interno, que se registra en el log y no se devuelve
+	cause error
+}
+
+// respondProblem responde el error como application/problem+json, con los
+// mensajes en el idioma de la petición. Los errores internos se registran con el
+// ID de la petición para poder encontrarlos.
+func respondProblem(c *gin.Context, problem Problem) {
+	localizer := localizer(c)
+	problem.Title = localizer.Message(problem.Title)
+	if problem.detail != nil {
+		problem.Detail = localizeError(localizer, problem.detail)
+	}
+	for i := range problem.Errors {
+		problem.Errors[i].Message = localizer.Message(problem.Errors[i].Message)
+	}
+
+	problem.Type = constants.ProblemTypePrefix + problem.Code
+	problem.Instance = c.Request.URL.Path
+	problem.RequestID = c.GetString(middleware.RequestIDKey)
+	if problem.Status >= constants.StatusInternalServerError {
+		log.Printf("ERROR: %s %s (request_id=%s): %v", c.Request.Method, c.Request.URL.Path, problem.RequestID, problem.cause)
+	}
+
+	c.Header("Content-Type", constants.ProblemContentType)
+	c.JSON(problem.Status, problem)
+}
+
+// respondInvalidID responde un ID de item (o de bloqueante) que no es numérico
+func respondInvalidID(c *gin.Context) {
+	respondProblem(c, Problem{
+		Status: constants.StatusBadRequest,
+		Code:   constants.ErrCodeInvalidID,
+		Title:  constants.IDInvalido,
+	})
+}
+
+// respondItemUserRequired responde una petición autenticada con una API key a una
+// ruta que necesita un usuario
+func respondItemUserRequired(c *gin.Context) {
+	respondProblem(c, Problem{
+		Status: constants.StatusUnauthorized,
+		Code:   constants.ErrCodeUserRequired,
+		Title:  constants.UsuarioRequerido,
+	})
+}
+
+// respondInvalidBody responde un cuerpo que no se pudo leer; si un campo tiene
+// un tipo inesperado se informa cuál
+func respondInvalidBody(c *gin.Context, err error) {
+	problem := Problem{
+		Status: constants.StatusBadRequest,
+		Code:   constants.ErrCodeInvalidBody,
+		Title:  constants.CuerpoInvalido,
+		detail: err,
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		problem.Errors = []models.FieldError{{
+			Field:   typeErr.Field,
+			Code:    constants.FieldInvalidType,
+			Message: localizer(c).Message(constants.TipoInvalido, typeErr.Type.String()),
+		}}
+	}
+	respondProblem(c, problem)
+}
+
+// respondInvalidFilter responde un parámetro de filtro inválido
+func respondInvalidFilter(c *gin.Context, err error) {
+	respondProblem(c, Problem{
+		Status: constants.StatusBadRequest,
+		Code:   constants.ErrCodeInvalidFilter,
+		Title:  constants.FiltroInvalido,
+		detail: err,
+	})
+}
+
+// respondInvalidPagination responde parámetros de paginación inválidos
+func respondInvalidPagination(c *gin.Context, err error) {
+	respondProblem(c, Problem{
+		Status: constants.StatusBadRequest,
+		Code:   constants.ErrCodeInvalidPagination,
+		Title:  constants.PaginacionInvalida,
+		detail: err,
+	})
+}
+
+// itemProblem clasifica un error de las operaciones de items (repositorio,
+// validación o reglas de negocio)
+func itemProblem(err error) Problem {
+	var validation ValidationError
+	if errors.As(err, &validation) {
+		problem := Problem{
+			Status: constants.StatusBadRequest,
+			Code:   constants.ErrCodeValidationFailed,
+			Title:  constants.DatosInvalidos,
+			detail: err,
+		}
+		var fieldErrors models.FieldErrors
+		if errors.As(err, &fieldErrors) {
+			// Copia, para traducir los mensajes sin modificar el error
+			problem.Errors = append([]models.FieldError(nil), fieldErrors...)
+		}
+		return problem
+	}
+
+	conflict := func(code string) Problem {
+		return Problem{Status: constants.StatusConflict, Code: code, Title: err.Error()}
+	}
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return Problem{Status: constants.StatusNotFound, Code: constants.ErrCodeItemNotFound, Title: constants.ItemNoEncontrado}
+	case errors.Is(err, repository.ErrProjectNotFound):
+		return Problem{Status: constants.StatusNotFound, Code: constants.ErrCodeProjectNotFound, Title: constants.ProyectoNoEncontrado}
+	case errors.Is(err, repository.ErrProjectArchived):
+		return Problem{Status: constants.StatusConflict, Code: constants.ErrCodeProjectArchived, Title: constants.ProyectoArchivadoErr}
+	case errors.Is(err, repository.ErrParentNotFound):
+		return Problem{Status: constants.StatusNotFound, Code: constants.ErrCodeParentNotFound, Title: constants.PadreNoEncontrado}
+	case errors.Is(err, repository.ErrBlockerNotFound):
+		return Problem{Status: constants.StatusNotFound, Code: constants.ErrCodeBlockerNotFound, Title: constants.BloqueanteNoEncontrado}
+	case errors.Is(err, repository.ErrAssigneeNotFound):
+		return Problem{Status: constants.StatusNotFound, Code: constants.ErrCodeAssigneeNotFound, Title: constants.ResponsableNoEncontrado}
+	case errors.Is(err, repository.ErrPermissionDenied):
+		return Problem{Status: constants.StatusForbidden, Code: constants.ErrCodePermissionDenied, Title: constants.PermisoInsuficiente}
+	case errors.Is(err, repository.ErrItemForbidden):
+		return Problem{Status: constants.StatusForbidden, Code: constants.ErrCodeItemForbidden, Title: constants.ItemDeOtroUsuario}
+	case errors.Is(err, repository.ErrItemQuotaExceeded):
+		return Problem{Status: constants.StatusForbidden, Code: constants.ErrCodeQuotaExceeded, Title: constants.CuotaItemsExcedida}
+	case errors.Is(err, repository.ErrItemNotDeleted):
+		return Problem{Status: constants.StatusConflict, Code: constants.ErrCodeItemNotDeleted, Title: constants.ItemNoEliminado}
+	case errors.Is(err, repository.ErrHierarchyCycle):
+		return conflict(constants.ErrCodeHierarchyCycle)
+	case errors.Is(err, repository.ErrMaxDepth):
+		return conflict(constants.ErrCodeMaxDepth)
+	case errors.Is(err, errPendingSubtasks):
+		return conflict(constants.ErrCodePendingSubtasks)
+	case errors.Is(err, repository.ErrDependencyCycle):
+		return conflict(constants.ErrCodeDependencyCycle)
+	case errors.Is(err, errBlockedItem):
+		return conflict(constants.ErrCodeItemBlocked)
+	case errors.Is(err, errInvalidTransition):
+		return conflict(constants.ErrCodeInvalidTransition)
+	case errors.Is(err, errNotInitialState):
+		return Problem{
+			Status: constants.StatusUnprocessableEntity,
+			Code:   constants.ErrCodeNotInitialState,
+			Title:  constants.EstadoNoInicial,
+			Errors: []models.FieldError{{Field: "state", Code: constants.FieldInvalidState, Message: constants.EstadoNoInicial}},
+		}
+	default:
+		return Problem{Status: constants.StatusInternalServerError, Code: constants.ErrCodeInternal, Title: constants.ErrorInterno, cause: err}
+	}
+}
+
+// ItemErrorStatus devuelve el código HTTP y el mensaje con que se responde un
+// error de las operaciones de items. Lo usan las APIs gRPC y GraphQL.
+func ItemErrorStatus(err error) (int, string) {
+	problem := itemProblem(err)
+	if problem.detail != nil {
+		return problem.Status, problem.detail.Error()
+	}
+	return problem.Status, problem.Title
+}
+
+// respondItemError responde según el tipo de error devuelto por el repositorio de items
+func respondItemError(c *gin.Context, err error) {
+	respondProblem(c, itemProblem(err))
+}
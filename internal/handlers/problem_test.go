@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeProblem verifica que la respuesta sea application/problem+json con el
+// código indicado y devuelve el cuerpo
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder, code string) Problem {
+	t.Helper()
+	assert.Equal(t, constants.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, code, problem.Code)
+	assert.Equal(t, constants.ProblemTypePrefix+code, problem.Type)
+	assert.Equal(t, w.Code, problem.Status)
+	return problem
+}
+
+func TestProblem_ValidationListsEveryField(t *testing.T) {
+	router, handler := setupRouter()
+	router.POST("/items", handler.CreateItem)
+
+	body := `{"state": "done", "priority": "whenever", "start_at": "2025-11-10T10:00:00Z", "due_at": "2025-11-01T10:00:00Z"}`
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeValidationFailed)
+	assert.Equal(t, constants.DatosInvalidos, problem.Title)
+	assert.Equal(t, "/items", problem.Instance)
+	assert.Equal(t, []models.FieldError{
+		{Field: "title", Code: constants.FieldRequired, Message: "el título es requerido"},
+		{Field: "state", Code: constants.FieldInvalidState, Message: constants.EstadoInvalido},
+		{Field: "priority", Code: constants.FieldInvalidPriority, Message: constants.PrioridadInvalida},
+		{Field: "due_at", Code: constants.FieldInvalidDateRange, Message: constants.RangoFechasInvalido},
+	}, problem.Errors)
+}
+
+func TestProblem_InvalidBodyType(t *testing.T) {
+	router, handler := setupRouter()
+	router.POST("/items", handler.CreateItem)
+
+	req, _ := http.NewRequest("POST", "/items", bytes.NewBufferString(`{"title": 42, "state": "pending"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeInvalidBody)
+	if assert.Len(t, problem.Errors, 1) {
+		assert.Equal(t, "title", problem.Errors[0].Field)
+		assert.Equal(t, constants.FieldInvalidType, problem.Errors[0].Code)
+	}
+}
+
+func TestProblem_InternalErrorHidesDetails(t *testing.T) {
+	router, handler := setupRouter()
+	router.Use(middleware.RequestMeta())
+	router.GET("/items/:id", handler.GetItemByID)
+
+	handler.repo.(*repository.MockRepository).SimulateError(true)
+
+	req, _ := http.NewRequest("GET", "/items/1", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusInternalServerError, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeInternal)
+	assert.Equal(t, constants.ErrorInterno, problem.Title)
+	assert.Empty(t, problem.Detail)
+	assert.Equal(t, "req-123", problem.RequestID)
+	assert.NotContains(t, w.Body.String(), "simulated database error")
+}
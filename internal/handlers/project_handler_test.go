@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupProjectRouter() (*gin.Engine, *ProjectHandler, *repository.MockRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	handler := NewProjectHandler(repo, NewItemHandler(repo, config.DefaultItemRules()))
+	return router, handler, repo
+}
+
+func TestCreateProject_Success(t *testing.T) {
+	router, handler, _ := setupProjectRouter()
+	router.POST("/projects", handler.CreateProject)
+
+	req, _ := http.NewRequest("POST", "/projects", bytes.NewBufferString(`{"name": "Backend", "description": "API"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusCreated, w.Code)
+
+	var response struct {
+		Message string         `json:"message"`
+		Data    models.Project `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.ProyectoCreado, response.Message)
+	assert.Equal(t, "Backend", response.Data.Name)
+	assert.Equal(t, 0, response.Data.Counts[constants.StatePending])
+}
+
+func TestCreateProject_MissingName(t *testing.T) {
+	router, handler, _ := setupProjectRouter()
+	router.POST("/projects", handler.CreateProject)
+
+	req, _ := http.NewRequest("POST", "/projects", bytes.NewBufferString(`{"description": "API"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.NombreProyectoRequerido, response["error"])
+}
+
+func TestGetProjectByID_Counts(t *testing.T) {
+	router, handler, repo := setupProjectRouter()
+	router.GET("/projects/:id", handler.GetProjectByID)
+
+	project, _ := repo.CreateProject(models.Project{Name: "Backend"})
+	repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending, ProjectID: &project.ID})
+	repo.Create(models.TodoItem{Title: "Task 2", State: constants.StatePending, ProjectID: &project.ID})
+	repo.Create(models.TodoItem{Title: "Task 3", State: constants.StateCompleted, ProjectID: &project.ID})
+	repo.Create(models.TodoItem{Title: "Sin proyecto", State: constants.StatePending})
+
+	req, _ := http.NewRequest("GET", "/projects/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data models.Project `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, 2, response.Data.Counts[constants.StatePending])
+	assert.Equal(t, 0, response.Data.Counts[constants.StateInProgress])
+	assert.Equal(t, 1, response.Data.Counts[constants.StateCompleted])
+}
+
+func TestGetProjectByID_NotFound(t *testing.T) {
+	router, handler, _ := setupProjectRouter()
+	router.GET("/projects/:id", handler.GetProjectByID)
+
+	req, _ := http.NewRequest("GET", "/projects/99", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.ProyectoNoEncontrado, response["error"])
+}
+
+func TestProjectItems_CreateAndList(t *testing.T) {
+	router, handler, repo := setupProjectRouter()
+	router.GET("/projects/:id/items", handler.GetProjectItems)
+	router.POST("/projects/:id/items", handler.CreateProjectItem)
+
+	repo.CreateProject(models.Project{Name: "Backend"})
+	repo.Create(models.TodoItem{Title: "Sin proyecto", State: constants.StatePending})
+
+	req, _ := http.NewRequest("POST", "/projects/1/items", bytes.NewBufferString(`{"title": "En proyecto", "state": "pending"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusCreated, w.Code)
+
+	req, _ = http.NewRequest("GET", "/projects/1/items", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data []models.TodoItem `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "En proyecto", response.Data[0].Title)
+}
+
+func TestCreateProjectItem_ArchivedProject(t *testing.T) {
+	router, handler, repo := setupProjectRouter()
+	router.POST("/projects/:id/items", handler.CreateProjectItem)
+
+	repo.CreateProject(models.Project{Name: "Viejo"})
+	repo.SetProjectArchived(1, true)
+
+	req, _ := http.NewRequest("POST", "/projects/1/items", bytes.NewBufferString(`{"title": "Nuevo", "state": "pending"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusConflict, w.Code)
+
+	problem := decodeProblem(t, w, constants.ErrCodeProjectArchived)
+	assert.Equal(t, constants.ProyectoArchivadoErr, problem.Title)
+}
+
+func TestArchiveProject_HiddenFromList(t *testing.T) {
+	router, handler, repo := setupProjectRouter()
+	router.GET("/projects", handler.GetProjects)
+	router.POST("/projects/:id/archive", handler.ArchiveProject)
+
+	repo.CreateProject(models.Project{Name: "Activo"})
+	repo.CreateProject(models.Project{Name: "Viejo"})
+
+	req, _ := http.NewRequest("POST", "/projects/2/archive", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data []models.Project `json:"data"`
+	}
+
+	req, _ = http.NewRequest("GET", "/projects", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data, 1)
+
+	req, _ = http.NewRequest("GET", "/projects?archived=true", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data, 2)
+}
+
+func TestDeleteProject_NotEmpty(t *testing.T) {
+	router, handler, repo := setupProjectRouter()
+	router.DELETE("/projects/:id", handler.DeleteProject)
+
+	project, _ := repo.CreateProject(models.Project{Name: "Backend"})
+	repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending, ProjectID: &project.ID})
+
+	req, _ := http.NewRequest("DELETE", "/projects/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.ProyectoConItems, response["error"])
+}
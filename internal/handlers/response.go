@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// pagination describe la página de un listado paginado
+type pagination struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+	Total    int `json:"total"`
+}
+
+// responseMeta acompaña al resultado de una respuesta exitosa
+type responseMeta struct {
+	// Warning es la advertencia de bloqueantes abiertos en modo warn de dependencias
+	Warning    string
+	Pagination *pagination
+}
+
+// apiVersion devuelve la versión de la API de la petición; sin los middlewares
+// de versión (por ejemplo en las mutaciones del WebSocket) es la 1
+func apiVersion(c *gin.Context) string {
+	if version := c.GetString(middleware.APIVersionKey); version != "" {
+		return version
+	}
+	return constants.APIVersion1
+}
+
+// respondData responde un resultado con el sobre de la versión de la API:
+// en v1 {message, data, warning, pagination}; en v2 {data, meta} con los items en
+// su representación V2 y sin mensaje
+func respondData(c *gin.Context, status int, message string, data any, meta responseMeta) {
+	localizer := localizer(c)
+
+	if apiVersion(c) == constants.APIVersion2 {
+		response := gin.H{"data": toV2(data)}
+		responseMeta := gin.H{}
+		if meta.Warning != "" {
+			responseMeta["warnings"] = []string{localizer.Message(meta.Warning)}
+		}
+		if meta.Pagination != nil {
+			responseMeta["pagination"] = meta.Pagination
+		}
+		if len(responseMeta) > 0 {
+			response["meta"] = responseMeta
+		}
+		c.JSON(status, response)
+		return
+	}
+
+	response := gin.H{
+		"message": localizer.Message(message),
+		"data":    data,
+	}
+	if meta.Warning != "" {
+		response["warning"] = localizer.Message(meta.Warning)
+	}
+	if meta.Pagination != nil {
+		response["pagination"] = meta.Pagination
+	}
+	c.JSON(status, response)
+}
+
+// respondMessage responde una operación sin resultado: en v1 con el mensaje y en
+// v2 con 204 sin cuerpo
+func respondMessage(c *gin.Context, message string) {
+	if apiVersion(c) == constants.APIVersion2 {
+		c.Status(constants.StatusNoContent)
+		return
+	}
+	c.JSON(constants.StatusOK, gin.H{
+		"message": localizer(c).Message(message),
+	})
+}
+
+// toV2 convierte los resultados de items a su representación de la versión 2
+func toV2(data any) any {
+	switch data := data.(type) {
+	case models.TodoItem:
+		return data.V2()
+	case []models.TodoItem:
+		items := make([]models.TodoItemV2, len(data))
+		for i, item := range data {
+			items[i] = item.V2()
+		}
+		return items
+	case models.ItemNode:
+		return data.V2()
+	case []models.ItemEvent:
+		events := make([]models.ItemEventV2, len(data))
+		for i, event := range data {
+			events[i] = event.V2()
+		}
+		return events
+	default:
+		return data
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupV2Router(rules config.ItemRules) (*gin.Engine, *ItemHandler) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler := NewItemHandler(repository.NewMockRepository(), rules)
+
+	v2 := router.Group("/v2", middleware.APIVersion(constants.APIVersion2))
+	v2.GET("/items/:id", handler.GetItemByID)
+	v2.PUT("/items/:id", handler.UpdateItem)
+	v2.DELETE("/items/:id", handler.DeleteItem)
+	v2.GET("/items/:id/history", handler.GetHistory)
+	v2.GET("/items/:id/subtree", handler.GetSubtree)
+	return router, handler
+}
+
+func TestV2_ItemHasTypedTimestamps(t *testing.T) {
+	router, handler := setupV2Router(config.DefaultItemRules())
+	dueAt := "2025-11-01T10:00:00-03:00"
+	handler.repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending, DueAt: &dueAt, CreatedAt: "2025-10-01T08:00:00.5-03:00"})
+
+	req, _ := http.NewRequest("GET", "/v2/items/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response map[string]json.RawMessage
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NotContains(t, response, "message")
+	assert.NotContains(t, response, "meta")
+
+	var item map[string]interface{}
+	json.Unmarshal(response["data"], &item)
+	// Las fechas se normalizan a UTC y los opcionales sin valor vienen en null
+	assert.Equal(t, "2025-11-01T13:00:00Z", item["due_at"])
+	assert.Contains(t, item, "start_at")
+	assert.Nil(t, item["start_at"])
+	assert.Nil(t, item["project_id"])
+	assert.Equal(t, []interface{}{}, item["tags"])
+
+	var typed struct {
+		Data models.TodoItemV2 `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &typed))
+	assert.Equal(t, time.Date(2025, time.October, 1, 11, 0, 0, 5e8, time.UTC), typed.Data.CreatedAt)
+}
+
+func TestV2_WarningsAndPaginationInMeta(t *testing.T) {
+	rules := config.DefaultItemRules()
+	rules.Dependencies.Enforcement = config.EnforcementWarn
+	router, handler := setupV2Router(rules)
+	handler.repo.Create(models.TodoItem{Title: "A", State: constants.StatePending})
+	handler.repo.Create(models.TodoItem{Title: "B", State: constants.StatePending})
+	handler.repo.AddDependency(2, 1)
+
+	req, _ := http.NewRequest("PUT", "/v2/items/2", bytes.NewBufferString(`{"title": "B", "state": "in_progress"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data models.TodoItemV2 `json:"data"`
+		Meta struct {
+			Warnings   []string    `json:"warnings"`
+			Pagination *pagination `json:"pagination"`
+		} `json:"meta"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.StateInProgress, response.Data.State)
+	assert.Equal(t, []string{constants.ItemBloqueado}, response.Meta.Warnings)
+
+	req, _ = http.NewRequest("GET", "/v2/items/2/history?page_size=1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var history struct {
+		Data []models.ItemEventV2 `json:"data"`
+		Meta struct {
+			Pagination pagination `json:"pagination"`
+		} `json:"meta"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &history)
+	if assert.Len(t, history.Data, 1) {
+		assert.False(t, history.Data[0].CreatedAt.IsZero())
+	}
+	assert.Equal(t, pagination{Page: 1, PageSize: 1, Total: 2}, history.Meta.Pagination)
+}
+
+func TestV2_SubtreeAndDelete(t *testing.T) {
+	router, handler := setupV2Router(config.DefaultItemRules())
+	root := createChild(handler, "Raíz", constants.StatePending, "")
+	createChild(handler, "Hija", constants.StatePending, root.ID)
+
+	req, _ := http.NewRequest("GET", "/v2/items/1/subtree", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var tree struct {
+		Data models.ItemNodeV2 `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &tree)
+	if assert.Len(t, tree.Data.Children, 1) {
+		assert.Equal(t, "Hija", tree.Data.Children[0].Title)
+		assert.Equal(t, []models.ItemNodeV2{}, tree.Data.Children[0].Children)
+	}
+
+	req, _ = http.NewRequest("DELETE", "/v2/items/2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/policy"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupRoleRouter monta las rutas de items, proyectos, etiquetas y usuarios con
+// tres usuarios: 1 (admin), 2 (member) y 3 (viewer). Como Authenticate, carga el
+// rol y las membresías del usuario del header X-Test-User en cada petición.
+func setupRoleRouter() (*gin.Engine, *repository.MockRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	for _, user := range []models.User{
+		{Email: "ana@example.com", Name: "Ana", Role: constants.RoleAdmin},
+		{Email: "luis@example.com", Name: "Luis", Role: constants.RoleMember},
+		{Email: "eva@example.com", Name: "Eva", Role: constants.RoleViewer},
+	} {
+		repo.CreateUser(user)
+	}
+	items := NewItemHandler(repo, config.DefaultItemRules())
+	projects := NewProjectHandler(repo, items)
+	tags := NewTagHandler(repo)
+	users := NewUserHandler(repo)
+
+	router.Use(func(c *gin.Context) {
+		if value := c.GetHeader("X-Test-User"); value != "" {
+			id, _ := strconv.Atoi(value)
+			user, _ := repo.GetUser(id)
+			user.ProjectRoles, _ = repo.GetUserProjectRoles(value)
+			c.Set(middleware.UserKey, user)
+			c.Set(middleware.ScopesKey, policy.Scopes(user.Role, user.ProjectRoles))
+		}
+	})
+	router.POST("/items", items.CreateItem)
+	router.PUT("/items/:id", items.UpdateItem)
+	router.DELETE("/items/:id", items.DeleteItem)
+	router.POST("/projects", projects.CreateProject)
+	router.DELETE("/projects/:id", projects.DeleteProject)
+	router.POST("/projects/:id/items", projects.CreateProjectItem)
+	router.GET("/projects/:id/members", projects.GetProjectMembers)
+	router.PUT("/projects/:id/members/:user_id", projects.SetProjectMember)
+	router.DELETE("/projects/:id/members/:user_id", projects.RemoveProjectMember)
+	router.POST("/tags", tags.CreateTag)
+	router.DELETE("/tags/:id", tags.DeleteTag)
+	router.PUT("/users/:id/role", users.SetUserRole)
+	return router, repo
+}
+
+func decodeError(w *httptest.ResponseRecorder) map[string]interface{} {
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return response
+}
+
+func TestRoles_GlobalPermissions(t *testing.T) {
+	router, _ := setupRoleRouter()
+	item := `{"title": "Tarea", "state": "pending"}`
+
+	// viewer: no crea items, etiquetas ni proyectos
+	w := sendAs(router, "3", "POST", "/items", item)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	decodeProblem(t, w, constants.ErrCodePermissionDenied)
+	w = sendAs(router, "3", "POST", "/tags", `{"name": "backend"}`)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	problem := decodeProblem(t, w, constants.ErrCodePermissionDenied)
+	assert.Equal(t, constants.PermisoInsuficiente, problem.Title)
+	assert.Contains(t, problem.Detail, constants.PermissionWrite)
+	w = sendAs(router, "3", "POST", "/projects", `{"name": "Backend"}`)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+
+	// member: crea y modifica, pero no elimina
+	w = sendAs(router, "2", "POST", "/items", item)
+	assert.Equal(t, constants.StatusCreated, w.Code)
+	w = sendAs(router, "2", "PUT", "/items/1", `{"title": "Cambiada", "state": "pending"}`)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	w = sendAs(router, "2", "DELETE", "/items/1", "")
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	decodeProblem(t, w, constants.ErrCodePermissionDenied)
+	w = sendAs(router, "2", "POST", "/tags", `{"name": "backend"}`)
+	assert.Equal(t, constants.StatusCreated, w.Code)
+	w = sendAs(router, "2", "DELETE", "/tags/1", "")
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+
+	// admin: elimina items de otros usuarios
+	w = sendAs(router, "1", "DELETE", "/items/1", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	w = sendAs(router, "1", "DELETE", "/tags/1", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+}
+
+func TestRoles_ProjectMembership(t *testing.T) {
+	router, _ := setupRoleRouter()
+	sendAs(router, "1", "POST", "/projects", `{"name": "Backend"}`)
+	item := `{"title": "Tarea", "state": "pending"}`
+
+	// El member no administra los miembros del proyecto
+	w := sendAs(router, "2", "PUT", "/projects/1/members/3", `{"role": "member"}`)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	assert.Contains(t, decodeProblem(t, w, constants.ErrCodePermissionDenied).Detail, constants.PermissionManage)
+
+	// Como member del proyecto, el viewer puede crear items en él y solo en él
+	w = sendAs(router, "1", "PUT", "/projects/1/members/3", `{"role": "member"}`)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	w = sendAs(router, "3", "POST", "/projects/1/items", item)
+	assert.Equal(t, constants.StatusCreated, w.Code)
+	w = sendAs(router, "3", "POST", "/items", item)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+
+	// Como admin del proyecto, el member elimina sus items y administra sus miembros
+	sendAs(router, "1", "PUT", "/projects/1/members/2", `{"role": "admin"}`)
+	w = sendAs(router, "2", "DELETE", "/items/1", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	w = sendAs(router, "2", "DELETE", "/projects/1/members/3", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	w = sendAs(router, "3", "POST", "/projects/1/items", item)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+
+	// El rol del proyecto también puede restringir: un member que es viewer en él
+	sendAs(router, "1", "PUT", "/projects/1/members/2", `{"role": "viewer"}`)
+	w = sendAs(router, "2", "POST", "/projects/1/items", item)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	w = sendAs(router, "2", "POST", "/items", item)
+	assert.Equal(t, constants.StatusCreated, w.Code)
+}
+
+func TestProjectMembers(t *testing.T) {
+	router, _ := setupRoleRouter()
+	sendAs(router, "1", "POST", "/projects", `{"name": "Backend"}`)
+
+	w := sendAs(router, "1", "PUT", "/projects/1/members/2", `{"role": "viewer"}`)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	w = sendAs(router, "1", "PUT", "/projects/1/members/2", `{"role": "admin"}`)
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	w = sendAs(router, "3", "GET", "/projects/1/members", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	var response struct {
+		Message string                 `json:"message"`
+		Data    []models.ProjectMember `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.MiembrosObtenidos, response.Message)
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, "2", response.Data[0].UserID)
+		assert.Equal(t, constants.RoleAdmin, response.Data[0].Role)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+		error  string
+	}{
+		{"PUT", "/projects/1/members/2", `{"role": "owner"}`, constants.StatusBadRequest, constants.RolInvalido},
+		{"PUT", "/projects/1/members/99", `{"role": "member"}`, constants.StatusNotFound, constants.UsuarioNoEncontrado},
+		{"PUT", "/projects/99/members/2", `{"role": "member"}`, constants.StatusNotFound, constants.ProyectoNoEncontrado},
+		{"PUT", "/projects/1/members/ana", `{"role": "member"}`, constants.StatusBadRequest, constants.IDUsuarioInvalido},
+		{"DELETE", "/projects/1/members/3", "", constants.StatusNotFound, constants.MiembroNoEncontrado},
+	}
+	for _, tt := range tests {
+		w := sendAs(router, "1", tt.method, tt.path, tt.body)
+		assert.Equal(t, tt.status, w.Code, tt.path)
+		assert.Equal(t, tt.error, decodeError(w)["error"], tt.path)
+	}
+
+	// Quitar al miembro
+	w = sendAs(router, "1", "DELETE", "/projects/1/members/2", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Equal(t, constants.MiembroEliminado, decodeError(w)["message"])
+}
+
+func TestSetUserRole(t *testing.T) {
+	router, repo := setupRoleRouter()
+
+	w := sendAs(router, "1", "PUT", "/users/3/role", `{"role": "member"}`)
+	assert.Equal(t, constants.StatusOK, w.Code)
+	var response struct {
+		Message string      `json:"message"`
+		Data    models.User `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.RolActualizado, response.Message)
+	assert.Equal(t, constants.RoleMember, response.Data.Role)
+
+	user, _ := repo.GetUser(3)
+	assert.Equal(t, constants.RoleMember, user.Role)
+
+	// Desde la próxima petición ya tiene los permisos del nuevo rol
+	w = sendAs(router, "3", "POST", "/items", `{"title": "Tarea", "state": "pending"}`)
+	assert.Equal(t, constants.StatusCreated, w.Code)
+
+	tests := []struct {
+		path   string
+		body   string
+		status int
+		error  string
+	}{
+		{"/users/3/role", `{"role": "owner"}`, constants.StatusBadRequest, constants.RolInvalido},
+		{"/users/3/role", `{}`, constants.StatusBadRequest, constants.CuerpoInvalido},
+		{"/users/99/role", `{"role": "viewer"}`, constants.StatusNotFound, constants.UsuarioNoEncontrado},
+		{"/users/ana/role", `{"role": "viewer"}`, constants.StatusBadRequest, constants.IDUsuarioInvalido},
+	}
+	for _, tt := range tests {
+		w := sendAs(router, "1", "PUT", tt.path, tt.body)
+		assert.Equal(t, tt.status, w.Code, tt.path+" "+tt.body)
+		assert.Equal(t, tt.error, decodeError(w)["error"], tt.path+" "+tt.body)
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/policy"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupShareRouter monta las rutas de items y de compartidos con cuatro
+// usuarios: 1 (admin), 2 y 3 (member) y 4 (viewer). Como Authenticate, carga
+// los roles y los compartidos del usuario del header X-Test-User en cada petición.
+// El usuario 2 crea el item 1 y el proyecto 1, con el item 2 adentro.
+func setupShareRouter(t *testing.T) (*gin.Engine, *repository.MockRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	for _, user := range []models.User{
+		{Email: "ana@example.com", Name: "Ana", Role: constants.RoleAdmin},
+		{Email: "luis@example.com", Name: "Luis", Role: constants.RoleMember},
+		{Email: "sol@example.com", Name: "Sol", Role: constants.RoleMember},
+		{Email: "eva@example.com", Name: "Eva", Role: constants.RoleViewer},
+	} {
+		repo.CreateUser(user)
+	}
+	items := NewItemHandler(repo, config.DefaultItemRules())
+	projects := NewProjectHandler(repo, items)
+	shares := NewShareHandler(repo, repo, items)
+
+	router.Use(func(c *gin.Context) {
+		if value := c.GetHeader("X-Test-User"); value != "" {
+			id, _ := strconv.Atoi(value)
+			user, _ := repo.GetUser(id)
+			user.ProjectRoles, _ = repo.GetUserProjectRoles(value)
+			user.Shares, _ = repo.GetUserShares(value)
+			c.Set(middleware.UserKey, user)
+			c.Set(middleware.ScopesKey, policy.Scopes(user.Role, user.ProjectRoles))
+		}
+	})
+	router.PUT("/items/:id", items.UpdateItem)
+	router.DELETE("/items/:id", items.DeleteItem)
+	router.POST("/items", items.CreateItem)
+	router.POST("/projects", projects.CreateProject)
+	router.POST("/projects/:id/items", projects.CreateProjectItem)
+	router.GET("/shares", shares.GetShares)
+	router.POST("/shares", shares.CreateShare)
+	router.GET("/shares/received", shares.GetReceivedShares)
+	router.POST("/shares/:id/revoke", shares.RevokeShare)
+	router.GET("/shared/:token", shares.GetSharedContent)
+	router.PUT("/shared/:token/items/:id", shares.UpdateSharedItem)
+
+	assert.Equal(t, constants.StatusCreated, sendAs(router, "2", "POST", "/items", `{"title": "Tarea de Luis", "state": "pending"}`).Code)
+	assert.Equal(t, constants.StatusCreated, sendAs(router, "2", "POST", "/projects", `{"name": "Backend"}`).Code)
+	assert.Equal(t, constants.StatusCreated, sendAs(router, "2", "POST", "/projects/1/items", `{"title": "Tarea del proyecto", "state": "pending"}`).Code)
+	return router, repo
+}
+
+// createShare crea el compartido y lo devuelve, con el token si es un enlace
+func createShare(t *testing.T, router *gin.Engine, userID string, body string) models.Share {
+	t.Helper()
+	w := sendAs(router, userID, "POST", "/shares", body)
+	if !assert.Equal(t, constants.StatusCreated, w.Code, w.Body.String()) {
+		t.FailNow()
+	}
+	var response struct {
+		Data models.Share `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Data
+}
+
+const editedItem = `{"title": "Editada", "state": "pending"}`
+
+func TestShares_UserEditShare(t *testing.T) {
+	router, _ := setupShareRouter(t)
+
+	// Sol (member) no puede modificar un item que no creó ni tiene asignado
+	w := sendAs(router, "3", "PUT", "/items/1", editedItem)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	decodeProblem(t, w, constants.ErrCodeItemForbidden)
+
+	// Un compartido read con un usuario no tendría efecto, porque ya lee todos
+	// los items; con uno edit puede modificarlo
+	w = sendAs(router, "2", "POST", "/shares", `{"item_id": "1", "user_id": "3", "permission": "read"}`)
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+	problem := decodeProblem(t, w, constants.ErrCodeValidationFailed)
+	assert.Equal(t, constants.LecturaCompartidaConUsuario, problem.Detail)
+	share := createShare(t, router, "2", `{"item_id": "1", "user_id": "3", "permission": "edit"}`)
+	assert.Empty(t, share.Token)
+	w = sendAs(router, "3", "PUT", "/items/1", editedItem)
+	assert.Equal(t, constants.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "Editada", decodeItem(w).Title)
+
+	// El compartido no permite eliminar el item ni modificar otros
+	w = sendAs(router, "3", "DELETE", "/items/1", "")
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	assert.Equal(t, constants.StatusForbidden, sendAs(router, "3", "PUT", "/items/2", editedItem).Code)
+
+	w = sendAs(router, "3", "GET", "/shares/received", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"permission":"edit"`)
+
+	// Revocado deja de valer, y no se revoca dos veces
+	assert.Equal(t, constants.StatusOK, sendAs(router, "2", "POST", "/shares/"+share.ID+"/revoke", "").Code)
+	assert.Equal(t, constants.StatusForbidden, sendAs(router, "3", "PUT", "/items/1", editedItem).Code)
+	w = sendAs(router, "2", "POST", "/shares/"+share.ID+"/revoke", "")
+	assert.Equal(t, constants.StatusConflict, w.Code)
+	assert.Equal(t, constants.CompartidoYaRevocado, decodeProblem(t, w, constants.ErrCodeShareRevoked).Title)
+}
+
+func TestShares_ProjectEditShareForViewer(t *testing.T) {
+	router, _ := setupShareRouter(t)
+
+	// Eva (viewer) no escribe en ningún proyecto, salvo en el que le compartieron con edit
+	assert.Equal(t, constants.StatusForbidden, sendAs(router, "4", "PUT", "/items/2", editedItem).Code)
+	assert.Equal(t, constants.StatusForbidden, sendAs(router, "2", "POST", "/shares", `{"project_id": "1", "user_id": "4", "permission": "edit"}`).Code,
+		"los compartidos de un proyecto exigen el permiso manage")
+	createShare(t, router, "1", `{"project_id": "1", "user_id": "4", "permission": "edit"}`)
+
+	assert.Equal(t, constants.StatusOK, sendAs(router, "4", "PUT", "/items/2", editedItem).Code)
+	assert.Equal(t, constants.StatusForbidden, sendAs(router, "4", "PUT", "/items/1", editedItem).Code)
+	assert.Equal(t, constants.StatusForbidden, sendAs(router, "4", "DELETE", "/items/2", "").Code)
+
+	w := sendAs(router, "1", "GET", "/shares?project_id=1", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"user_id":"4"`)
+}
+
+func TestShares_ManagementRequiresOwnership(t *testing.T) {
+	router, repo := setupShareRouter(t)
+
+	// Solo quien puede modificar el item (su creador, su responsable o un admin) lo comparte
+	w := sendAs(router, "3", "POST", "/shares", `{"item_id": "1", "permission": "edit"}`)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	decodeProblem(t, w, constants.ErrCodeItemForbidden)
+	w = sendAs(router, "4", "POST", "/shares", `{"item_id": "1", "permission": "read"}`)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	decodeProblem(t, w, constants.ErrCodePermissionDenied)
+
+	// Lo que le compartieron no le permite volver a compartirlo ni ver sus compartidos
+	share := createShare(t, router, "2", `{"item_id": "1", "user_id": "3", "permission": "edit"}`)
+	assert.Equal(t, constants.StatusForbidden, sendAs(router, "3", "POST", "/shares", `{"item_id": "1", "permission": "edit"}`).Code)
+	assert.Equal(t, constants.StatusForbidden, sendAs(router, "3", "GET", "/shares?item_id=1", "").Code)
+	assert.Equal(t, constants.StatusForbidden, sendAs(router, "3", "POST", "/shares/"+share.ID+"/revoke", "").Code)
+	assert.Equal(t, constants.StatusOK, sendAs(router, "1", "POST", "/shares/"+share.ID+"/revoke", "").Code)
+
+	// Validaciones
+	for _, body := range []string{
+		`{"item_id": "1", "permission": "owner"}`,
+		`{"permission": "read"}`,
+		`{"item_id": "1", "project_id": "1", "permission": "read"}`,
+		`{"item_id": "1", "permission": "read", "expires_at": "2000-01-01T00:00:00Z"}`,
+		`{"item_id": "1", "permission": "read", "expires_at": "mañana"}`,
+		`{"item_id": "1", "user_id": "uno", "permission": "read"}`,
+	} {
+		assert.Equal(t, constants.StatusBadRequest, sendAs(router, "2", "POST", "/shares", body).Code, body)
+	}
+	assert.Equal(t, constants.StatusNotFound, sendAs(router, "2", "POST", "/shares", `{"item_id": "99", "permission": "read"}`).Code)
+	assert.Equal(t, constants.StatusNotFound, sendAs(router, "2", "POST", "/shares", `{"item_id": "1", "user_id": "99", "permission": "edit"}`).Code)
+	assert.Equal(t, constants.StatusBadRequest, sendAs(router, "2", "GET", "/shares", "").Code)
+	assert.Equal(t, constants.StatusUnauthorized, sendAs(router, "", "GET", "/shares/received", "").Code)
+
+	// Los errores de la base de datos se registran, pero no se devuelven
+	repo.SimulateError(true)
+	w = sendAs(router, "", "GET", "/shared/"+constants.ShareTokenPrefix+"desconocido", "")
+	assert.Equal(t, constants.StatusInternalServerError, w.Code)
+	problem := decodeProblem(t, w, constants.ErrCodeInternal)
+	assert.NotContains(t, problem.Title+problem.Detail, "simulated")
+}
+
+func TestShares_Links(t *testing.T) {
+	router, repo := setupShareRouter(t)
+
+	readLink := createShare(t, router, "2", `{"item_id": "1", "permission": "read"}`)
+	assert.Regexp(t, "^"+constants.ShareTokenPrefix+"[0-9a-f]{64}$", readLink.Token)
+	assert.Equal(t, readLink.Token[:len(readLink.Prefix)], readLink.Prefix)
+
+	// Sin autenticarse se ve el item, pero un enlace read no lo modifica
+	w := sendAs(router, "", "GET", "/shared/"+readLink.Token, "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Tarea de Luis")
+	w = sendAs(router, "", "PUT", "/shared/"+readLink.Token+"/items/1", editedItem)
+	assert.Equal(t, constants.StatusForbidden, w.Code)
+	decodeProblem(t, w, constants.ErrCodeShareReadOnly)
+
+	// Un enlace edit del proyecto modifica solo sus items, y figura en el historial
+	editLink := createShare(t, router, "1", `{"project_id": "1", "permission": "edit"}`)
+	w = sendAs(router, "", "GET", "/shared/"+editLink.Token, "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Tarea del proyecto")
+	assert.NotContains(t, w.Body.String(), "Tarea de Luis")
+	w = sendAs(router, "", "PUT", "/shared/"+editLink.Token+"/items/2", editedItem)
+	assert.Equal(t, constants.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "Editada", decodeItem(w).Title)
+	events, _, _ := repo.GetHistory(2, 1, 10)
+	assert.Equal(t, editLink.Prefix, events[0].Actor)
+	assert.Equal(t, constants.StatusNotFound, sendAs(router, "", "PUT", "/shared/"+editLink.Token+"/items/1", editedItem).Code)
+
+	// Las validaciones son las de PUT /items/:id
+	w = sendAs(router, "", "PUT", "/shared/"+editLink.Token+"/items/2", `{"title": "", "state": "pending"}`)
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+	decodeProblem(t, w, constants.ErrCodeValidationFailed)
+
+	// Revocado o con un token desconocido, el enlace no existe
+	assert.Equal(t, constants.StatusOK, sendAs(router, "1", "POST", "/shares/"+editLink.ID+"/revoke", "").Code)
+	assert.Equal(t, constants.StatusNotFound, sendAs(router, "", "GET", "/shared/"+editLink.Token, "").Code)
+	assert.Equal(t, constants.StatusNotFound, sendAs(router, "", "PUT", "/shared/"+editLink.Token+"/items/2", editedItem).Code)
+	w = sendAs(router, "", "GET", "/shared/"+constants.ShareTokenPrefix+"desconocido", "")
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+	decodeProblem(t, w, constants.ErrCodeShareLinkNotFound)
+}
+
+func TestShares_Expiry(t *testing.T) {
+	router, repo := setupShareRouter(t)
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	link := createShare(t, router, "2", fmt.Sprintf(`{"item_id": "1", "permission": "edit", "expires_at": "%s"}`, expiresAt))
+	userShare := createShare(t, router, "2", fmt.Sprintf(`{"item_id": "1", "user_id": "3", "permission": "edit", "expires_at": "%s"}`, expiresAt))
+	assert.Equal(t, constants.StatusOK, sendAs(router, "", "GET", "/shared/"+link.Token, "").Code)
+	assert.Equal(t, constants.StatusOK, sendAs(router, "3", "PUT", "/items/1", editedItem).Code)
+
+	// Vencidos dejan de valer, aunque sigan listados
+	repo.ExpireShare(link.ID, time.Now())
+	repo.ExpireShare(userShare.ID, time.Now())
+	assert.Equal(t, constants.StatusNotFound, sendAs(router, "", "GET", "/shared/"+link.Token, "").Code)
+	assert.Equal(t, constants.StatusForbidden, sendAs(router, "3", "PUT", "/items/1", editedItem).Code)
+	assert.Contains(t, sendAs(router, "3", "GET", "/shares/received", "").Body.String(), `"data":[]`)
+
+	w := sendAs(router, "2", "GET", "/shares?item_id=1", "")
+	assert.Equal(t, constants.StatusOK, w.Code)
+	var response struct {
+		Data []models.Share `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+}
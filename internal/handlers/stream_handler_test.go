@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupStreamServer(t *testing.T, replayBuffer int) (*httptest.Server, repository.IRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := events.NewHub(events.NewMemoryPubSub(), replayBuffer)
+	go hub.Run(ctx)
+	// Esperamos a que el hub se suscriba antes de publicar
+	time.Sleep(20 * time.Millisecond)
+
+	repo := events.NewPublishingRepository(repository.NewMockRepository(), hub)
+	itemHandler := NewItemHandler(repo, config.DefaultItemRules())
+	streamHandler := NewStreamHandler(hub, time.Minute)
+
+	router.POST("/items", itemHandler.CreateItem)
+	router.GET("/items/stream", streamHandler.StreamItems)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(func() {
+		server.Close()
+		cancel()
+	})
+	return server, repo
+}
+
+// sseMessage es un evento leído del stream
+type sseMessage struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// openStream se conecta al stream y devuelve una función que lee el próximo evento
+func openStream(t *testing.T, url string, lastEventID string) func() sseMessage {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if lastEventID != "" {
+		req.Header.Set(constants.LastEventIDHeader, lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	return func() sseMessage {
+		var message sseMessage
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("stream cerrado: %v", err)
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case line == "" && message.Event != "":
+				return message
+			case strings.HasPrefix(line, "id:"):
+				message.ID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+			case strings.HasPrefix(line, "event:"):
+				message.Event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			case strings.HasPrefix(line, "data:"):
+				message.Data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			}
+		}
+	}
+}
+
+func TestStreamItems_LiveEvents(t *testing.T) {
+	server, _ := setupStreamServer(t, 10)
+	next := openStream(t, server.URL+"/items/stream", "")
+
+	req, _ := http.NewRequest("POST", server.URL+"/items", bytes.NewBufferString(`{"title": "Task 1", "state": "pending"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	message := next()
+	assert.Equal(t, constants.WebhookItemCreated, message.Event)
+	assert.Equal(t, "1", message.ID)
+
+	var event events.Event
+	json.Unmarshal([]byte(message.Data), &event)
+	assert.Equal(t, "Task 1", event.Item.Title)
+}
+
+func TestStreamItems_FilterByState(t *testing.T) {
+	server, repo := setupStreamServer(t, 10)
+	next := openStream(t, server.URL+"/items/stream?state=completed", "")
+
+	repo.Create(models.TodoItem{Title: "Pendiente", State: constants.StatePending})
+	repo.Create(models.TodoItem{Title: "Completada", State: constants.StateCompleted})
+
+	var event events.Event
+	json.Unmarshal([]byte(next().Data), &event)
+	assert.Equal(t, "Completada", event.Item.Title)
+}
+
+func TestStreamItems_ResumeFromLastEventID(t *testing.T) {
+	server, repo := setupStreamServer(t, 10)
+	repo.Create(models.TodoItem{Title: "A", State: constants.StatePending})
+	repo.Create(models.TodoItem{Title: "B", State: constants.StatePending})
+	repo.Update(models.TodoItem{ID: "1", Title: "A2", State: constants.StatePending})
+
+	next := openStream(t, server.URL+"/items/stream", "1")
+
+	message := next()
+	assert.Equal(t, "2", message.ID)
+	assert.Equal(t, constants.WebhookItemCreated, message.Event)
+
+	message = next()
+	assert.Equal(t, "3", message.ID)
+	assert.Equal(t, constants.WebhookItemUpdated, message.Event)
+}
+
+func TestStreamItems_AssignmentEvents(t *testing.T) {
+	server, repo := setupStreamServer(t, 10)
+	mock := repo.(*events.PublishingRepository).IRepository.(*repository.MockRepository)
+	user, _ := mock.CreateUser(models.User{Email: "ana@example.com", Name: "Ana"})
+	repo.Create(models.TodoItem{Title: "Tarea", State: constants.StatePending})
+
+	next := openStream(t, server.URL+"/items/stream", "1")
+	assert.NoError(t, repo.AssignItem(1, &user.ID))
+	assert.NoError(t, repo.AssignItem(1, nil))
+
+	var event events.Event
+	message := next()
+	assert.Equal(t, constants.WebhookItemUpdated, message.Event)
+	json.Unmarshal([]byte(message.Data), &event)
+	if assert.NotNil(t, event.Item.AssigneeID) {
+		assert.Equal(t, user.ID, *event.Item.AssigneeID)
+	}
+
+	message = next()
+	assert.Equal(t, constants.WebhookItemUpdated, message.Event)
+	event = events.Event{}
+	json.Unmarshal([]byte(message.Data), &event)
+	assert.Nil(t, event.Item.AssigneeID)
+}
+
+func TestStreamItems_ResetWhenBufferOverflowed(t *testing.T) {
+	server, repo := setupStreamServer(t, 2)
+	for _, title := range []string{"A", "B", "C", "D"} {
+		repo.Create(models.TodoItem{Title: title, State: constants.StatePending})
+	}
+
+	next := openStream(t, server.URL+"/items/stream", "1")
+
+	assert.Equal(t, constants.StreamReset, next().Event)
+	assert.Equal(t, "3", next().ID)
+	assert.Equal(t, "4", next().ID)
+}
+
+func TestStreamItems_InvalidLastEventID(t *testing.T) {
+	server, _ := setupStreamServer(t, 10)
+
+	req, _ := http.NewRequest("GET", server.URL+"/items/stream", nil)
+	req.Header.Set(constants.LastEventIDHeader, "abc")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, constants.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTagRouter() (*gin.Engine, *TagHandler, *repository.MockRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	handler := NewTagHandler(repo)
+	return router, handler, repo
+}
+
+func TestCreateTag_Success(t *testing.T) {
+	router, handler, _ := setupTagRouter()
+	router.POST("/tags", handler.CreateTag)
+
+	req, _ := http.NewRequest("POST", "/tags", bytes.NewBufferString(`{"name": " Backend "}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusCreated, w.Code)
+
+	var response struct {
+		Message string     `json:"message"`
+		Data    models.Tag `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.EtiquetaCreada, response.Message)
+	assert.Equal(t, "backend", response.Data.Name)
+}
+
+func TestCreateTag_Duplicate(t *testing.T) {
+	router, handler, repo := setupTagRouter()
+	router.POST("/tags", handler.CreateTag)
+
+	repo.CreateTag("bug")
+
+	req, _ := http.NewRequest("POST", "/tags", bytes.NewBufferString(`{"name": "BUG"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.EtiquetaDuplicada, response["error"])
+}
+
+func TestCreateTag_Invalid(t *testing.T) {
+	router, handler, _ := setupTagRouter()
+	router.POST("/tags", handler.CreateTag)
+
+	req, _ := http.NewRequest("POST", "/tags", bytes.NewBufferString(`{"name": "a,b"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.EtiquetaInvalida, response["error"])
+}
+
+func TestRenameTag_UpdatesItems(t *testing.T) {
+	router, handler, repo := setupTagRouter()
+	router.PUT("/tags/:id", handler.RenameTag)
+
+	repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending, Tags: []string{"q3"}})
+
+	req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(`{"name": "q4"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	item, _ := repo.Get(1)
+	assert.Equal(t, []string{"q4"}, item.Tags)
+}
+
+func TestRenameTag_NotFound(t *testing.T) {
+	router, handler, _ := setupTagRouter()
+	router.PUT("/tags/:id", handler.RenameTag)
+
+	req, _ := http.NewRequest("PUT", "/tags/99", bytes.NewBufferString(`{"name": "q4"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.EtiquetaNoEncontrada, response["error"])
+}
+
+func TestMergeTags_Success(t *testing.T) {
+	router, handler, repo := setupTagRouter()
+	router.POST("/tags/:id/merge", handler.MergeTags)
+
+	repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending, Tags: []string{"bugfix", "bug"}})
+	repo.Create(models.TodoItem{Title: "Task 2", State: constants.StatePending, Tags: []string{"bugfix"}})
+
+	req, _ := http.NewRequest("POST", "/tags/1/merge", bytes.NewBufferString(`{"target_id": 2}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	var response struct {
+		Data models.Tag `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "bug", response.Data.Name)
+	assert.Equal(t, 2, response.Data.ItemCount)
+
+	tags, _ := repo.GetAllTags()
+	assert.Len(t, tags, 1)
+	item, _ := repo.Get(1)
+	assert.Equal(t, []string{"bug"}, item.Tags)
+}
+
+func TestMergeTags_SameTag(t *testing.T) {
+	router, handler, repo := setupTagRouter()
+	router.POST("/tags/:id/merge", handler.MergeTags)
+
+	repo.CreateTag("bug")
+
+	req, _ := http.NewRequest("POST", "/tags/1/merge", bytes.NewBufferString(`{"target_id": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+}
+
+func TestDeleteTag_Success(t *testing.T) {
+	router, handler, repo := setupTagRouter()
+	router.DELETE("/tags/:id", handler.DeleteTag)
+
+	repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending, Tags: []string{"backend", "bug"}})
+
+	req, _ := http.NewRequest("DELETE", "/tags/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+
+	item, _ := repo.Get(1)
+	assert.Equal(t, []string{"bug"}, item.Tags)
+}
+
+func TestGetTags_DatabaseError(t *testing.T) {
+	router, handler, repo := setupTagRouter()
+	router.GET("/tags", handler.GetTags)
+
+	repo.SimulateError(true)
+
+	req, _ := http.NewRequest("GET", "/tags", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusInternalServerError, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, constants.ErrorBaseDatos, response["error"])
+}
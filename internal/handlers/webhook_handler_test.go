@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupWebhookRouter() (*gin.Engine, *repository.MockRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	repo := repository.NewMockRepository()
+	handler := NewWebhookHandler(repo)
+
+	router.GET("/webhooks", handler.GetWebhooks)
+	router.POST("/webhooks", handler.CreateWebhook)
+	router.GET("/webhooks/deliveries", handler.GetDeliveries)
+	router.POST("/webhooks/deliveries/:id/redeliver", handler.RedeliverDelivery)
+	router.GET("/webhooks/:id", handler.GetWebhookByID)
+	router.PUT("/webhooks/:id", handler.UpdateWebhook)
+	router.DELETE("/webhooks/:id", handler.DeleteWebhook)
+	return router, repo
+}
+
+func sendWebhook(router *gin.Engine, method string, path string, body string) (*httptest.ResponseRecorder, models.Webhook) {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Data models.Webhook `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response.Data
+}
+
+func TestCreateWebhook_Success(t *testing.T) {
+	router, _ := setupWebhookRouter()
+
+	w, webhook := sendWebhook(router, "POST", "/webhooks", `{"url": "https://bot.example.com/hook", "events": ["item.created", "item.completed"]}`)
+
+	assert.Equal(t, constants.StatusCreated, w.Code)
+	assert.Equal(t, "1", webhook.ID)
+	assert.True(t, webhook.Active)
+	assert.NotEmpty(t, webhook.Secret)
+	assert.Equal(t, []string{constants.WebhookItemCreated, constants.WebhookItemCompleted}, webhook.Events)
+}
+
+func TestCreateWebhook_Invalid(t *testing.T) {
+	router, _ := setupWebhookRouter()
+
+	w, _ := sendWebhook(router, "POST", "/webhooks", `{"url": "ftp://bot.example.com"}`)
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	w, _ = sendWebhook(router, "POST", "/webhooks", `{"url": "https://bot.example.com", "events": ["item.renamed"]}`)
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+
+	// Ni la propia máquina ni la red interna
+	for _, url := range []string{"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://10.0.0.5/hook", "http://169.254.169.254/latest/meta-data", "http://[::1]/hook", "http://[::ffff:192.168.1.1]/hook"} {
+		w, _ = sendWebhook(router, "POST", "/webhooks", `{"url": "`+url+`"}`)
+		assert.Equal(t, constants.StatusBadRequest, w.Code, url)
+		assert.Contains(t, w.Body.String(), constants.URLWebhookInterna, url)
+	}
+}
+
+func TestGetWebhook_HidesSecret(t *testing.T) {
+	router, _ := setupWebhookRouter()
+	sendWebhook(router, "POST", "/webhooks", `{"url": "https://bot.example.com/hook", "secret": "s3creto"}`)
+
+	w, webhook := sendWebhook(router, "GET", "/webhooks/1", "")
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Empty(t, webhook.Secret)
+}
+
+func TestUpdateWebhook_KeepsSecret(t *testing.T) {
+	router, repo := setupWebhookRouter()
+	sendWebhook(router, "POST", "/webhooks", `{"url": "https://bot.example.com/hook", "secret": "s3creto"}`)
+
+	w, webhook := sendWebhook(router, "PUT", "/webhooks/1", `{"url": "https://ci.example.com/hook", "active": false}`)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+	assert.Equal(t, "https://ci.example.com/hook", webhook.URL)
+	assert.False(t, webhook.Active)
+
+	stored, _ := repo.GetWebhook(1)
+	assert.Equal(t, "s3creto", stored.Secret)
+}
+
+func TestDeleteWebhook_NotFound(t *testing.T) {
+	router, _ := setupWebhookRouter()
+
+	w, _ := sendWebhook(router, "DELETE", "/webhooks/99", "")
+
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+}
+
+func TestGetDeliveries_FilterAndRedeliver(t *testing.T) {
+	router, repo := setupWebhookRouter()
+	sendWebhook(router, "POST", "/webhooks", `{"url": "https://bot.example.com/hook"}`)
+	repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+	repo.FanOutOutbox(10)
+	repo.FailDelivery("1", nil, "connection refused", nil)
+
+	req, _ := http.NewRequest("GET", "/webhooks/deliveries?status=dead", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+	var response struct {
+		Data []models.WebhookDelivery `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, "connection refused", response.Data[0].LastError)
+
+	req, _ = http.NewRequest("POST", "/webhooks/deliveries/1/redeliver", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusOK, w.Code)
+	var redelivered struct {
+		Data models.WebhookDelivery `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &redelivered)
+	assert.Equal(t, constants.DeliveryPending, redelivered.Data.Status)
+	assert.Equal(t, 0, redelivered.Data.Attempts)
+}
+
+func TestGetDeliveries_InvalidStatus(t *testing.T) {
+	router, _ := setupWebhookRouter()
+
+	req, _ := http.NewRequest("GET", "/webhooks/deliveries?status=lost", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusBadRequest, w.Code)
+}
+
+func TestRedeliver_NotFound(t *testing.T) {
+	router, _ := setupWebhookRouter()
+
+	req, _ := http.NewRequest("POST", "/webhooks/deliveries/99/redeliver", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, constants.StatusNotFound, w.Code)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/realtime"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupWebSocketServer(t *testing.T) (*httptest.Server, repository.IRepository) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := events.NewHub(events.NewMemoryPubSub(), 10)
+	go hub.Run(ctx)
+	// Esperamos a que el hub se suscriba antes de publicar
+	time.Sleep(20 * time.Millisecond)
+
+	repo := events.NewPublishingRepository(repository.NewMockRepository(), hub)
+	itemHandler := NewItemHandler(repo, config.DefaultItemRules())
+
+	mutations := gin.New()
+	mutations.Use(middleware.RequestMeta())
+	mutations.POST("/items", itemHandler.CreateItem)
+	mutations.PUT("/items/:id", itemHandler.UpdateItem)
+	mutations.DELETE("/items/:id", itemHandler.DeleteItem)
+	mutations.POST("/items/:id/transitions", itemHandler.TransitionItem)
+
+	webSocketHandler := NewWebSocketHandler(realtime.NewServer(hub, mutations, config.DefaultWebSocketConfig()))
+	router.GET("/ws", webSocketHandler.Connect)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(func() {
+		server.Close()
+		cancel()
+	})
+	return server, repo
+}
+
+// wsMessage es un mensaje recibido por el WebSocket
+type wsMessage struct {
+	Type     string            `json:"type"`
+	Ref      string            `json:"ref"`
+	ClientID string            `json:"client_id"`
+	Event    *events.Event     `json:"event"`
+	ItemID   string            `json:"item_id"`
+	Viewers  []realtime.Viewer `json:"viewers"`
+	Status   int               `json:"status"`
+	Body     json.RawMessage   `json:"body"`
+	Error    string            `json:"error"`
+}
+
+// dialWebSocket se conecta a /ws como actor y consume el mensaje de bienvenida
+func dialWebSocket(t *testing.T, server *httptest.Server, actor string) *websocket.Conn {
+	header := http.Header{}
+	if actor != "" {
+		header.Set(middleware.ActorHeader, actor)
+	}
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", header)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	assert.Equal(t, constants.WSWelcome, nextWSMessage(t, conn, constants.WSWelcome).Type)
+	return conn
+}
+
+// nextWSMessage devuelve el próximo mensaje del tipo indicado, ignorando los demás
+func nextWSMessage(t *testing.T, conn *websocket.Conn, messageType string) wsMessage {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var message wsMessage
+		if err := conn.ReadJSON(&message); err != nil {
+			t.Fatalf("no se recibió un mensaje %s: %v", messageType, err)
+		}
+		if message.Type == messageType {
+			return message
+		}
+	}
+}
+
+func TestWebSocket_CreateItemAndReceiveEvent(t *testing.T) {
+	server, _ := setupWebSocketServer(t)
+	conn := dialWebSocket(t, server, "ana")
+
+	conn.WriteJSON(gin.H{"type": constants.WSSubscribe})
+	conn.WriteJSON(gin.H{"type": constants.WSCreate, "ref": "r1", "data": gin.H{"title": "Task 1", "state": "pending"}})
+
+	result := nextWSMessage(t, conn, constants.WSResult)
+	assert.Equal(t, "r1", result.Ref)
+	assert.Equal(t, constants.StatusCreated, result.Status)
+	assert.Contains(t, string(result.Body), constants.ItemCreado)
+
+	event := nextWSMessage(t, conn, constants.WSEvent)
+	assert.Equal(t, constants.WebhookItemCreated, event.Event.Type)
+	assert.Equal(t, "Task 1", event.Event.Item.Title)
+}
+
+func TestWebSocket_MutationUsesRestValidation(t *testing.T) {
+	server, _ := setupWebSocketServer(t)
+	conn := dialWebSocket(t, server, "")
+
+	conn.WriteJSON(gin.H{"type": constants.WSCreate, "ref": "r1", "data": gin.H{"state": "pending"}})
+	result := nextWSMessage(t, conn, constants.WSResult)
+	assert.Equal(t, constants.StatusBadRequest, result.Status)
+
+	conn.WriteJSON(gin.H{"type": constants.WSUpdate, "ref": "r2", "item_id": "99", "data": gin.H{"title": "X", "state": "pending"}})
+	result = nextWSMessage(t, conn, constants.WSResult)
+	assert.Equal(t, "r2", result.Ref)
+	assert.Equal(t, constants.StatusNotFound, result.Status)
+
+	conn.WriteJSON(gin.H{"type": constants.WSDelete, "ref": "r3", "item_id": "1/restore"})
+	message := nextWSMessage(t, conn, constants.WSError)
+	assert.Equal(t, constants.IDInvalido, message.Error)
+}
+
+func TestWebSocket_SubscriptionFiltersItems(t *testing.T) {
+	server, repo := setupWebSocketServer(t)
+	conn := dialWebSocket(t, server, "")
+
+	first, _ := repo.Create(models.TodoItem{Title: "Primera", State: constants.StatePending})
+	second, _ := repo.Create(models.TodoItem{Title: "Segunda", State: constants.StatePending})
+
+	conn.WriteJSON(gin.H{"type": constants.WSSubscribe, "item_ids": []string{second.ID}})
+	// La presencia inicial confirma que la suscripción ya se procesó
+	nextWSMessage(t, conn, constants.WSPresence)
+
+	first.Title = "Primera editada"
+	repo.Update(first)
+	second.Title = "Segunda editada"
+	repo.Update(second)
+
+	event := nextWSMessage(t, conn, constants.WSEvent)
+	assert.Equal(t, second.ID, event.Event.ItemID)
+	assert.Equal(t, "Segunda editada", event.Event.Item.Title)
+}
+
+func TestWebSocket_Presence(t *testing.T) {
+	server, _ := setupWebSocketServer(t)
+	watcher := dialWebSocket(t, server, "luis")
+	viewer := dialWebSocket(t, server, "ana")
+
+	watcher.WriteJSON(gin.H{"type": constants.WSSubscribe, "item_ids": []string{"1"}})
+	presence := nextWSMessage(t, watcher, constants.WSPresence)
+	assert.Equal(t, "1", presence.ItemID)
+	assert.Empty(t, presence.Viewers)
+
+	viewer.WriteJSON(gin.H{"type": constants.WSView, "item_id": "1"})
+	presence = nextWSMessage(t, watcher, constants.WSPresence)
+	if assert.Len(t, presence.Viewers, 1) {
+		assert.Equal(t, "ana", presence.Viewers[0].Actor)
+	}
+
+	// Al desconectarse deja de aparecer como viendo el item
+	viewer.Close()
+	presence = nextWSMessage(t, watcher, constants.WSPresence)
+	assert.Empty(t, presence.Viewers)
+}
+
+func TestWebSocket_PingAndUnknownType(t *testing.T) {
+	server, _ := setupWebSocketServer(t)
+	conn := dialWebSocket(t, server, "")
+
+	conn.WriteJSON(gin.H{"type": constants.WSPing, "ref": "p1"})
+	assert.Equal(t, "p1", nextWSMessage(t, conn, constants.WSPong).Ref)
+
+	conn.WriteJSON(gin.H{"type": "otro"})
+	assert.Equal(t, constants.WSTipoDesconocido, nextWSMessage(t, conn, constants.WSError).Error)
+
+	conn.WriteMessage(websocket.TextMessage, []byte("no es json"))
+	assert.Equal(t, constants.WSMensajeInvalido, nextWSMessage(t, conn, constants.WSError).Error)
+}
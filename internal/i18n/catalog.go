@@ -0,0 +1,149 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Source es el idioma en que están escritos los mensajes de constants. Los
+// mensajes se usan como claves del catálogo, por lo que no necesita archivo.
+var Source = language.Spanish
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// Default es el catálogo con las traducciones de locales/. Para agregar un idioma
+// basta con un archivo <idioma>.json (por ejemplo pt.json) con los mensajes traducidos.
+var Default = mustLoad(locales, "locales")
+
+// Catalog guarda las traducciones de cada idioma, indexadas por el mensaje en español
+type Catalog struct {
+	languages []language.Tag
+	messages  map[language.Tag]map[string]string
+	matcher   language.Matcher
+}
+
+// Load lee las traducciones de los archivos <idioma>.json del directorio dir
+func Load(fsys fs.FS, dir string) (*Catalog, error) {
+	catalog := &Catalog{
+		languages: []language.Tag{Source},
+		messages:  map[language.Tag]map[string]string{Source: {}},
+	}
+
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(file), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if tag != Source {
+			catalog.languages = append(catalog.languages, tag)
+		}
+		catalog.messages[tag] = messages
+	}
+
+	catalog.matcher = language.NewMatcher(catalog.languages)
+	return catalog, nil
+}
+
+func mustLoad(fsys fs.FS, dir string) *Catalog {
+	catalog, err := Load(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// Languages devuelve los idiomas disponibles; el primero es Source
+func (c *Catalog) Languages() []language.Tag {
+	return c.languages
+}
+
+// Negotiate elige el idioma de la respuesta: el parámetro lang tiene prioridad
+// sobre la cabecera Accept-Language. Si ninguno coincide se usa Source.
+func (c *Catalog) Negotiate(lang, acceptLanguage string) language.Tag {
+	var desired []language.Tag
+	if tag, err := language.Parse(lang); err == nil {
+		desired = append(desired, tag)
+	}
+	// Un Accept-Language inválido se ignora, como si no se hubiera enviado
+	if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil {
+		desired = append(desired, tags...)
+	}
+	if len(desired) == 0 {
+		return Source
+	}
+
+	_, index, confidence := c.matcher.Match(desired...)
+	if confidence == language.No {
+		return Source
+	}
+	return c.languages[index]
+}
+
+// Localizer devuelve el traductor para el idioma indicado
+func (c *Catalog) Localizer(tag language.Tag) Localizer {
+	return Localizer{Tag: tag, messages: c.messages[tag]}
+}
+
+// Localizer traduce los mensajes a un idioma; los mensajes sin traducción se
+// devuelven en español
+type Localizer struct {
+	Tag      language.Tag
+	messages map[string]string
+}
+
+// Message traduce el mensaje; si recibe argumentos, el mensaje es un formato de fmt
+func (l Localizer) Message(key string, args ...any) string {
+	message, ok := l.messages[key]
+	if !ok {
+		message = key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Error traduce el mensaje del error. Los errores creados con Errorf se traducen
+// con sus argumentos; el resto, por su texto completo.
+func (l Localizer) Error(err error) string {
+	var localized *Error
+	if errors.As(err, &localized) {
+		return l.Message(localized.Key, localized.Args...)
+	}
+	return l.Message(err.Error())
+}
+
+// Error es un error con un mensaje de formato traducible y sus argumentos
+type Error struct {
+	Key  string
+	Args []any
+}
+
+// Errorf crea un error cuyo mensaje se traduce con Localizer.Error
+func Errorf(key string, args ...any) error {
+	return &Error{Key: key, Args: args}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf(e.Key, e.Args...)
+}
@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+// verb son los verbos de formato de fmt (%s, %d, ...) de un mensaje
+var verb = regexp.MustCompile(`%[a-z]`)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		lang           string
+		acceptLanguage string
+		expected       language.Tag
+	}{
+		{"sin preferencia", "", "", language.Spanish},
+		{"accept-language", "", "en-US,en;q=0.9", language.English},
+		{"calidad", "", "fr;q=1, en;q=0.8, es;q=0.5", language.English},
+		{"lang tiene prioridad", "es", "en", language.Spanish},
+		{"lang regional", "en-GB", "", language.English},
+		{"idioma sin traducción", "", "fr", language.Spanish},
+		{"lang inválido", "??", "en", language.English},
+		{"accept-language inválido", "", ";;;", language.Spanish},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Default.Negotiate(tt.lang, tt.acceptLanguage))
+		})
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	english := Default.Localizer(language.English)
+	spanish := Default.Localizer(Source)
+
+	assert.Equal(t, "Item not found", english.Message("Item no encontrado"))
+	assert.Equal(t, "Item no encontrado", spanish.Message("Item no encontrado"))
+	// Sin traducción se devuelve el mensaje original
+	assert.Equal(t, "sin traducción", english.Message("sin traducción"))
+	// Los mensajes sin argumentos no se interpretan como formato
+	assert.Equal(t, "100%", english.Message("100%"))
+
+	err := Errorf("campo de ordenamiento inválido: %s", "state")
+	assert.Equal(t, "campo de ordenamiento inválido: state", err.Error())
+	assert.Equal(t, "invalid sort field: state", english.Error(err))
+	assert.Equal(t, "campo de ordenamiento inválido: state", spanish.Error(err))
+}
+
+func TestLocalesKeepFormatVerbs(t *testing.T) {
+	assert.Contains(t, Default.Languages(), language.English)
+
+	for _, tag := range Default.Languages()[1:] {
+		for key, message := range Default.messages[tag] {
+			assert.NotEmpty(t, message, "%s: %q", tag, key)
+			assert.Equal(t, verb.FindAllString(key, -1), verb.FindAllString(message, -1), "%s: %q", tag, key)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// constantMessages devuelve los mensajes exportados del paquete constants: los
+// de los bloques const cuyo comentario empieza con "Mensajes"
+func constantMessages(t *testing.T) map[string]string {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, "../constants", nil, parser.ParseComments)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	messages := map[string]string{}
+	for _, file := range packages["constants"].Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST || gen.Doc == nil || !strings.HasPrefix(gen.Doc.Text(), "Mensajes") {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				value := spec.(*ast.ValueSpec)
+				for i, name := range value.Names {
+					literal, ok := value.Values[i].(*ast.BasicLit)
+					if !name.IsExported() || !ok || literal.Kind != token.STRING {
+						continue
+					}
+					message, _ := strconv.Unquote(literal.Value)
+					messages[name.Name] = message
+				}
+			}
+		}
+	}
+	return messages
+}
+
+func TestConstantMessagesAreTranslated(t *testing.T) {
+	messages := constantMessages(t)
+	assert.Contains(t, messages, "ItemNoEncontrado")
+
+	for _, tag := range Default.Languages()[1:] {
+		for name, message := range messages {
+			_, ok := Default.messages[tag][message]
+			assert.True(t, ok, "%s: falta la traducción de constants.%s (%q)", tag, name, message)
+		}
+	}
+}
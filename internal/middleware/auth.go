@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/policy"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// Claves del contexto de gin con la identidad de la petición: la API key
+// (models.APIKey) o el usuario (models.User) y los claims de su token de acceso
+// (*auth.Claims), y en ambos casos los scopes otorgados ([]string)
+const (
+	APIKeyKey = "api_key"
+	UserKey   = "user"
+	ClaimsKey = "token_claims"
+	ScopesKey = "scopes"
+)
+
+// Keys que no están en la base de datos: la de bootstrap y la que se usa cuando
+// la autenticación está deshabilitada
+var (
+	bootstrapKey = models.APIKey{Name: "bootstrap", Scopes: []string{constants.ScopeAdmin}}
+	anonymousKey = models.APIKey{Name: "anonymous", Scopes: []string{constants.ScopeAdmin}}
+)
+
+// Authenticate identifica a quien hace la petición, con la API key de la cabecera
+// X-API-Key o con el token de acceso de un usuario en Authorization: Bearer, y lo
+// guarda en el contexto; responde 401 si falta, no es válido o está revocado.
+// El token puede ser propio o, si oidc no es nil, de su proveedor de identidad.
+// Un usuario queda además como actor de los cambios que haga. Con la
+// autenticación deshabilitada toda petición usa una key anónima con scope admin.
+// Por último fija el tenant de la petición, el de las credenciales, y responde
+// 403 si no es el que pidió la petición (ver ResolveTenant y Authenticator).
+func Authenticate(authenticator *Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		credentials, err := authenticator.Authenticate(c.Request.Context(), c.GetHeader(constants.APIKeyHeader), BearerToken(c.GetHeader("Authorization")))
+		if err != nil {
+			abortAuthError(c, err)
+			return
+		}
+		requested, hasRequested := RequestedTenant(c)
+		tenant, err := authenticator.Tenant(credentials, requested, hasRequested)
+		if err != nil {
+			abortAuthError(c, err)
+			return
+		}
+
+		if credentials.User != nil {
+			c.Set(UserKey, *credentials.User)
+			c.Set(ActorKey, credentials.User.Email)
+		} else {
+			c.Set(APIKeyKey, *credentials.APIKey)
+		}
+		if credentials.Claims != nil {
+			c.Set(ClaimsKey, credentials.Claims)
+		}
+		c.Set(ScopesKey, credentials.Scopes)
+		c.Set(TenantKey, tenant)
+		c.Next()
+	}
+}
+
+// loadAccess completa los roles por proyecto y los compartidos vigentes del
+// usuario, que se leen en cada petición como su rol
+func loadAccess(users repository.IUserRepository, user *models.User) error {
+	var err error
+	if user.ProjectRoles, err = users.GetUserProjectRoles(user.ID); err != nil {
+		return err
+	}
+	user.Shares, err = users.GetUserShares(user.ID)
+	return err
+}
+
+// userScopes devuelve los scopes de las rutas del usuario según sus roles (ver
+// policy.Scopes), más items:write si algo le compartieron con permiso edit
+func userScopes(user models.User) []string {
+	scopes := policy.Scopes(user.Role, user.ProjectRoles)
+	if models.HasScope(scopes, constants.ScopeItemsWrite) {
+		return scopes
+	}
+	for _, share := range user.Shares {
+		if share.Permission == constants.SharePermissionEdit {
+			return append(scopes, constants.ScopeItemsWrite)
+		}
+	}
+	return scopes
+}
+
+// touch registra el uso de la key si el último registrado es más viejo que interval
+func touch(keys repository.IAPIKeyRepository, key models.APIKey, interval time.Duration) {
+	now := time.Now()
+	if key.LastUsedAt != nil {
+		if lastUsed, err := models.ParseTimestamp(*key.LastUsedAt); err == nil && now.Sub(lastUsed) < interval {
+			return
+		}
+	}
+	// Un fallo al registrar el uso no debe rechazar la petición
+	if err := keys.TouchAPIKey(key.ID, now); err != nil {
+		log.Printf("WARN: no se pudo registrar el uso de la API key %s: %v", key.ID, err)
+	}
+}
+
+func abortUnauthorized(c *gin.Context) {
+	c.Header("WWW-Authenticate", `APIKey header="`+constants.APIKeyHeader+`", Bearer`)
+	abortProblem(c, constants.StatusUnauthorized, constants.ErrCodeAuthRequired, constants.APIKeyRequerida, "")
+}
+
+// abortAuthError responde con el error de Authenticator, ver AuthErrorStatus
+func abortAuthError(c *gin.Context, err error) {
+	status, message := AuthErrorStatus(err)
+	switch {
+	case errors.Is(err, errCredentialsRequired):
+		abortUnauthorized(c)
+	case status == constants.StatusUnauthorized:
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		abortProblem(c, status, constants.ErrCodeInvalidToken, message, "")
+	case errors.Is(err, errTenantMismatch):
+		requested, _ := RequestedTenant(c)
+		abortProblem(c, status, constants.ErrCodeTenantMismatch, message, requested.Slug)
+	case errors.Is(err, errRegistrationClosed):
+		abortProblem(c, status, constants.ErrCodeRegistrationClosed, message, "")
+	default:
+		abortDatabaseError(c, err)
+	}
+}
+
+// abortDatabaseError registra el error con el ID de la petición y responde 500
+// sin su detalle, que no debe llegar a quien todavía no se autenticó
+func abortDatabaseError(c *gin.Context, err error) {
+	log.Printf("ERROR: %s %s (request_id=%s): %v", c.Request.Method, c.Request.URL.Path, c.GetString(RequestIDKey), err)
+	abortProblem(c, constants.StatusInternalServerError, constants.ErrCodeInternal, constants.ErrorBaseDatos, "")
+}
+
+// CurrentUser devuelve el usuario autenticado con un token de acceso, si lo hay
+func CurrentUser(c *gin.Context) (models.User, bool) {
+	value, exists := c.Get(UserKey)
+	if !exists {
+		return models.User{}, false
+	}
+	user, ok := value.(models.User)
+	return user, ok
+}
+
+// SetUserAccess completa meta con el usuario autenticado con un token de acceso,
+// sus roles, lo que le compartieron y si tiene el scope admin; con una API key
+// no lo cambia
+func SetUserAccess(c *gin.Context, meta *models.RequestMeta) {
+	user, ok := CurrentUser(c)
+	if !ok {
+		return
+	}
+	scopes, _ := Scopes(c)
+	Credentials{User: &user, Scopes: scopes}.SetUserAccess(meta)
+}
+
+// Scopes devuelve los scopes otorgados a la petición, si pasó por Authenticate
+func Scopes(c *gin.Context) ([]string, bool) {
+	value, exists := c.Get(ScopesKey)
+	if !exists {
+		return nil, false
+	}
+	scopes, ok := value.([]string)
+	return scopes, ok
+}
+
+// Authorize exige que la petición (su API key o su usuario) tenga el scope read en las
+// lecturas (GET y HEAD) y el scope write en el resto; responde 403
+// (insufficient_scope) si no lo tiene.
+// Va después de Authenticate: sin identidad en el contexto responde 401.
+func Authorize(read string, write string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, ok := Scopes(c)
+		if !ok {
+			abortUnauthorized(c)
+			return
+		}
+
+		scope := write
+		if c.Request.Method == "GET" || c.Request.Method == "HEAD" {
+			scope = read
+		}
+		if !models.HasScope(scopes, scope) {
+			abortProblem(c, constants.StatusForbidden, constants.ErrCodeInsufficientScope, constants.ScopeInsuficiente,
+				Localizer(c).Message(constants.ScopeRequerido, scope))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,298 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+)
+
+// Credentials es la identidad que prueban las credenciales de una petición: la
+// API key o el usuario y los claims de su token de acceso propio (nil con un
+// token del proveedor OIDC), y en ambos casos los scopes otorgados
+type Credentials struct {
+	APIKey *models.APIKey
+	User   *models.User
+	Claims *auth.Claims
+	Scopes []string
+}
+
+// Tenant devuelve el tenant de las credenciales; vacío para las keys que no
+// están en la base de datos, que valen en cualquier tenant
+func (cr Credentials) Tenant() string {
+	if cr.User != nil {
+		return cr.User.TenantID
+	}
+	if cr.APIKey != nil {
+		return cr.APIKey.TenantID
+	}
+	return ""
+}
+
+// SetUserAccess completa meta con el usuario, sus roles, lo que le compartieron
+// y si tiene el scope admin; con una API key no lo cambia
+func (cr Credentials) SetUserAccess(meta *models.RequestMeta) {
+	if cr.User == nil {
+		return
+	}
+	meta.UserID = cr.User.ID
+	meta.Admin = models.HasScope(cr.Scopes, constants.ScopeAdmin)
+	meta.Role = cr.User.Role
+	meta.ProjectRoles = cr.User.ProjectRoles
+	meta.Shares = cr.User.Shares
+}
+
+// Errores de Authenticator; AuthErrorStatus da el código HTTP de cada uno
+var (
+	errCredentialsRequired = errors.New(constants.APIKeyRequerida)
+	errInvalidToken        = errors.New(constants.TokenAccesoInvalido)
+	errMissingEmail        = errors.New(constants.TokenOIDCSinEmail)
+	errIdentityConflict    = errors.New(constants.IdentidadOIDCEnConflicto)
+	errUnknownTenant       = errors.New(constants.TenantOIDCDesconocido)
+	errRegistrationClosed  = errors.New(constants.RegistroNoPermitido)
+	errTenantMismatch      = errors.New(constants.TenantNoCoincide)
+)
+
+// AuthErrorStatus devuelve el código HTTP y el mensaje de un error de
+// Authenticator: 401 si las credenciales faltan o no son válidas, 403 si no
+// valen en el tenant pedido y 500 (sin el detalle) para el resto
+func AuthErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, errCredentialsRequired), errors.Is(err, errInvalidToken), errors.Is(err, errMissingEmail),
+		errors.Is(err, errIdentityConflict), errors.Is(err, errUnknownTenant):
+		return constants.StatusUnauthorized, err.Error()
+	case errors.Is(err, errRegistrationClosed), errors.Is(err, errTenantMismatch):
+		return constants.StatusForbidden, err.Error()
+	default:
+		return constants.StatusInternalServerError, constants.ErrorBaseDatos
+	}
+}
+
+// Authenticator verifica las credenciales de las peticiones, las de la API
+// REST (ver Authenticate) y las de la API gRPC, con las mismas reglas
+type Authenticator struct {
+	keys          repository.IAPIKeyRepository
+	users         repository.IUserRepository
+	tenants       repository.ITenantRepository
+	tokens        *auth.TokenIssuer
+	oidc          *auth.OIDCVerifier
+	config        config.AuthConfig
+	bootstrapHash []byte
+}
+
+func NewAuthenticator(keys repository.IAPIKeyRepository, users repository.IUserRepository, tenants repository.ITenantRepository, tokens *auth.TokenIssuer, oidc *auth.OIDCVerifier, authConfig config.AuthConfig) *Authenticator {
+	a := &Authenticator{
+		keys:    keys,
+		users:   users,
+		tenants: tenants,
+		tokens:  tokens,
+		oidc:    oidc,
+		config:  authConfig,
+	}
+	if authConfig.BootstrapKey != "" {
+		a.bootstrapHash = []byte(auth.HashToken(authConfig.BootstrapKey))
+	}
+	return a
+}
+
+// Tokens devuelve el emisor de los tokens de acceso propios
+func (a *Authenticator) Tokens() *auth.TokenIssuer {
+	return a.tokens
+}
+
+// Authenticate identifica a quien presenta la API key apiKey o, si está vacía,
+// el token de acceso bearer, propio o (si oidc no es nil) de su proveedor de
+// identidad. Con la autenticación deshabilitada devuelve una key anónima con
+// scope admin.
+func (a *Authenticator) Authenticate(ctx context.Context, apiKey string, bearer string) (Credentials, error) {
+	switch {
+	case !a.config.Enabled:
+		return keyCredentials(anonymousKey), nil
+	case apiKey != "":
+		return a.authenticateAPIKey(apiKey)
+	case bearer == "":
+		return Credentials{}, errCredentialsRequired
+	case a.oidc != nil && auth.UnverifiedIssuer(bearer) == a.oidc.Issuer():
+		return a.authenticateOIDCUser(ctx, bearer)
+	default:
+		return a.authenticateUser(bearer)
+	}
+}
+
+// Tenant devuelve el tenant con el que trabajan las credenciales: el suyo, que
+// debe coincidir con el pedido (requested, si hasRequested), o el pedido, o el
+// tenant por defecto
+func (a *Authenticator) Tenant(credentials Credentials, requested models.Tenant, hasRequested bool) (models.Tenant, error) {
+	tenantID := credentials.Tenant()
+	if hasRequested {
+		if tenantID != "" && tenantID != requested.ID {
+			return models.Tenant{}, errTenantMismatch
+		}
+		return requested, nil
+	}
+	if tenantID == "" {
+		tenantID = constants.DefaultTenantID
+	}
+	return a.tenants.GetTenant(tenantID)
+}
+
+func keyCredentials(key models.APIKey) Credentials {
+	return Credentials{APIKey: &key, Scopes: key.Scopes}
+}
+
+func (a *Authenticator) authenticateAPIKey(presented string) (Credentials, error) {
+	hash := auth.HashToken(presented)
+	if a.bootstrapHash != nil && subtle.ConstantTimeCompare([]byte(hash), a.bootstrapHash) == 1 {
+		return keyCredentials(bootstrapKey), nil
+	}
+
+	key, err := a.keys.GetAPIKeyByHash(hash)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && key.Revoked()) {
+		return Credentials{}, errCredentialsRequired
+	}
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	touch(a.keys, key, a.config.TouchInterval)
+	return keyCredentials(key), nil
+}
+
+func (a *Authenticator) authenticateUser(token string) (Credentials, error) {
+	claims, err := a.tokens.Parse(token)
+	if err != nil {
+		return Credentials{}, errInvalidToken
+	}
+	revoked, err := a.users.IsAccessTokenRevoked(claims.ID)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if revoked {
+		return Credentials{}, errInvalidToken
+	}
+
+	// El usuario puede haberse eliminado después de emitir el token. Su rol se
+	// lee en cada petición para que los cambios valgan sin volver a iniciar sesión.
+	id, _ := strconv.Atoi(claims.Subject)
+	user, err := a.users.GetUser(id)
+	// Los tokens emitidos antes de la multi-tenencia no tienen tenant
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && claims.TenantID != "" && claims.TenantID != user.TenantID) {
+		return Credentials{}, errInvalidToken
+	}
+	if err == nil {
+		err = loadAccess(a.users, &user)
+	}
+	if err != nil {
+		return Credentials{}, err
+	}
+	return Credentials{User: &user, Claims: claims, Scopes: userScopes(user)}, nil
+}
+
+// authenticateOIDCUser valida el token con el proveedor de identidad y busca al
+// usuario por emisor y sub. La primera vez lo asocia al usuario local con el mismo
+// email, si el proveedor lo verificó, o crea uno nuevo sin contraseña. El tenant
+// nunca sale de la petición sino del token (ver oidcTenant); si no es el que
+// pidió la petición, Tenant lo rechaza.
+func (a *Authenticator) authenticateOIDCUser(ctx context.Context, token string) (Credentials, error) {
+	identity, err := a.oidc.Verify(ctx, token)
+	if err != nil {
+		return Credentials{}, errInvalidToken
+	}
+
+	user, err := a.users.GetUserByIdentity(identity.Issuer, identity.Subject)
+	if errors.Is(err, sql.ErrNoRows) {
+		var tenantID string
+		if tenantID, err = oidcTenant(a.tenants, a.oidc, identity); err == nil {
+			user, err = provisionUser(a.users, identity, tenantID)
+		}
+	}
+	if err == nil {
+		err = loadAccess(a.users, &user)
+	}
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	// Con OIDC_ROLE_SCOPES los scopes salen de los roles del token; si no, del
+	// rol guardado, como los usuarios locales
+	scopes := userScopes(user)
+	if a.oidc.MapsRoles() {
+		scopes = a.oidc.Scopes(identity.Roles)
+	}
+	user.Roles = identity.Roles
+	return Credentials{User: &user, Scopes: scopes}, nil
+}
+
+// oidcTenant devuelve el tenant donde crear el usuario de una identidad OIDC
+// nueva: el del claim OIDC_TENANT_CLAIM, que debe existir, o sin ese claim
+// configurado el tenant por defecto, si admite el registro
+func oidcTenant(tenants repository.ITenantRepository, oidc *auth.OIDCVerifier, identity auth.Identity) (string, error) {
+	if oidc.MapsTenants() {
+		if identity.Tenant == "" {
+			return "", errUnknownTenant
+		}
+		tenant, err := tenants.GetTenantBySlug(identity.Tenant)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", errUnknownTenant
+		}
+		return tenant.ID, err
+	}
+
+	tenant, err := tenants.GetTenant(constants.DefaultTenantID)
+	if err != nil {
+		return "", err
+	}
+	if !tenant.AllowRegistration {
+		return "", errRegistrationClosed
+	}
+	return tenant.ID, nil
+}
+
+// provisionUser asocia o crea (en tenantID) el usuario local de una identidad OIDC
+// nueva; una cuenta local solo se asocia si es del mismo tenant
+func provisionUser(users repository.IUserRepository, identity auth.Identity, tenantID string) (models.User, error) {
+	user := models.User{TenantID: tenantID, Email: identity.Email, Name: identity.Name, Issuer: identity.Issuer, Subject: identity.Subject}
+	if err := user.Validate(); err != nil {
+		return models.User{}, errMissingEmail
+	}
+
+	existing, err := users.GetUserByEmail(user.Email)
+	if err == nil {
+		// Sin email verificado cualquiera podría apropiarse de una cuenta local
+		if existing.Issuer != "" || !identity.EmailVerified || existing.TenantID != tenantID {
+			return models.User{}, errIdentityConflict
+		}
+		return users.LinkUserIdentity(existing.ID, identity.Issuer, identity.Subject)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, err
+	}
+
+	created, err := users.CreateUser(user)
+	if errors.Is(err, repository.ErrDuplicateEmail) {
+		// Otra petición con la misma identidad lo creó primero
+		if existing, err := users.GetUserByIdentity(identity.Issuer, identity.Subject); err == nil {
+			return existing, nil
+		}
+		return models.User{}, errIdentityConflict
+	}
+	return created, err
+}
+
+// BearerToken devuelve el token de un valor de Authorization: Bearer <token>;
+// vacío si no tiene esa forma
+func BearerToken(authorization string) string {
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, constants.TokenTypeBearer) {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Parámetro, cabeceras y clave del contexto de gin del idioma de la respuesta
+const (
+	LanguageParam         = "lang"
+	AcceptLanguageHeader  = "Accept-Language"
+	ContentLanguageHeader = "Content-Language"
+	LocalizerKey          = "localizer"
+)
+
+// Language negocia el idioma de la respuesta (?lang= tiene prioridad sobre
+// Accept-Language) y guarda en el contexto el i18n.Localizer para ese idioma
+func Language(catalog *i18n.Catalog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tag := catalog.Negotiate(c.Query(LanguageParam), c.GetHeader(AcceptLanguageHeader))
+		c.Set(LocalizerKey, catalog.Localizer(tag))
+		c.Header(ContentLanguageHeader, tag.String())
+		c.Header("Vary", AcceptLanguageHeader)
+		c.Next()
+	}
+}
+
+// Localizer devuelve el traductor guardado por Language; sin el middleware los
+// mensajes se responden en español
+func Localizer(c *gin.Context) i18n.Localizer {
+	if value, ok := c.Get(LocalizerKey); ok {
+		if localizer, ok := value.(i18n.Localizer); ok {
+			return localizer
+		}
+	}
+	return i18n.Default.Localizer(i18n.Source)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/gin-gonic/gin"
+)
+
+// abortProblem corta la petición con un error application/problem+json (RFC
+// 9457), con la misma forma que los de la API de items (ver handlers.Problem).
+// title es un mensaje de constants, que se traduce al idioma de la petición.
+func abortProblem(c *gin.Context, status int, code string, title string, detail string) {
+	problem := gin.H{
+		"type":     constants.ProblemTypePrefix + code,
+		"title":    Localizer(c).Message(title),
+		"status":   status,
+		"instance": c.Request.URL.Path,
+		"code":     code,
+	}
+	if detail != "" {
+		problem["detail"] = detail
+	}
+	if requestID := c.GetString(RequestIDKey); requestID != "" {
+		problem["request_id"] = requestID
+	}
+
+	c.Header("Content-Type", constants.ProblemContentType)
+	c.AbortWithStatusJSON(status, problem)
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cabeceras y claves del contexto de gin con los datos de la petición
+const (
+	RequestIDHeader = "X-Request-ID"
+	ActorHeader     = "X-Actor"
+	RequestIDKey    = "request_id"
+	ActorKey        = "actor"
+)
+
+// maxRequestIDLength es el largo de la columna request_id del historial
+const maxRequestIDLength = 64
+
+// RequestMeta guarda en el contexto el ID de la petición (el recibido en
+// X-Request-ID o uno nuevo) y el actor informado en X-Actor, y devuelve el ID
+// en la respuesta para poder correlacionarlo con el historial
+func RequestMeta() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = NewRequestID()
+		}
+		c.Set(RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		if actor := c.GetHeader(ActorHeader); actor != "" {
+			c.Set(ActorKey, actor)
+		}
+		c.Next()
+	}
+}
+
+func NewRequestID() string {
+	buf := make([]byte, 16)
+	// crypto/rand.Read no falla en las plataformas soportadas
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
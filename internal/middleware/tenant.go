@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// Claves del contexto de gin con el tenant (models.Tenant) que pidió la petición
+// con la cabecera o el subdominio, y el tenant con el que finalmente trabaja
+const (
+	RequestedTenantKey = "requested_tenant"
+	TenantKey          = "tenant"
+)
+
+// ResolveTenant guarda en el contexto el tenant que pide la petición con la
+// cabecera X-Tenant o, si está configurado, con el subdominio; responde 404 si
+// no existe. Va antes de Authenticate, que verifica que las credenciales sean
+// de ese tenant. Sin cabecera ni subdominio no hace nada.
+func ResolveTenant(tenants repository.ITenantRepository, tenantConfig config.TenantConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug := requestedSlug(c, tenantConfig)
+		if slug == "" {
+			c.Next()
+			return
+		}
+
+		tenant, err := tenants.GetTenantBySlug(slug)
+		if errors.Is(err, sql.ErrNoRows) {
+			abortProblem(c, constants.StatusNotFound, constants.ErrCodeTenantNotFound, constants.TenantNoEncontrado, slug)
+			return
+		}
+		if err != nil {
+			abortDatabaseError(c, err)
+			return
+		}
+		c.Set(RequestedTenantKey, tenant)
+		c.Next()
+	}
+}
+
+// requestedSlug devuelve el slug de la cabecera X-Tenant o, si no está, el
+// subdominio de BaseDomain en Host (sin el puerto)
+func requestedSlug(c *gin.Context, tenantConfig config.TenantConfig) string {
+	if slug := strings.TrimSpace(c.GetHeader(constants.TenantHeader)); slug != "" {
+		return strings.ToLower(slug)
+	}
+	if tenantConfig.BaseDomain == "" {
+		return ""
+	}
+
+	host := strings.ToLower(c.Request.Host)
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	slug, found := strings.CutSuffix(host, "."+tenantConfig.BaseDomain)
+	if !found || slug == "" || strings.Contains(slug, ".") {
+		return ""
+	}
+	return slug
+}
+
+// RequestedTenant devuelve el tenant que pidió la petición, si pidió alguno
+func RequestedTenant(c *gin.Context) (models.Tenant, bool) {
+	value, exists := c.Get(RequestedTenantKey)
+	if !exists {
+		return models.Tenant{}, false
+	}
+	tenant, ok := value.(models.Tenant)
+	return tenant, ok
+}
+
+// CurrentTenant devuelve el tenant de la petición autenticada
+func CurrentTenant(c *gin.Context) (models.Tenant, bool) {
+	value, exists := c.Get(TenantKey)
+	if !exists {
+		return models.Tenant{}, false
+	}
+	tenant, ok := value.(models.Tenant)
+	return tenant, ok
+}
+
+// TenantID devuelve el ID del tenant con el que trabaja la petición: el de la
+// petición autenticada, el pedido en las rutas públicas o el tenant por defecto
+func TenantID(c *gin.Context) string {
+	if tenant, ok := CurrentTenant(c); ok {
+		return tenant.ID
+	}
+	if tenant, ok := RequestedTenant(c); ok {
+		return tenant.ID
+	}
+	return constants.DefaultTenantID
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionKey es la clave del contexto de gin con la versión de la API de la petición
+const APIVersionKey = "api_version"
+
+// Cabeceras de deprecación de una versión (RFC 9745 y RFC 8594)
+const (
+	DeprecationHeader = "Deprecation"
+	SunsetHeader      = "Sunset"
+	LinkHeader        = "Link"
+)
+
+// APIVersion fija la versión de la API de las rutas de un grupo (/v1, /v2)
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(APIVersionKey, version)
+		c.Header(constants.APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// NegotiateVersion elige la versión de las rutas sin prefijo con la cabecera
+// API-Version (1 o 2, también v1 o v2). Sin cabecera se usa la 1, para no romper
+// a los clientes existentes.
+func NegotiateVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(c.GetHeader(constants.APIVersionHeader))), "v")
+		switch version {
+		case "":
+			version = constants.APIVersion1
+		case constants.APIVersion1, constants.APIVersion2:
+		default:
+			abortProblem(c, constants.StatusBadRequest, constants.ErrCodeUnsupportedVersion, constants.VersionNoSoportada,
+				constants.APIVersionHeader+": "+constants.APIVersion1+" | "+constants.APIVersion2)
+			return
+		}
+		c.Set(APIVersionKey, version)
+		c.Header(constants.APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// Deprecated marca las respuestas de la versión 1 con las fechas de deprecación
+// y retiro y con el enlace a la misma ruta en la versión 2
+func Deprecated(versions config.VersionConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(APIVersionKey) == constants.APIVersion1 {
+			if !versions.V1Deprecation.IsZero() {
+				c.Header(DeprecationHeader, "@"+strconv.FormatInt(versions.V1Deprecation.Unix(), 10))
+			}
+			if !versions.V1Sunset.IsZero() {
+				c.Header(SunsetHeader, versions.V1Sunset.UTC().Format(http.TimeFormat))
+			}
+			successor := "/v" + constants.APIVersion2 + strings.TrimPrefix(c.Request.URL.Path, "/v"+constants.APIVersion1)
+			c.Header(LinkHeader, "<"+successor+`>; rel="successor-version"`)
+		}
+		c.Next()
+	}
+}
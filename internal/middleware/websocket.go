@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocketCredentials pasa a las cabeceras X-API-Key y Authorization las
+// credenciales que un navegador envía como subprotocolos del WebSocket
+// (apikey.<key> o bearer.<token>, ver constants.WSProtocol), para que
+// Authenticate las verifique y las mutaciones del canal las reenvíen. Va antes
+// de Authenticate; las cabeceras, si vienen, tienen prioridad.
+func WebSocketCredentials() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(constants.APIKeyHeader) != "" || c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+		for _, header := range c.Request.Header.Values("Sec-WebSocket-Protocol") {
+			for _, protocol := range strings.Split(header, ",") {
+				protocol = strings.TrimSpace(protocol)
+				if key, found := strings.CutPrefix(protocol, constants.WSAPIKeyProtocolPrefix); found && key != "" {
+					c.Request.Header.Set(constants.APIKeyHeader, key)
+				} else if token, found := strings.CutPrefix(protocol, constants.WSBearerProtocolPrefix); found && token != "" {
+					c.Request.Header.Set("Authorization", constants.TokenTypeBearer+" "+token)
+				}
+			}
+		}
+		c.Next()
+	}
+}
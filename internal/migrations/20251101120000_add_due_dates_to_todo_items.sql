@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE todo_items
+    ADD COLUMN start_at DATETIME NULL DEFAULT NULL AFTER state,
+    ADD COLUMN due_at DATETIME NULL DEFAULT NULL AFTER start_at,
+    ADD INDEX idx_todo_items_due_at (due_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todo_items
+    DROP INDEX idx_todo_items_due_at,
+    DROP COLUMN due_at,
+    DROP COLUMN start_at;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE todo_items
+    ADD COLUMN priority ENUM('low', 'medium', 'high', 'urgent') NOT NULL DEFAULT 'medium' AFTER state,
+    ADD INDEX idx_todo_items_priority (priority);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todo_items
+    DROP INDEX idx_todo_items_priority,
+    DROP COLUMN priority;
+-- +goose StatementEnd
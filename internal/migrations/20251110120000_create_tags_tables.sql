@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE tags (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_tags_name (name)
+);
+
+CREATE TABLE todo_item_tags (
+    item_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    PRIMARY KEY (item_id, tag_id),
+    INDEX idx_todo_item_tags_tag_id (tag_id),
+    CONSTRAINT fk_todo_item_tags_item FOREIGN KEY (item_id) REFERENCES todo_items (id) ON DELETE CASCADE,
+    CONSTRAINT fk_todo_item_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE todo_item_tags;
+DROP TABLE tags;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE projects (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    archived_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+ALTER TABLE todo_items
+    ADD COLUMN project_id INT NULL DEFAULT NULL AFTER id,
+    ADD CONSTRAINT fk_todo_items_project FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todo_items
+    DROP FOREIGN KEY fk_todo_items_project,
+    DROP COLUMN project_id;
+
+DROP TABLE projects;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE todo_items
+    ADD COLUMN parent_id INT NULL DEFAULT NULL AFTER project_id,
+    ADD CONSTRAINT fk_todo_items_parent FOREIGN KEY (parent_id) REFERENCES todo_items (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todo_items
+    DROP FOREIGN KEY fk_todo_items_parent,
+    DROP COLUMN parent_id;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE item_dependencies (
+    item_id INT NOT NULL,
+    blocker_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (item_id, blocker_id),
+    INDEX idx_item_dependencies_blocker_id (blocker_id),
+    CONSTRAINT fk_item_dependencies_item FOREIGN KEY (item_id) REFERENCES todo_items (id) ON DELETE CASCADE,
+    CONSTRAINT fk_item_dependencies_blocker FOREIGN KEY (blocker_id) REFERENCES todo_items (id) ON DELETE CASCADE,
+    CONSTRAINT chk_item_dependencies_self CHECK (item_id <> blocker_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE item_dependencies;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Los estados válidos los define el flujo configurable (config.Workflow), no la base de datos
+ALTER TABLE todo_items
+    MODIFY COLUMN state VARCHAR(32) NOT NULL DEFAULT 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todo_items
+    MODIFY COLUMN state ENUM('pending', 'in_progress', 'completed') DEFAULT 'pending';
+-- +goose StatementEnd
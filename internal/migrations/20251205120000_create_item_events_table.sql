@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE item_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    item_id INT NOT NULL,
+    event_type VARCHAR(20) NOT NULL,
+    actor VARCHAR(255) NULL,
+    request_id VARCHAR(64) NULL,
+    changes JSON NOT NULL,
+    created_at TIMESTAMP(6) DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_item_events_item_id (item_id, id),
+    CONSTRAINT fk_item_events_item FOREIGN KEY (item_id) REFERENCES todo_items (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE item_events;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE item_stream_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    item_id INT NOT NULL,
+    version INT NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    data JSON NOT NULL,
+    actor VARCHAR(255) NULL,
+    request_id VARCHAR(64) NULL,
+    occurred_at TIMESTAMP(6) DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE KEY uq_item_stream_events_version (item_id, version)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE item_snapshots (
+    item_id INT NOT NULL,
+    version INT NOT NULL,
+    state JSON NOT NULL,
+    occurred_at TIMESTAMP(6) NOT NULL,
+    PRIMARY KEY (item_id, version)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE item_snapshots;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE item_stream_events;
+-- +goose StatementEnd
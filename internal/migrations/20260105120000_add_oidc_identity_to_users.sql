@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Usuarios de un proveedor OIDC: se identifican por emisor y sub, y no tienen contraseña
+ALTER TABLE users
+    ADD COLUMN oidc_issuer VARCHAR(255) NULL DEFAULT NULL AFTER password_hash,
+    ADD COLUMN oidc_subject VARCHAR(255) NULL DEFAULT NULL AFTER oidc_issuer,
+    MODIFY COLUMN password_hash VARCHAR(255) NOT NULL DEFAULT '',
+    ADD UNIQUE KEY uq_users_oidc_identity (oidc_issuer, oidc_subject);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP INDEX uq_users_oidc_identity,
+    DROP COLUMN oidc_subject,
+    DROP COLUMN oidc_issuer,
+    MODIFY COLUMN password_hash VARCHAR(255) NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- created_by queda en NULL para los items creados con una API key o antes de que hubiera usuarios
+ALTER TABLE todo_items
+    ADD COLUMN created_by INT NULL DEFAULT NULL,
+    ADD COLUMN assignee_id INT NULL DEFAULT NULL,
+    ADD INDEX idx_todo_items_created_by (created_by),
+    ADD INDEX idx_todo_items_assignee (assignee_id),
+    ADD CONSTRAINT fk_todo_items_created_by FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL,
+    ADD CONSTRAINT fk_todo_items_assignee FOREIGN KEY (assignee_id) REFERENCES users (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todo_items
+    DROP FOREIGN KEY fk_todo_items_assignee,
+    DROP FOREIGN KEY fk_todo_items_created_by,
+    DROP INDEX idx_todo_items_assignee,
+    DROP INDEX idx_todo_items_created_by,
+    DROP COLUMN assignee_id,
+    DROP COLUMN created_by;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Los usuarios existentes conservan lo que podían hacer: leer, crear y modificar
+ALTER TABLE users ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'member' AFTER name;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- El rol de una membresía reemplaza al rol global del usuario en el proyecto
+CREATE TABLE project_members (
+    project_id INT NOT NULL,
+    user_id INT NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, user_id),
+    INDEX idx_project_members_user (user_id),
+    CONSTRAINT fk_project_members_project FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    CONSTRAINT fk_project_members_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE project_members;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN role;
+-- +goose StatementEnd
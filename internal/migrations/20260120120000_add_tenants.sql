@@ -0,0 +1,99 @@
+-- +goose Up
+-- +goose StatementBegin
+-- max_items y max_projects son las cuotas del tenant; 0 es sin límite
+CREATE TABLE tenants (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    slug VARCHAR(63) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    max_items INT NOT NULL DEFAULT 0,
+    max_projects INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_tenants_slug (slug)
+);
+
+-- Los datos existentes quedan en el tenant por defecto
+INSERT INTO tenants (id, slug, name) VALUES (1, 'default', 'Default');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Cada tabla con datos propios lleva su tenant. Las que solo relacionan filas
+-- (todo_item_tags, item_dependencies, project_members) o cuelgan de un usuario
+-- (refresh_tokens, revoked_tokens) se consultan siempre a través de ellas.
+ALTER TABLE todo_items
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    ADD INDEX idx_todo_items_tenant (tenant_id, deleted_at),
+    ADD CONSTRAINT fk_todo_items_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+ALTER TABLE projects
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    ADD CONSTRAINT fk_projects_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+-- Los nombres de etiquetas son únicos dentro de cada tenant
+ALTER TABLE tags
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    DROP INDEX uq_tags_name,
+    ADD UNIQUE KEY uq_tags_tenant_name (tenant_id, name),
+    ADD CONSTRAINT fk_tags_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+ALTER TABLE item_events
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    ADD CONSTRAINT fk_item_events_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+ALTER TABLE item_stream_events
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    ADD CONSTRAINT fk_item_stream_events_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+ALTER TABLE item_snapshots
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 FIRST,
+    ADD CONSTRAINT fk_item_snapshots_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+ALTER TABLE item_change_feed
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    ADD CONSTRAINT fk_item_change_feed_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+ALTER TABLE webhooks
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    ADD CONSTRAINT fk_webhooks_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+ALTER TABLE webhook_outbox
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    ADD CONSTRAINT fk_webhook_outbox_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+ALTER TABLE webhook_deliveries
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    ADD CONSTRAINT fk_webhook_deliveries_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+ALTER TABLE api_keys
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    ADD CONSTRAINT fk_api_keys_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+
+-- Los emails siguen siendo únicos en toda la API: el inicio de sesión no pide tenant
+ALTER TABLE users
+    ADD COLUMN tenant_id INT NOT NULL DEFAULT 1 AFTER id,
+    ADD CONSTRAINT fk_users_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP FOREIGN KEY fk_users_tenant, DROP COLUMN tenant_id;
+ALTER TABLE api_keys DROP FOREIGN KEY fk_api_keys_tenant, DROP COLUMN tenant_id;
+ALTER TABLE webhook_deliveries DROP FOREIGN KEY fk_webhook_deliveries_tenant, DROP COLUMN tenant_id;
+ALTER TABLE webhook_outbox DROP FOREIGN KEY fk_webhook_outbox_tenant, DROP COLUMN tenant_id;
+ALTER TABLE webhooks DROP FOREIGN KEY fk_webhooks_tenant, DROP COLUMN tenant_id;
+ALTER TABLE item_change_feed DROP FOREIGN KEY fk_item_change_feed_tenant, DROP COLUMN tenant_id;
+ALTER TABLE item_snapshots DROP FOREIGN KEY fk_item_snapshots_tenant, DROP COLUMN tenant_id;
+ALTER TABLE item_stream_events DROP FOREIGN KEY fk_item_stream_events_tenant, DROP COLUMN tenant_id;
+ALTER TABLE item_events DROP FOREIGN KEY fk_item_events_tenant, DROP COLUMN tenant_id;
+ALTER TABLE tags
+    DROP FOREIGN KEY fk_tags_tenant,
+    DROP INDEX uq_tags_tenant_name,
+    DROP COLUMN tenant_id,
+    ADD UNIQUE KEY uq_tags_name (name);
+ALTER TABLE projects DROP FOREIGN KEY fk_projects_tenant, DROP COLUMN tenant_id;
+ALTER TABLE todo_items DROP FOREIGN KEY fk_todo_items_tenant, DROP INDEX idx_todo_items_tenant, DROP COLUMN tenant_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE tenants;
+-- +goose StatementEnd
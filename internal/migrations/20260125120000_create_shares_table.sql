@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Un compartido es de un item o de un proyecto (solo uno) con un usuario o,
+-- sin user_id, con quien tenga el enlace, del que se guarda solo el hash
+CREATE TABLE shares (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    item_id INT NULL,
+    project_id INT NULL,
+    user_id INT NULL,
+    permission VARCHAR(16) NOT NULL,
+    prefix VARCHAR(16) NULL,
+    token_hash CHAR(64) NULL,
+    expires_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_by INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_shares_token_hash (token_hash),
+    INDEX idx_shares_user (user_id, revoked_at),
+    CONSTRAINT chk_shares_target CHECK ((item_id IS NULL) <> (project_id IS NULL)),
+    CONSTRAINT fk_shares_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id),
+    CONSTRAINT fk_shares_item FOREIGN KEY (item_id) REFERENCES todo_items (id) ON DELETE CASCADE,
+    CONSTRAINT fk_shares_project FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    CONSTRAINT fk_shares_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_shares_created_by FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE shares;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Solo los tenants con allow_registration admiten que cualquiera se registre;
+-- en el resto los usuarios llegan con OIDC (OIDC_TENANT_CLAIM) o los da de
+-- alta un administrador. El tenant por defecto lo conserva, como antes de la
+-- multi-tenencia.
+ALTER TABLE tenants ADD COLUMN allow_registration BOOLEAN NOT NULL DEFAULT FALSE AFTER max_projects;
+UPDATE tenants SET allow_registration = TRUE WHERE id = 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tenants DROP COLUMN allow_registration;
+-- +goose StatementEnd
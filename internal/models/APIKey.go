@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// APIKey es una credencial para usar la API. Se guarda solo el hash de la key;
+// Key (la key en claro) se devuelve únicamente al crearla o rotarla.
+type APIKey struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Key        string   `json:"key,omitempty"`
+	Prefix     string   `json:"prefix"`
+	Hash       string   `json:"-"`
+	Scopes     []string `json:"scopes"`
+	LastUsedAt *string  `json:"last_used_at,omitempty"`
+	RevokedAt  *string  `json:"revoked_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+	// TenantID es el tenant en el que vale la key; vacío en las keys que no están
+	// en la base de datos (bootstrap), que valen en el tenant de la petición
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// Validate valida los campos de la APIKey
+func (k *APIKey) Validate() error {
+	k.Name = strings.TrimSpace(k.Name)
+	if k.Name == "" {
+		return errors.New(constants.NombreAPIKeyRequerido)
+	}
+	if len(k.Scopes) == 0 {
+		return errors.New(constants.ScopesRequeridos)
+	}
+	for _, scope := range k.Scopes {
+		if !constants.IsValidScope(scope) {
+			return errors.New(constants.ScopeInvalido + ": " + scope)
+		}
+	}
+	return nil
+}
+
+// Revoked indica si la key fue revocada
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// HasScope indica si scopes otorga scope; admin los incluye a todos
+func HasScope(scopes []string, scope string) bool {
+	return slices.Contains(scopes, scope) || slices.Contains(scopes, constants.ScopeAdmin)
+}
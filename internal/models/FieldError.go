@@ -0,0 +1,26 @@
+package models
+
+import "strings"
+
+// FieldError es un error de validación de un campo, con un código estable
+// (constants.Field*) y el mensaje legible
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// FieldErrors son los errores de validación de todos los campos inválidos
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Message
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// FieldChange es el valor anterior y el nuevo de un campo. Old es nil en los
+// eventos de creación.
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// ItemEvent es una entrada del historial de un item
+type ItemEvent struct {
+	ID        string                 `json:"id"`
+	ItemID    string                 `json:"item_id"`
+	Type      string                 `json:"type"`
+	Actor     string                 `json:"actor,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Changes   map[string]FieldChange `json:"changes"`
+	CreatedAt string                 `json:"created_at"`
+}
+
+// RequestMeta identifica quién hace un cambio y en qué petición, para el historial
+type RequestMeta struct {
+	Actor     string
+	RequestID string
+	// UserID es el usuario autenticado (vacío con una API key) y Admin indica si
+	// tiene el scope admin; ver policy.CanModify
+	UserID string
+	Admin  bool
+	// Role y ProjectRoles son el rol global del usuario y sus roles por
+	// proyecto; ver policy.Can
+	Role         string
+	ProjectRoles map[string]string
+	// TenantID es el tenant de la petición: el repositorio solo ve y modifica
+	// sus datos. Vacío es constants.DefaultTenantID, ver Tenant.
+	TenantID string
+	// Shares son los compartidos con el usuario o, para un Guest (quien usa un
+	// enlace compartido, sin otra identidad), el del enlace; ver policy.SharedPermission
+	Shares []Share
+	Guest  bool
+}
+
+// Tenant devuelve el tenant de la petición
+func (m RequestMeta) Tenant() string {
+	if m.TenantID == "" {
+		return constants.DefaultTenantID
+	}
+	return m.TenantID
+}
+
+// DiffItems devuelve los campos guardados que cambian de old a new. Con old nil
+// (creación) se incluyen todos los campos con valor.
+func DiffItems(old *TodoItem, new TodoItem) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+	var before TodoItem
+	if old != nil {
+		before = *old
+	}
+
+	compare := func(field string, oldValue, newValue any, equal bool) {
+		if old == nil {
+			oldValue = nil
+			if newValue == nil {
+				return
+			}
+		} else if equal {
+			return
+		}
+		changes[field] = FieldChange{Old: oldValue, New: newValue}
+	}
+
+	compare("title", before.Title, new.Title, before.Title == new.Title)
+	compare("description", before.Description, new.Description, before.Description == new.Description)
+	compare("state", before.State, new.State, before.State == new.State)
+	compare("priority", before.Priority, new.Priority, before.Priority == new.Priority)
+	compare("project_id", optionalValue(before.ProjectID), optionalValue(new.ProjectID), optionalEqual(before.ProjectID, new.ProjectID))
+	compare("parent_id", optionalValue(before.ParentID), optionalValue(new.ParentID), optionalEqual(before.ParentID, new.ParentID))
+	compare("created_by", optionalValue(before.CreatedBy), optionalValue(new.CreatedBy), optionalEqual(before.CreatedBy, new.CreatedBy))
+	compare("assignee_id", optionalValue(before.AssigneeID), optionalValue(new.AssigneeID), optionalEqual(before.AssigneeID, new.AssigneeID))
+	compare("start_at", timestampValue(before.StartAt), timestampValue(new.StartAt), timestampEqual(before.StartAt, new.StartAt))
+	compare("due_at", timestampValue(before.DueAt), timestampValue(new.DueAt), timestampEqual(before.DueAt, new.DueAt))
+
+	oldTags, newTags := sortedTags(before.Tags), sortedTags(new.Tags)
+	var newTagsValue any
+	if len(newTags) > 0 || old != nil {
+		newTagsValue = newTags
+	}
+	compare("tags", oldTags, newTagsValue, slices.Equal(oldTags, newTags))
+
+	return changes
+}
+
+// optionalValue convierte un puntero en su valor, o nil para que se serialice como null
+func optionalValue(value *string) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func optionalEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// timestampValue normaliza una fecha a RFC3339 en UTC, ya que la base de datos
+// no la devuelve necesariamente en el mismo formato en que se envió
+func timestampValue(value *string) any {
+	if value == nil {
+		return nil
+	}
+	t, err := ParseTimestamp(*value)
+	if err != nil {
+		return *value
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func timestampEqual(a, b *string) bool {
+	return timestampValue(a) == timestampValue(b)
+}
+
+func sortedTags(tags []string) []string {
+	sorted := slices.Clone(tags)
+	if sorted == nil {
+		sorted = []string{}
+	}
+	slices.Sort(sorted)
+	return sorted
+}
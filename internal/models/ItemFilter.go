@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Campos por los que se puede ordenar el listado de items
+const (
+	SortByID        = "id"
+	SortByCreatedAt = "created_at"
+	SortByDueAt     = "due_at"
+	SortByPriority  = "priority"
+	SortByTitle     = "title"
+)
+
+// ValidSortFields contiene los campos de ordenamiento permitidos
+var ValidSortFields = []string{SortByID, SortByCreatedAt, SortByDueAt, SortByPriority, SortByTitle}
+
+// IsValidSortField verifica si un campo de ordenamiento es válido
+func IsValidSortField(field string) bool {
+	for _, validField := range ValidSortFields {
+		if field == validField {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemFilter contiene los filtros opcionales para listar items.
+// Un campo nil (o vacío) significa que el filtro no se aplica.
+type ItemFilter struct {
+	ProjectID  *string
+	ParentID   *string
+	Overdue    *bool
+	DueBefore  *time.Time
+	Priorities []string
+
+	// Tags filtra por nombre de etiqueta; con TagMatchAll el item debe tenerlas todas,
+	// si no basta con que tenga alguna
+	Tags        []string
+	TagMatchAll bool
+
+	// IDs y ParentIDs permiten cargar en una sola consulta varios items o las
+	// subtareas de varios items
+	IDs       []string
+	ParentIDs []string
+
+	// AssigneeID filtra por responsable y Unassigned por los items sin responsable;
+	// CreatedBy filtra por creador y InvolvedUserID por creador o responsable
+	AssigneeID     *string
+	Unassigned     bool
+	CreatedBy      *string
+	InvolvedUserID *string
+
+	// SortBy es uno de ValidSortFields; vacío ordena por id
+	SortBy   string
+	SortDesc bool
+}
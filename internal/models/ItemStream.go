@@ -0,0 +1,164 @@
+package models
+
+import (
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// ItemStreamEvent es un evento del flujo append-only de un item. Data tiene los
+// valores nuevos de los campos que cambian, con los mismos nombres que en el JSON del item.
+type ItemStreamEvent struct {
+	ItemID     string         `json:"item_id"`
+	Version    int            `json:"version"`
+	Type       string         `json:"type"`
+	Data       map[string]any `json:"data"`
+	OccurredAt string         `json:"occurred_at"`
+}
+
+// streamFieldEvents indica qué evento del flujo registra el cambio de cada campo;
+// los que no están aquí se registran como ItemDetailsChanged
+var streamFieldEvents = map[string]string{
+	"state":      constants.StreamItemStateChanged,
+	"title":      constants.StreamItemRetitled,
+	"tags":       constants.StreamItemTagsChanged,
+	"project_id": constants.StreamItemMoved,
+	"parent_id":  constants.StreamItemReparented,
+	// created_by solo se registra al crear el item, en ItemCreated
+	"assignee_id": constants.StreamItemAssigned,
+}
+
+// streamEventOrder fija el orden en que se emiten los eventos de una misma actualización
+var streamEventOrder = []string{
+	constants.StreamItemRetitled,
+	constants.StreamItemDetailsChanged,
+	constants.StreamItemStateChanged,
+	constants.StreamItemTagsChanged,
+	constants.StreamItemMoved,
+	constants.StreamItemReparented,
+	constants.StreamItemAssigned,
+}
+
+// ChangeValues devuelve los valores nuevos de un conjunto de cambios
+func ChangeValues(changes map[string]FieldChange) map[string]any {
+	values := make(map[string]any, len(changes))
+	for field, change := range changes {
+		values[field] = change.New
+	}
+	return values
+}
+
+// StreamEventsFor traduce un evento del historial (constants.EventX) a los eventos
+// del flujo que lo representan, sin versión ni fecha
+func StreamEventsFor(eventType string, changes map[string]FieldChange) []ItemStreamEvent {
+	switch eventType {
+	case constants.EventCreated:
+		return []ItemStreamEvent{{Type: constants.StreamItemCreated, Data: ChangeValues(changes)}}
+	case constants.EventDeleted:
+		return []ItemStreamEvent{{Type: constants.StreamItemDeleted, Data: map[string]any{}}}
+	case constants.EventRestored:
+		return []ItemStreamEvent{{Type: constants.StreamItemRestored, Data: map[string]any{}}}
+	}
+
+	grouped := make(map[string]map[string]any)
+	for field, change := range changes {
+		streamType, ok := streamFieldEvents[field]
+		if !ok {
+			streamType = constants.StreamItemDetailsChanged
+		}
+		if grouped[streamType] == nil {
+			grouped[streamType] = make(map[string]any)
+		}
+		grouped[streamType][field] = change.New
+	}
+
+	events := []ItemStreamEvent{}
+	for _, streamType := range streamEventOrder {
+		if data, ok := grouped[streamType]; ok {
+			events = append(events, ItemStreamEvent{Type: streamType, Data: data})
+		}
+	}
+	return events
+}
+
+// HistoryType devuelve el tipo de evento del historial (constants.EventX) equivalente
+func (e ItemStreamEvent) HistoryType() string {
+	switch e.Type {
+	case constants.StreamItemCreated:
+		return constants.EventCreated
+	case constants.StreamItemDeleted:
+		return constants.EventDeleted
+	case constants.StreamItemRestored:
+		return constants.EventRestored
+	default:
+		return constants.EventUpdated
+	}
+}
+
+// ApplyEvent aplica sobre el item un evento del historial (constants.EventX) con
+// los valores nuevos de los campos, ocurrido en at
+func (item *TodoItem) ApplyEvent(eventType string, values map[string]any, at string) {
+	switch eventType {
+	case constants.EventCreated:
+		*item = TodoItem{ID: item.ID, Tags: []string{}, CreatedAt: at}
+	case constants.EventDeleted:
+		item.DeletedAt = &at
+	case constants.EventRestored:
+		item.DeletedAt = nil
+	}
+	item.UpdatedAt = at
+
+	for field, value := range values {
+		switch field {
+		case "title":
+			item.Title = stringValue(value)
+		case "description":
+			item.Description = stringValue(value)
+		case "state":
+			item.State = stringValue(value)
+		case "priority":
+			item.Priority = stringValue(value)
+		case "project_id":
+			item.ProjectID = optionalString(value)
+		case "parent_id":
+			item.ParentID = optionalString(value)
+		case "created_by":
+			item.CreatedBy = optionalString(value)
+		case "assignee_id":
+			item.AssigneeID = optionalString(value)
+		case "start_at":
+			item.StartAt = optionalString(value)
+		case "due_at":
+			item.DueAt = optionalString(value)
+		case "tags":
+			item.Tags = stringList(value)
+		}
+	}
+}
+
+// Los valores llegan como tipos de Go o decodificados desde JSON ([]any en vez de []string)
+func stringValue(value any) string {
+	s, _ := value.(string)
+	return s
+}
+
+func optionalString(value any) *string {
+	s, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	return &s
+}
+
+func stringList(value any) []string {
+	list := []string{}
+	switch values := value.(type) {
+	case []string:
+		list = append(list, values...)
+	case []any:
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+type Project struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Archived    bool    `json:"archived"`
+	ArchivedAt  *string `json:"archived_at,omitempty"`
+	// Counts es la cantidad de items (no eliminados) por estado
+	Counts    map[string]int `json:"counts"`
+	CreatedAt string         `json:"created_at"`
+	UpdatedAt string         `json:"updated_at"`
+}
+
+// ProjectMember es la membresía de un usuario en un proyecto, con el rol que
+// tiene en él en lugar de su rol global
+type ProjectMember struct {
+	ProjectID string `json:"project_id"`
+	UserID    string `json:"user_id"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// ValidateRole valida un rol (constants.RoleX)
+func ValidateRole(role string) error {
+	if !constants.IsValidRole(role) {
+		return errors.New(constants.RolInvalido)
+	}
+	return nil
+}
+
+// Validate valida los campos del Project
+func (p *Project) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New(constants.NombreProyectoRequerido)
+	}
+	return nil
+}
+
+// NewStateCounts devuelve un conteo en cero para cada estado válido
+func NewStateCounts() map[string]int {
+	counts := make(map[string]int, len(constants.ValidStates))
+	for _, state := range constants.ValidStates {
+		counts[state] = 0
+	}
+	return counts
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// Share comparte un item (ItemID) o un proyecto con sus items (ProjectID) con
+// un usuario del tenant (UserID) o, sin UserID, con quien tenga el enlace. Los
+// enlaces pueden ser read o edit; con un usuario solo edit, porque todos los
+// usuarios del tenant ya leen sus items (ver policy.Can). Del enlace se guarda
+// solo el hash; Token (el token en claro) se devuelve únicamente al crearlo.
+type Share struct {
+	ID         string  `json:"id"`
+	ItemID     *string `json:"item_id,omitempty"`
+	ProjectID  *string `json:"project_id,omitempty"`
+	UserID     *string `json:"user_id,omitempty"`
+	Permission string  `json:"permission"`
+	Token      string  `json:"token,omitempty"`
+	Prefix     string  `json:"prefix,omitempty"`
+	Hash       string  `json:"-"`
+	ExpiresAt  *string `json:"expires_at,omitempty"`
+	RevokedAt  *string `json:"revoked_at,omitempty"`
+	// CreatedBy es el usuario que lo creó; nil si se creó con una API key
+	CreatedBy *string `json:"created_by,omitempty"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+	TenantID  string  `json:"-"`
+}
+
+// Validate valida los campos del Share: el permiso (edit si es con un
+// usuario), que indique un solo destino y que el vencimiento, si lo tiene, sea
+// posterior a now
+func (s *Share) Validate(now time.Time) error {
+	if !constants.IsValidSharePermission(s.Permission) {
+		return errors.New(constants.PermisoCompartidoInvalido)
+	}
+	if !s.IsLink() && s.Permission == constants.SharePermissionRead {
+		return errors.New(constants.LecturaCompartidaConUsuario)
+	}
+	if (s.ItemID == nil) == (s.ProjectID == nil) {
+		return errors.New(constants.DestinoCompartidoRequerido)
+	}
+	if s.ExpiresAt != nil {
+		expiresAt, err := ParseTimestamp(*s.ExpiresAt)
+		if err != nil || !expiresAt.After(now) {
+			return errors.New(constants.VencimientoCompartidoInvalido)
+		}
+		normalized := expiresAt.UTC().Format(time.RFC3339)
+		s.ExpiresAt = &normalized
+	}
+	return nil
+}
+
+// IsLink indica si es un enlace (sin usuario destinatario)
+func (s *Share) IsLink() bool {
+	return s.UserID == nil
+}
+
+// Active indica si el compartido vale en now: no fue revocado ni venció
+func (s *Share) Active(now time.Time) bool {
+	if s.RevokedAt != nil {
+		return false
+	}
+	if s.ExpiresAt == nil {
+		return true
+	}
+	expiresAt, err := ParseTimestamp(*s.ExpiresAt)
+	return err == nil && now.Before(expiresAt)
+}
+
+// Covers indica si el compartido incluye al item: es el item compartido o está
+// en el proyecto compartido
+func (s *Share) Covers(item TodoItem) bool {
+	if s.ItemID != nil {
+		return *s.ItemID == item.ID
+	}
+	return s.ProjectID != nil && item.ProjectID != nil && *s.ProjectID == *item.ProjectID
+}
+
+// SharedContent es lo que ve quien abre un enlace compartido: el item o el
+// proyecto con sus items
+type SharedContent struct {
+	Permission string     `json:"permission"`
+	ExpiresAt  *string    `json:"expires_at,omitempty"`
+	Item       *TodoItem  `json:"item,omitempty"`
+	Project    *Project   `json:"project,omitempty"`
+	Items      []TodoItem `json:"items,omitempty"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+type Tag struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ItemCount int    `json:"item_count"`
+	CreatedAt string `json:"created_at"`
+}
+
+// NormalizeTagName deja el nombre en minúsculas y sin espacios alrededor,
+// de modo que "Backend" y " backend " sean la misma etiqueta
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ValidateTagName valida un nombre de etiqueta ya normalizado
+func ValidateTagName(name string) error {
+	if name == "" || len([]rune(name)) > constants.MaxTagLength || strings.Contains(name, ",") {
+		return errors.New(constants.EtiquetaInvalida)
+	}
+	return nil
+}
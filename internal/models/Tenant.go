@@ -0,0 +1,32 @@
+package models
+
+// Tenant es un equipo que usa la API con sus propios datos: items, proyectos,
+// etiquetas, webhooks, API keys y usuarios
+type Tenant struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	// MaxItems y MaxProjects son las cuotas del tenant; 0 es sin límite. Los
+	// items eliminados no cuentan.
+	MaxItems    int `json:"max_items"`
+	MaxProjects int `json:"max_projects"`
+	// AllowRegistration indica si cualquiera puede registrarse en el tenant
+	// (POST /auth/register); si no, sus usuarios llegan con OIDC
+	// (OIDC_TENANT_CLAIM) o los crea un administrador
+	AllowRegistration bool   `json:"allow_registration"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+	// Usage solo se completa en GET /tenant
+	Usage *TenantUsage `json:"usage,omitempty"`
+}
+
+// TenantUsage es lo que el tenant usa de sus cuotas
+type TenantUsage struct {
+	Items    int `json:"items"`
+	Projects int `json:"projects"`
+}
+
+// Exceeds indica si agregar uno más superaría la cuota max (0 es sin límite)
+func Exceeds(max int, used int) bool {
+	return max > 0 && used >= max
+}
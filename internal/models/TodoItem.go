@@ -1,37 +1,200 @@
 package models
 
 import (
-	"errors"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
 )
 
+// TodoItem es un item de la lista. CreatedBy es el usuario que lo creó (nil si lo
+// creó una API key) y AssigneeID su responsable; ninguno cambia al actualizar el
+// item, ver IRepository.AssignItem. BlockedBy (bloqueantes directos), Overdue,
+// Progress (porcentaje de subtareas completadas) y AllowedTransitions (próximos
+// estados según el flujo) son calculados y se ignoran al guardar.
 type TodoItem struct {
-	ID          string  `json:"id"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	State       string  `json:"state"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
-	DeletedAt   *string `json:"deleted_at,omitempty"`
+	ID          string   `json:"id"`
+	ProjectID   *string  `json:"project_id,omitempty"`
+	ParentID    *string  `json:"parent_id,omitempty"`
+	CreatedBy   *string  `json:"created_by,omitempty"`
+	AssigneeID  *string  `json:"assignee_id,omitempty"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	State       string   `json:"state"`
+	Priority    string   `json:"priority"`
+	Tags        []string `json:"tags"`
+	BlockedBy   []string `json:"blocked_by"`
+	StartAt     *string  `json:"start_at,omitempty"`
+	DueAt       *string  `json:"due_at,omitempty"`
+	Overdue     bool     `json:"overdue"`
+	Progress    *int     `json:"progress,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	DeletedAt   *string  `json:"deleted_at,omitempty"`
+
+	AllowedTransitions []string `json:"allowed_transitions,omitempty"`
+}
+
+// InvolvesUser indica si el usuario creó el item o es su responsable
+func (t *TodoItem) InvolvesUser(userID string) bool {
+	return (t.CreatedBy != nil && *t.CreatedBy == userID) || (t.AssigneeID != nil && *t.AssigneeID == userID)
 }
 
-// Validate valida los campos del TodoItem
+// ParseTimestamp interpreta una fecha en formato RFC3339 (ej: 2025-11-01T10:00:00Z)
+func ParseTimestamp(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, strings.TrimSpace(value))
+}
+
+// Validate valida los campos del TodoItem con los estados por defecto
 func (t *TodoItem) Validate() error {
+	return t.ValidateWithStates(constants.ValidStates)
+}
+
+// ValidateWithStates valida los campos del TodoItem aceptando los estados indicados
+// (los del flujo configurado). Devuelve FieldErrors con todos los campos inválidos.
+func (t *TodoItem) ValidateWithStates(validStates []string) error {
+	var errs FieldErrors
+	invalid := func(field, code, message string) {
+		errs = append(errs, FieldError{Field: field, Code: code, Message: message})
+	}
+
 	// Validar título
 	if strings.TrimSpace(t.Title) == "" {
-		return errors.New("el título es requerido")
+		invalid("title", constants.FieldRequired, constants.TituloRequerido)
 	}
 
 	// Validar estado
 	if strings.TrimSpace(t.State) == "" {
-		return errors.New("el estado es requerido")
+		invalid("state", constants.FieldRequired, constants.EstadoRequerido)
+	} else if !slices.Contains(validStates, t.State) {
+		invalid("state", constants.FieldInvalidState, constants.EstadoInvalido)
 	}
 
-	if !constants.IsValidState(t.State) {
-		return errors.New(constants.EstadoInvalido)
+	// Validar prioridad (opcional: vacía se completa con ApplyDefaults o se conserva al actualizar)
+	if t.Priority != "" && !constants.IsValidPriority(t.Priority) {
+		invalid("priority", constants.FieldInvalidPriority, constants.PrioridadInvalida)
 	}
 
+	// Validar fechas (opcionales)
+	var startAt, dueAt time.Time
+	var startErr, dueErr error
+	if t.StartAt != nil {
+		if startAt, startErr = ParseTimestamp(*t.StartAt); startErr != nil {
+			invalid("start_at", constants.FieldInvalidDate, constants.FechaInicioInvalida)
+		}
+	}
+	if t.DueAt != nil {
+		if dueAt, dueErr = ParseTimestamp(*t.DueAt); dueErr != nil {
+			invalid("due_at", constants.FieldInvalidDate, constants.FechaVencimientoInvalida)
+		}
+	}
+	if t.StartAt != nil && t.DueAt != nil && startErr == nil && dueErr == nil && startAt.After(dueAt) {
+		invalid("due_at", constants.FieldInvalidDateRange, constants.RangoFechasInvalido)
+	}
+
+	// Validar etiquetas
+	for _, tag := range t.Tags {
+		if err := ValidateTagName(tag); err != nil {
+			invalid("tags", constants.FieldInvalidTag, err.Error())
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
+
+// ApplyDefaults completa los campos opcionales que no fueron enviados al crear
+func (t *TodoItem) ApplyDefaults() {
+	if strings.TrimSpace(t.Priority) == "" {
+		t.Priority = constants.DefaultPriority
+	}
+}
+
+// NormalizeTags normaliza los nombres de etiquetas y elimina duplicados.
+// Un slice nil se conserva como nil (al actualizar significa "no modificar").
+func (t *TodoItem) NormalizeTags() {
+	if t.Tags == nil {
+		return
+	}
+	tags := make([]string, 0, len(t.Tags))
+	for _, tag := range t.Tags {
+		tag = NormalizeTagName(tag)
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	t.Tags = tags
+}
+
+// IsOverdue indica si el item venció: tiene fecha de vencimiento pasada y no está completado
+func (t *TodoItem) IsOverdue(now time.Time) bool {
+	if t.DueAt == nil || t.State == constants.StateCompleted {
+		return false
+	}
+	dueAt, err := ParseTimestamp(*t.DueAt)
+	if err != nil {
+		return false
+	}
+	return dueAt.Before(now)
+}
+
+// RefreshOverdue recalcula el campo calculado Overdue
+func (t *TodoItem) RefreshOverdue(now time.Time) {
+	t.Overdue = t.IsOverdue(now)
+}
+
+// ItemNode es un item con sus subtareas anidadas, usado en GET /items/:id/subtree
+type ItemNode struct {
+	TodoItem
+	Children []ItemNode `json:"children"`
+}
+
+// BuildTree arma el árbol de root a partir de la lista plana de sus descendientes
+func BuildTree(root TodoItem, descendants []TodoItem) ItemNode {
+	children := make(map[string][]TodoItem)
+	for _, item := range descendants {
+		if item.ParentID != nil {
+			children[*item.ParentID] = append(children[*item.ParentID], item)
+		}
+	}
+
+	var build func(item TodoItem) ItemNode
+	build = func(item TodoItem) ItemNode {
+		node := ItemNode{TodoItem: item, Children: []ItemNode{}}
+		for _, child := range children[item.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+	return build(root)
+}
+
+// Height devuelve la cantidad de niveles del árbol (1 si no tiene subtareas)
+func (n ItemNode) Height() int {
+	height := 0
+	for _, child := range n.Children {
+		height = max(height, child.Height())
+	}
+	return height + 1
+}
+
+// Each aplica fn a todos los items del árbol
+func (n *ItemNode) Each(fn func(item *TodoItem)) {
+	fn(&n.TodoItem)
+	for i := range n.Children {
+		n.Children[i].Each(fn)
+	}
+}
+
+// ComputeProgress calcula el porcentaje de subtareas completadas (redondeado hacia abajo)
+func ComputeProgress(completed int, total int) *int {
+	if total == 0 {
+		return nil
+	}
+	progress := completed * 100 / total
+	return &progress
+}
@@ -0,0 +1,122 @@
+package models
+
+import "time"
+
+// TodoItemV2 es la representación de un item en la versión 2 de la API: las
+// fechas son time.Time (RFC3339 en UTC) y los campos opcionales se envían
+// siempre, con null cuando no tienen valor
+type TodoItemV2 struct {
+	ID                 string     `json:"id"`
+	ProjectID          *string    `json:"project_id"`
+	ParentID           *string    `json:"parent_id"`
+	CreatedBy          *string    `json:"created_by"`
+	AssigneeID         *string    `json:"assignee_id"`
+	Title              string     `json:"title"`
+	Description        string     `json:"description"`
+	State              string     `json:"state"`
+	Priority           string     `json:"priority"`
+	Tags               []string   `json:"tags"`
+	BlockedBy          []string   `json:"blocked_by"`
+	StartAt            *time.Time `json:"start_at"`
+	DueAt              *time.Time `json:"due_at"`
+	Overdue            bool       `json:"overdue"`
+	Progress           *int       `json:"progress"`
+	AllowedTransitions []string   `json:"allowed_transitions"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	DeletedAt          *time.Time `json:"deleted_at"`
+}
+
+// ItemNodeV2 es un item de la versión 2 con sus subtareas anidadas
+type ItemNodeV2 struct {
+	TodoItemV2
+	Children []ItemNodeV2 `json:"children"`
+}
+
+// ItemEventV2 es un evento del historial en la versión 2 de la API
+type ItemEventV2 struct {
+	ID        string                 `json:"id"`
+	ItemID    string                 `json:"item_id"`
+	Type      string                 `json:"type"`
+	Actor     *string                `json:"actor"`
+	RequestID *string                `json:"request_id"`
+	Changes   map[string]FieldChange `json:"changes"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// V2 convierte el item a su representación de la versión 2
+func (t TodoItem) V2() TodoItemV2 {
+	return TodoItemV2{
+		ID:                 t.ID,
+		ProjectID:          t.ProjectID,
+		ParentID:           t.ParentID,
+		CreatedBy:          t.CreatedBy,
+		AssigneeID:         t.AssigneeID,
+		Title:              t.Title,
+		Description:        t.Description,
+		State:              t.State,
+		Priority:           t.Priority,
+		Tags:               nonNil(t.Tags),
+		BlockedBy:          nonNil(t.BlockedBy),
+		StartAt:            optionalTime(t.StartAt),
+		DueAt:              optionalTime(t.DueAt),
+		Overdue:            t.Overdue,
+		Progress:           t.Progress,
+		AllowedTransitions: nonNil(t.AllowedTransitions),
+		CreatedAt:          typedTime(t.CreatedAt),
+		UpdatedAt:          typedTime(t.UpdatedAt),
+		DeletedAt:          optionalTime(t.DeletedAt),
+	}
+}
+
+// V2 convierte el árbol a su representación de la versión 2
+func (n ItemNode) V2() ItemNodeV2 {
+	children := make([]ItemNodeV2, len(n.Children))
+	for i, child := range n.Children {
+		children[i] = child.V2()
+	}
+	return ItemNodeV2{TodoItemV2: n.TodoItem.V2(), Children: children}
+}
+
+// V2 convierte el evento a su representación de la versión 2
+func (e ItemEvent) V2() ItemEventV2 {
+	event := ItemEventV2{
+		ID:        e.ID,
+		ItemID:    e.ItemID,
+		Type:      e.Type,
+		Changes:   e.Changes,
+		CreatedAt: typedTime(e.CreatedAt),
+	}
+	if e.Actor != "" {
+		event.Actor = &e.Actor
+	}
+	if e.RequestID != "" {
+		event.RequestID = &e.RequestID
+	}
+	return event
+}
+
+// typedTime interpreta una fecha guardada como texto RFC3339; una fecha inválida
+// queda en cero
+func typedTime(value string) time.Time {
+	parsed, err := ParseTimestamp(value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed.UTC()
+}
+
+func optionalTime(value *string) *time.Time {
+	if value == nil {
+		return nil
+	}
+	parsed := typedTime(*value)
+	return &parsed
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
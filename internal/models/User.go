@@ -0,0 +1,59 @@
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// User es una persona que usa la API. PasswordHash nunca se devuelve.
+type User struct {
+	ID           string `json:"id"`
+	Email        string `json:"email"`
+	Name         string `json:"name"`
+	PasswordHash string `json:"-"`
+	// Issuer y Subject identifican al usuario en el proveedor OIDC con el que
+	// inició sesión; están vacíos para los usuarios locales
+	Issuer    string `json:"issuer,omitempty"`
+	Subject   string `json:"-"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	// Roles son los roles del token del proveedor OIDC; no se guardan
+	Roles []string `json:"roles,omitempty"`
+	// Role es el rol global (constants.RoleX) y ProjectRoles el rol en cada
+	// proyecto del que es miembro, por ID de proyecto
+	Role         string            `json:"role"`
+	ProjectRoles map[string]string `json:"project_roles,omitempty"`
+	// Shares son los compartidos vigentes con el usuario; no se devuelven
+	Shares []Share `json:"-"`
+	// TenantID es el tenant al que pertenece; sus tokens solo valen en él
+	TenantID string `json:"tenant_id"`
+}
+
+// Validate valida y normaliza los campos del User (el email se guarda en minúsculas)
+func (u *User) Validate() error {
+	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
+	u.Name = strings.TrimSpace(u.Name)
+	if address, err := mail.ParseAddress(u.Email); err != nil || address.Address != u.Email {
+		return errors.New(constants.EmailInvalido)
+	}
+	return nil
+}
+
+// RefreshToken es un token opaco para obtener nuevos tokens de acceso. Se
+// guarda solo su hash; cada uso lo revoca y emite uno nuevo (rotación).
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	Hash      string
+	ExpiresAt string
+	RevokedAt *string
+	CreatedAt string
+}
+
+// Revoked indica si el token fue revocado (usado, cerrado o invalidado)
+func (t *RefreshToken) Revoked() bool {
+	return t.RevokedAt != nil
+}
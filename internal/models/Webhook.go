@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// Webhook es una suscripción a los eventos de los items. Events vacío recibe
+// todos los eventos. Secret firma los payloads y solo se devuelve al crear.
+type Webhook struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Secret    string   `json:"secret,omitempty"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// Validate valida los campos del Webhook. La URL no puede apuntar a la propia
+// máquina ni a la red interna; los nombres que resuelven a esas direcciones se
+// rechazan al conectarse (ver webhooks.Dispatcher).
+func (w *Webhook) Validate() error {
+	parsed, err := url.Parse(w.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New(constants.URLWebhookInvalida)
+	}
+	if !publicHost(parsed.Hostname()) {
+		return errors.New(constants.URLWebhookInterna)
+	}
+	for _, event := range w.Events {
+		if !constants.IsValidWebhookEvent(event) {
+			return errors.New(constants.EventoWebhookInvalido)
+		}
+	}
+	return nil
+}
+
+// sharedAddressSpace es el rango de CGNAT (RFC 6598), interno aunque no sea privado
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// PublicAddress indica si addr es una dirección pública, a la que se pueden
+// enviar webhooks: no es de loopback, privada, link-local, multicast ni la no
+// especificada
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// publicHost indica si el host de una URL puede ser público: localhost y las IP
+// internas no lo son; el resto de los nombres se verifican al conectarse
+func publicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return PublicAddress(addr)
+	}
+	return true
+}
+
+// Accepts indica si el webhook está suscrito al evento
+func (w *Webhook) Accepts(event string) bool {
+	return w.Active && (len(w.Events) == 0 || slices.Contains(w.Events, event))
+}
+
+// WebhookPayload es el cuerpo JSON que se envía a los webhooks
+type WebhookPayload struct {
+	Event      string                 `json:"event"`
+	ItemID     string                 `json:"item_id"`
+	Changes    map[string]FieldChange `json:"changes"`
+	Actor      string                 `json:"actor,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	OccurredAt string                 `json:"occurred_at"`
+}
+
+// WebhookEventsFor devuelve los eventos de webhook que genera un evento del
+// historial; completar un item genera además item.completed
+func WebhookEventsFor(event ItemEvent) []string {
+	var events []string
+	switch event.Type {
+	case constants.EventCreated:
+		events = []string{constants.WebhookItemCreated}
+	case constants.EventUpdated:
+		events = []string{constants.WebhookItemUpdated}
+	case constants.EventDeleted:
+		return []string{constants.WebhookItemDeleted}
+	case constants.EventRestored:
+		return []string{constants.WebhookItemRestored}
+	default:
+		return nil
+	}
+
+	if change, ok := event.Changes["state"]; ok && change.New == constants.StateCompleted {
+		events = append(events, constants.WebhookItemCompleted)
+	}
+	return events
+}
+
+// WebhookDelivery es el envío de un evento a un webhook, con sus reintentos
+type WebhookDelivery struct {
+	ID             string  `json:"id"`
+	WebhookID      string  `json:"webhook_id"`
+	Event          string  `json:"event"`
+	Payload        []byte  `json:"-"`
+	Status         string  `json:"status"`
+	Attempts       int     `json:"attempts"`
+	NextAttemptAt  *string `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int    `json:"last_status_code,omitempty"`
+	LastError      string  `json:"last_error,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+	UpdatedAt      string  `json:"updated_at"`
+
+	// URL y Secret del webhook, para el despachador
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
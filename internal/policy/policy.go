@@ -0,0 +1,60 @@
+// Package policy decide qué puede hacer cada rol: viewer solo lee, member además
+// crea y modifica, y admin además elimina y administra (webhooks, API keys,
+// roles y miembros de proyectos). Un usuario tiene un rol global y, en los
+// proyectos de los que es miembro, el rol de su membresía. Can y CanModify
+// aplican esos roles, y los compartidos, a quien hace una petición.
+package policy
+
+import (
+	"slices"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// permissions son los permisos de cada rol
+var permissions = map[string][]string{
+	constants.RoleViewer: {constants.PermissionRead},
+	constants.RoleMember: {constants.PermissionRead, constants.PermissionWrite},
+	constants.RoleAdmin:  {constants.PermissionRead, constants.PermissionWrite, constants.PermissionDelete, constants.PermissionManage},
+}
+
+// roleScopes son los scopes de las rutas que corresponden a cada rol global
+var roleScopes = map[string][]string{
+	constants.RoleViewer: {constants.ScopeItemsRead},
+	constants.RoleMember: {constants.ScopeItemsRead, constants.ScopeItemsWrite},
+	constants.RoleAdmin:  {constants.ScopeAdmin},
+}
+
+// Allows indica si el rol otorga el permiso
+func Allows(role string, permission string) bool {
+	return slices.Contains(permissions[role], permission)
+}
+
+// RoleIn devuelve el rol de un usuario con el rol global role y los roles por
+// proyecto projectRoles en el proyecto projectID (nil: recursos sin proyecto).
+// El rol de la membresía reemplaza al global, salvo para los admin globales.
+func RoleIn(role string, projectRoles map[string]string, projectID *string) string {
+	if role == constants.RoleAdmin || projectID == nil {
+		return role
+	}
+	if projectRole, ok := projectRoles[*projectID]; ok {
+		return projectRole
+	}
+	return role
+}
+
+// Scopes devuelve los scopes de las rutas de un usuario: los de su rol global
+// más items:write si algún proyecto le permite escribir. Las rutas dejan pasar
+// la petición y el permiso sobre cada proyecto se decide con RoleIn.
+func Scopes(role string, projectRoles map[string]string) []string {
+	scopes := slices.Clone(roleScopes[role])
+	if slices.Contains(scopes, constants.ScopeItemsWrite) || slices.Contains(scopes, constants.ScopeAdmin) {
+		return scopes
+	}
+	for _, projectRole := range projectRoles {
+		if Allows(projectRole, constants.PermissionWrite) {
+			return append(scopes, constants.ScopeItemsWrite)
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission string
+		allowed    bool
+	}{
+		{constants.RoleViewer, constants.PermissionRead, true},
+		{constants.RoleViewer, constants.PermissionWrite, false},
+		{constants.RoleMember, constants.PermissionWrite, true},
+		{constants.RoleMember, constants.PermissionDelete, false},
+		{constants.RoleMember, constants.PermissionManage, false},
+		{constants.RoleAdmin, constants.PermissionDelete, true},
+		{constants.RoleAdmin, constants.PermissionManage, true},
+		{"owner", constants.PermissionRead, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, Allows(tt.role, tt.permission), tt.role+" "+tt.permission)
+	}
+}
+
+func TestRoleIn(t *testing.T) {
+	projectRoles := map[string]string{"1": constants.RoleAdmin, "2": constants.RoleViewer}
+	one, two, three := "1", "2", "3"
+
+	assert.Equal(t, constants.RoleAdmin, RoleIn(constants.RoleMember, projectRoles, &one))
+	assert.Equal(t, constants.RoleViewer, RoleIn(constants.RoleMember, projectRoles, &two))
+	assert.Equal(t, constants.RoleMember, RoleIn(constants.RoleMember, projectRoles, &three))
+	assert.Equal(t, constants.RoleMember, RoleIn(constants.RoleMember, projectRoles, nil), "sin proyecto vale el rol global")
+	assert.Equal(t, constants.RoleAdmin, RoleIn(constants.RoleAdmin, projectRoles, &two), "un admin global no pierde permisos")
+}
+
+func TestScopes(t *testing.T) {
+	assert.Equal(t, []string{constants.ScopeItemsRead}, Scopes(constants.RoleViewer, nil))
+	assert.Equal(t, []string{constants.ScopeItemsRead, constants.ScopeItemsWrite}, Scopes(constants.RoleMember, nil))
+	assert.Equal(t, []string{constants.ScopeAdmin}, Scopes(constants.RoleAdmin, map[string]string{"1": constants.RoleViewer}))
+
+	// Un viewer que es member de algún proyecto necesita llegar a las rutas de escritura
+	assert.Equal(t, []string{constants.ScopeItemsRead, constants.ScopeItemsWrite},
+		Scopes(constants.RoleViewer, map[string]string{"1": constants.RoleMember}))
+	assert.Empty(t, Scopes("owner", nil))
+}
+
+func TestCan(t *testing.T) {
+	one, two := "1", "2"
+	viewer := models.RequestMeta{UserID: "7", Role: constants.RoleViewer, ProjectRoles: map[string]string{"1": constants.RoleMember}}
+	assert.True(t, Can(viewer, constants.PermissionWrite, &one))
+	assert.False(t, Can(viewer, constants.PermissionWrite, &two))
+	assert.True(t, Can(models.RequestMeta{}, constants.PermissionDelete, &two), "las API keys solo se limitan con los scopes")
+
+	// Un proyecto compartido con permiso edit permite escribir, pero no eliminar
+	viewer.Shares = []models.Share{{ProjectID: &two, Permission: constants.SharePermissionEdit}}
+	assert.True(t, Can(viewer, constants.PermissionWrite, &two))
+	assert.False(t, Can(viewer, constants.PermissionDelete, &two))
+
+	guest := models.RequestMeta{Guest: true, Shares: []models.Share{{ProjectID: &one, Permission: constants.SharePermissionRead}}}
+	assert.True(t, Can(guest, constants.PermissionRead, &one))
+	assert.False(t, Can(guest, constants.PermissionWrite, &one))
+	assert.False(t, Can(guest, constants.PermissionRead, nil))
+}
+
+func TestCanModify(t *testing.T) {
+	one, creator := "1", "7"
+	item := models.TodoItem{ID: "5", ProjectID: &one, CreatedBy: &creator}
+	assert.True(t, CanModify(models.RequestMeta{UserID: "7", Role: constants.RoleMember}, item))
+	assert.False(t, CanModify(models.RequestMeta{UserID: "8", Role: constants.RoleMember}, item))
+	assert.True(t, CanModify(models.RequestMeta{UserID: "8", Role: constants.RoleMember, ProjectRoles: map[string]string{"1": constants.RoleAdmin}}, item))
+
+	// Un compartido vencido no otorga nada
+	itemID, expired := "5", "2000-01-01T00:00:00Z"
+	shared := models.RequestMeta{UserID: "8", Role: constants.RoleViewer, Shares: []models.Share{
+		{ItemID: &itemID, Permission: constants.SharePermissionEdit, ExpiresAt: &expired},
+		{ItemID: &itemID, Permission: constants.SharePermissionRead},
+	}}
+	assert.Equal(t, constants.SharePermissionRead, SharedPermission(shared, item))
+	assert.False(t, CanModify(shared, item))
+	shared.Shares[0].ExpiresAt = nil
+	assert.Equal(t, constants.SharePermissionEdit, SharedPermission(shared, item))
+	assert.True(t, CanModify(shared, item))
+}
@@ -0,0 +1,78 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+// Can indica si quien hace la petición tiene el permiso (constants.PermissionX)
+// sobre los recursos del proyecto projectID (nil: sin proyecto) según su rol.
+// Las API keys y los administradores se limitan solo con los scopes de las rutas.
+// Un proyecto compartido otorga read y, con permiso edit, también write. Todos
+// los roles leen, así que para un usuario solo importan los compartidos edit;
+// los read sirven a los enlaces (meta.Guest).
+func Can(meta models.RequestMeta, permission string, projectID *string) bool {
+	if sharesProject(meta, permission, projectID) {
+		return true
+	}
+	if meta.Guest {
+		return false
+	}
+	if meta.UserID == "" || meta.Admin {
+		return true
+	}
+	return Allows(RoleIn(meta.Role, meta.ProjectRoles, projectID), permission)
+}
+
+// CanModify indica si quien hace la petición puede modificar el item: las API
+// keys, los administradores y los admin del proyecto pueden modificar cualquiera,
+// y el resto de los usuarios solo los que crearon o tienen asignados o les
+// compartieron con permiso edit
+func CanModify(meta models.RequestMeta, item models.TodoItem) bool {
+	if SharedPermission(meta, item) == constants.SharePermissionEdit {
+		return true
+	}
+	if meta.Guest {
+		return false
+	}
+	return meta.UserID == "" || meta.Admin || item.InvolvesUser(meta.UserID) ||
+		RoleIn(meta.Role, meta.ProjectRoles, item.ProjectID) == constants.RoleAdmin
+}
+
+// SharedPermission devuelve el mayor permiso (constants.SharePermissionX) que
+// otorgan sobre el item los compartidos vigentes de meta.Shares; vacío si ninguno lo incluye
+func SharedPermission(meta models.RequestMeta, item models.TodoItem) string {
+	permission := ""
+	now := time.Now()
+	for _, share := range meta.Shares {
+		if !share.Active(now) || !share.Covers(item) {
+			continue
+		}
+		if share.Permission == constants.SharePermissionEdit {
+			return share.Permission
+		}
+		permission = share.Permission
+	}
+	return permission
+}
+
+// sharesProject indica si un compartido vigente del proyecto projectID otorga
+// el permiso: read con cualquier compartido y write con uno edit
+func sharesProject(meta models.RequestMeta, permission string, projectID *string) bool {
+	if projectID == nil {
+		return false
+	}
+	now := time.Now()
+	for _, share := range meta.Shares {
+		if share.ProjectID == nil || *share.ProjectID != *projectID || !share.Active(now) {
+			continue
+		}
+		if permission == constants.PermissionRead ||
+			(permission == constants.PermissionWrite && share.Permission == constants.SharePermissionEdit) {
+			return true
+		}
+	}
+	return false
+}
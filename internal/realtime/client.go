@@ -0,0 +1,190 @@
+package realtime
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/gorilla/websocket"
+)
+
+// client es una conexión WebSocket. Solo writePump escribe en la conexión; el
+// resto encola los mensajes en send sin bloquearse.
+type client struct {
+	id       string
+	actor    string
+	tenantID string
+	conn     *websocket.Conn
+	server   *Server
+	send     chan []byte
+
+	// credentials son las cabeceras de autenticación de la conexión; se envían en
+	// cada mutación, que se autentica de nuevo (por ejemplo si se revocaron)
+	credentials http.Header
+
+	mu         sync.Mutex
+	subscribed bool
+	itemIDs    []string
+	project    string
+	states     []string
+	viewing    map[string]struct{}
+
+	closeOnce   sync.Once
+	done        chan struct{}
+	closeCode   int
+	closeReason string
+}
+
+// wants indica si el cliente está suscripto a los cambios del evento
+func (c *client) wants(event events.Event) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.subscribed || event.TenantID != c.tenantID {
+		return false
+	}
+	if len(c.itemIDs) > 0 && !slices.Contains(c.itemIDs, event.ItemID) {
+		return false
+	}
+	if len(c.states) > 0 && !slices.Contains(c.states, event.Item.State) {
+		return false
+	}
+	if c.project != "" && (event.Item.ProjectID == nil || *event.Item.ProjectID != c.project) {
+		return false
+	}
+	return true
+}
+
+// watches indica si al cliente le interesa la presencia en un item: lo está
+// viendo o está suscripto a él por ID (o a todos los items)
+func (c *client) watches(itemID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.viewing[itemID]; ok {
+		return true
+	}
+	if !c.subscribed {
+		return false
+	}
+	if len(c.itemIDs) > 0 {
+		return slices.Contains(c.itemIDs, itemID)
+	}
+	return c.project == "" && len(c.states) == 0
+}
+
+func (c *client) subscribe(message clientMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscribed = true
+	c.itemIDs = message.ItemIDs
+	c.project = message.Project
+	c.states = message.States
+}
+
+func (c *client) unsubscribe() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscribed = false
+	c.itemIDs, c.project, c.states = nil, "", nil
+}
+
+// enqueue encola un mensaje; si el buffer del cliente está lleno lo desconecta
+// para no frenar al resto
+func (c *client) enqueue(message any) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	select {
+	case <-c.done:
+	case c.send <- data:
+	default:
+		c.close(websocket.CloseTryAgainLater, constants.WSClienteLento)
+	}
+}
+
+// close marca al cliente para desconectarlo; writePump envía el código de cierre
+func (c *client) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeReason = reason
+		close(c.done)
+	})
+}
+
+// forward encola los eventos del hub hasta que se cierre la suscripción. El
+// hub la cierra si el cliente no consume a tiempo.
+func (c *client) forward(sub *events.Subscription) {
+	for event := range sub.Events {
+		event := event
+		event.Item.RefreshOverdue(time.Now())
+		c.enqueue(serverMessage{Type: constants.WSEvent, Event: &event})
+	}
+	c.close(websocket.CloseTryAgainLater, constants.WSClienteLento)
+}
+
+// writePump escribe los mensajes encolados y envía un ping cada PingInterval
+func (c *client) writePump() {
+	config := c.server.config
+	ping := time.NewTicker(config.PingInterval)
+	defer func() {
+		ping.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(config.WriteTimeout))
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ping.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(config.WriteTimeout)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-c.done:
+			message := websocket.FormatCloseMessage(c.closeCode, c.closeReason)
+			c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(config.WriteTimeout))
+			return
+		}
+	}
+}
+
+// readPump procesa los mensajes del cliente hasta que se cierra la conexión o
+// deja de responder los pings
+func (c *client) readPump() {
+	config := c.server.config
+	pongWait := 2 * config.PingInterval
+
+	c.conn.SetReadLimit(config.MaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		// Cualquier mensaje del cliente también cuenta como señal de vida
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		var message clientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			c.enqueue(serverMessage{Type: constants.WSError, Error: constants.WSMensajeInvalido})
+			continue
+		}
+		c.server.handle(c, message)
+	}
+}
@@ -0,0 +1,48 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_DisconnectsWhenBufferIsFull(t *testing.T) {
+	c := &client{send: make(chan []byte, 1), done: make(chan struct{})}
+
+	c.enqueue(serverMessage{Type: constants.WSPong})
+	c.enqueue(serverMessage{Type: constants.WSPong})
+
+	select {
+	case <-c.done:
+	default:
+		t.Fatal("el cliente lento no se desconectó")
+	}
+	assert.Equal(t, websocket.CloseTryAgainLater, c.closeCode)
+	assert.Equal(t, constants.WSClienteLento, c.closeReason)
+}
+
+func TestClient_Wants(t *testing.T) {
+	project := "2"
+	event := events.Event{ItemID: "1", Item: models.TodoItem{ID: "1", State: constants.StatePending, ProjectID: &project}}
+	c := &client{viewing: make(map[string]struct{})}
+
+	assert.False(t, c.wants(event), "sin suscripción no recibe eventos")
+
+	c.subscribe(clientMessage{})
+	assert.True(t, c.wants(event))
+	assert.True(t, c.watches("1"))
+
+	c.subscribe(clientMessage{ItemIDs: []string{"3"}})
+	assert.False(t, c.wants(event))
+
+	c.subscribe(clientMessage{Project: "2", States: []string{constants.StatePending}})
+	assert.True(t, c.wants(event))
+	assert.False(t, c.watches("1"), "la presencia solo se envía por ID o a quien ve el item")
+
+	c.unsubscribe()
+	assert.False(t, c.wants(event))
+}
@@ -0,0 +1,46 @@
+package realtime
+
+import (
+	"encoding/json"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+)
+
+// clientMessage es un mensaje recibido del cliente. Según Type se usan unos campos u otros:
+//
+//	{"type":"subscribe","item_ids":["1","2"],"project":"3","states":["pending"]}
+//	{"type":"view","item_id":"1"}
+//	{"type":"create","ref":"a1","data":{"title":"..."}}
+//	{"type":"update","ref":"a2","item_id":"1","data":{...}}
+//	{"type":"transition","ref":"a3","item_id":"1","data":{"to":"completed"}}
+//	{"type":"delete","ref":"a4","item_id":"1"}
+type clientMessage struct {
+	Type string `json:"type"`
+	// Ref lo elige el cliente para asociar la respuesta de una mutación
+	Ref     string          `json:"ref,omitempty"`
+	ItemIDs []string        `json:"item_ids,omitempty"`
+	Project string          `json:"project,omitempty"`
+	States  []string        `json:"states,omitempty"`
+	ItemID  string          `json:"item_id,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// serverMessage es un mensaje enviado al cliente
+type serverMessage struct {
+	Type     string        `json:"type"`
+	Ref      string        `json:"ref,omitempty"`
+	ClientID string        `json:"client_id,omitempty"`
+	Event    *events.Event `json:"event,omitempty"`
+	ItemID   string        `json:"item_id,omitempty"`
+	Viewers  []Viewer      `json:"viewers,omitempty"`
+	// Status y Body son la respuesta de una mutación, igual a la de la API REST
+	Status int             `json:"status,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// Viewer es un cliente que está viendo un item
+type Viewer struct {
+	ClientID string `json:"client_id"`
+	Actor    string `json:"actor,omitempty"`
+}
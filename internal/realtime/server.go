@@ -0,0 +1,310 @@
+// Package realtime implementa el canal colaborativo en vivo por WebSocket: los
+// clientes se suscriben a conjuntos de items, reciben sus cambios, envían
+// mutaciones y ven quién más está mirando cada item.
+package realtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/gorilla/websocket"
+)
+
+// Server mantiene los clientes conectados a esta instancia. Los cambios de items
+// llegan por el hub, así que se ven los hechos en cualquier instancia; la
+// presencia, en cambio, es solo de los clientes conectados a esta.
+type Server struct {
+	hub *events.Hub
+	// mutations atiende las mutaciones con los mismos handlers que la API REST
+	mutations http.Handler
+	config    config.WebSocketConfig
+	upgrader  websocket.Upgrader
+
+	mu      sync.Mutex
+	nextID  uint64
+	clients map[*client]struct{}
+	// viewers está indexado por presenceKey: cada tenant tiene su propia presencia
+	viewers map[string]map[*client]struct{}
+}
+
+// presenceMessage informa quién está viendo un item; Viewers vacío indica que nadie
+type presenceMessage struct {
+	Type    string   `json:"type"`
+	ItemID  string   `json:"item_id"`
+	Viewers []Viewer `json:"viewers"`
+}
+
+// NewServer crea el servidor. mutations debe atender POST /items, PUT /items/:id,
+// DELETE /items/:id y POST /items/:id/transitions.
+func NewServer(hub *events.Hub, mutations http.Handler, wsConfig config.WebSocketConfig) *Server {
+	s := &Server{
+		hub:       hub,
+		mutations: mutations,
+		config:    wsConfig,
+		clients:   make(map[*client]struct{}),
+		viewers:   make(map[string]map[*client]struct{}),
+	}
+	// Un navegador que pide subprotocolos rechaza la conexión si el servidor no elige uno
+	s.upgrader = websocket.Upgrader{CheckOrigin: s.checkOrigin, Subprotocols: []string{constants.WSProtocol}}
+	return s
+}
+
+// checkOrigin acepta el propio origen y los configurados en AllowedOrigins
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || slices.Contains(s.config.AllowedOrigins, "*") || slices.Contains(s.config.AllowedOrigins, origin) {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && parsed.Host == r.Host
+}
+
+// Serve convierte la petición en una conexión WebSocket y la atiende hasta que
+// se cierra. actor es quien aparece en la presencia y en el historial de las
+// mutaciones; el cliente solo recibe los cambios y la presencia de tenant.
+func (s *Server) Serve(w http.ResponseWriter, r *http.Request, actor string, tenant models.Tenant) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade ya respondió el error al cliente
+		return
+	}
+
+	c := &client{
+		actor:    actor,
+		tenantID: tenant.ID,
+		conn:     conn,
+		server:   s,
+		send:     make(chan []byte, s.config.SendBuffer),
+		viewing:  make(map[string]struct{}),
+		done:     make(chan struct{}),
+		credentials: http.Header{
+			constants.APIKeyHeader: r.Header.Values(constants.APIKeyHeader),
+			"Authorization":        r.Header.Values("Authorization"),
+			// El tenant pudo venir del subdominio, que la mutación no tiene
+			constants.TenantHeader: {tenant.Slug},
+		},
+	}
+	s.register(c)
+	sub, _, _ := s.hub.Subscribe(0, c.wants)
+
+	go c.writePump()
+	go c.forward(sub)
+	c.enqueue(serverMessage{Type: constants.WSWelcome, ClientID: c.id})
+
+	c.readPump()
+
+	c.close(websocket.CloseNormalClosure, "")
+	sub.Close()
+	s.unregister(c)
+}
+
+func (s *Server) register(c *client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	c.id = strconv.FormatUint(s.nextID, 10)
+	s.clients[c] = struct{}{}
+}
+
+// unregister quita al cliente y avisa que dejó de ver sus items
+func (s *Server) unregister(c *client) {
+	s.mu.Lock()
+	delete(s.clients, c)
+	s.mu.Unlock()
+
+	c.mu.Lock()
+	viewing := make([]string, 0, len(c.viewing))
+	for itemID := range c.viewing {
+		viewing = append(viewing, itemID)
+	}
+	c.mu.Unlock()
+
+	for _, itemID := range viewing {
+		s.leave(c, itemID)
+	}
+}
+
+// handle procesa un mensaje del cliente
+func (s *Server) handle(c *client, message clientMessage) {
+	switch message.Type {
+	case constants.WSSubscribe:
+		c.subscribe(message)
+		for _, itemID := range message.ItemIDs {
+			c.enqueue(s.presence(c.tenantID, itemID))
+		}
+	case constants.WSUnsubscribe:
+		c.unsubscribe()
+	case constants.WSView:
+		if message.ItemID == "" {
+			c.enqueue(serverMessage{Type: constants.WSError, Ref: message.Ref, Error: constants.IDInvalido})
+			return
+		}
+		s.view(c, message.ItemID)
+	case constants.WSLeave:
+		s.leave(c, message.ItemID)
+	case constants.WSPing:
+		c.enqueue(serverMessage{Type: constants.WSPong, Ref: message.Ref})
+	case constants.WSCreate, constants.WSUpdate, constants.WSDelete, constants.WSTransition:
+		s.mutate(c, message)
+	default:
+		c.enqueue(serverMessage{Type: constants.WSError, Ref: message.Ref, Error: constants.WSTipoDesconocido})
+	}
+}
+
+func (s *Server) view(c *client, itemID string) {
+	c.mu.Lock()
+	c.viewing[itemID] = struct{}{}
+	c.mu.Unlock()
+
+	key := presenceKey(c.tenantID, itemID)
+	s.mu.Lock()
+	if s.viewers[key] == nil {
+		s.viewers[key] = make(map[*client]struct{})
+	}
+	s.viewers[key][c] = struct{}{}
+	s.mu.Unlock()
+
+	s.broadcastPresence(c.tenantID, itemID)
+}
+
+func (s *Server) leave(c *client, itemID string) {
+	c.mu.Lock()
+	_, ok := c.viewing[itemID]
+	delete(c.viewing, itemID)
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	key := presenceKey(c.tenantID, itemID)
+	s.mu.Lock()
+	delete(s.viewers[key], c)
+	if len(s.viewers[key]) == 0 {
+		delete(s.viewers, key)
+	}
+	s.mu.Unlock()
+
+	s.broadcastPresence(c.tenantID, itemID)
+}
+
+// presenceKey identifica un item dentro de su tenant
+func presenceKey(tenantID string, itemID string) string {
+	return tenantID + "/" + itemID
+}
+
+// presence arma el mensaje con quién del tenant está viendo el item
+func (s *Server) presence(tenantID string, itemID string) presenceMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := presenceKey(tenantID, itemID)
+	viewers := make([]Viewer, 0, len(s.viewers[key]))
+	for viewer := range s.viewers[key] {
+		viewers = append(viewers, Viewer{ClientID: viewer.id, Actor: viewer.actor})
+	}
+	sort.Slice(viewers, func(i, j int) bool { return viewers[i].ClientID < viewers[j].ClientID })
+	return presenceMessage{Type: constants.WSPresence, ItemID: itemID, Viewers: viewers}
+}
+
+// broadcastPresence envía la presencia del item a los clientes del tenant que
+// lo ven o están suscriptos a él
+func (s *Server) broadcastPresence(tenantID string, itemID string) {
+	message := s.presence(tenantID, itemID)
+
+	s.mu.Lock()
+	targets := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		targets = append(targets, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range targets {
+		if c.tenantID == tenantID && c.watches(itemID) {
+			c.enqueue(message)
+		}
+	}
+}
+
+// mutate ejecuta la mutación con los handlers de la API REST y devuelve al
+// cliente la misma respuesta (código y cuerpo) que hubiera recibido por HTTP
+func (s *Server) mutate(c *client, message clientMessage) {
+	var method, path string
+	if message.Type != constants.WSCreate {
+		if _, err := strconv.Atoi(message.ItemID); err != nil {
+			c.enqueue(serverMessage{Type: constants.WSError, Ref: message.Ref, Error: constants.IDInvalido})
+			return
+		}
+	}
+	switch message.Type {
+	case constants.WSCreate:
+		method, path = http.MethodPost, "/items"
+	case constants.WSUpdate:
+		method, path = http.MethodPut, "/items/"+message.ItemID
+	case constants.WSDelete:
+		method, path = http.MethodDelete, "/items/"+message.ItemID
+	case constants.WSTransition:
+		method, path = http.MethodPost, "/items/"+message.ItemID+"/transitions"
+	}
+
+	req, err := http.NewRequest(method, path, bytes.NewReader(message.Data))
+	if err != nil {
+		c.enqueue(serverMessage{Type: constants.WSError, Ref: message.Ref, Error: constants.ErrorInterno})
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.actor != "" {
+		req.Header.Set(middleware.ActorHeader, c.actor)
+	}
+	// Add canoniza el nombre: X-API-Key se lee como X-Api-Key
+	for name, values := range c.credentials {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if message.Ref != "" {
+		req.Header.Set(middleware.RequestIDHeader, message.Ref)
+	}
+
+	response := &responseRecorder{header: make(http.Header)}
+	s.mutations.ServeHTTP(response, req)
+
+	body := response.body.Bytes()
+	if !json.Valid(body) {
+		body = nil
+	}
+	c.enqueue(serverMessage{Type: constants.WSResult, Ref: message.Ref, Status: response.status, Body: body})
+}
+
+// responseRecorder guarda la respuesta de una mutación para reenviarla por el WebSocket
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(data)
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+const apiKeyColumns = "id, tenant_id, name, prefix, key_hash, scopes, last_used_at, revoked_at, created_at, updated_at"
+
+type APIKeyMySqlRepository struct {
+	db *sql.DB
+	// tenantID limita las keys a un tenant, ver APIKeysForTenant
+	tenantID string
+}
+
+func NewAPIKeyMySqlRepository(db *sql.DB) *APIKeyMySqlRepository {
+	return &APIKeyMySqlRepository{db: db}
+}
+
+// APIKeysForTenant devuelve una copia del repositorio limitada a las keys del tenant
+func (r *APIKeyMySqlRepository) APIKeysForTenant(tenantID string) IAPIKeyRepository {
+	scoped := *r
+	scoped.tenantID = tenantID
+	return &scoped
+}
+
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var key models.APIKey
+	var scopes []byte
+	if err := row.Scan(&key.ID, &key.TenantID, &key.Name, &key.Prefix, &key.Hash, &scopes, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt, &key.UpdatedAt); err != nil {
+		return key, err
+	}
+	err := json.Unmarshal(scopes, &key.Scopes)
+	return key, err
+}
+
+func (r *APIKeyMySqlRepository) GetAllAPIKeys() ([]models.APIKey, error) {
+	rows, err := r.db.Query("SELECT "+apiKeyColumns+" FROM api_keys WHERE tenant_id = ? ORDER BY id", scopeTenant(r.tenantID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *APIKeyMySqlRepository) GetAPIKey(id int) (models.APIKey, error) {
+	return scanAPIKey(r.db.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE id = ? AND tenant_id = ?", id, scopeTenant(r.tenantID)))
+}
+
+// GetAPIKeyByHash busca en todos los tenants: la key identifica al suyo
+func (r *APIKeyMySqlRepository) GetAPIKeyByHash(hash string) (models.APIKey, error) {
+	return scanAPIKey(r.db.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = ?", hash))
+}
+
+func (r *APIKeyMySqlRepository) CreateAPIKey(key models.APIKey) (models.APIKey, error) {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	result, err := r.db.Exec("INSERT INTO api_keys (tenant_id, name, prefix, key_hash, scopes) VALUES (?, ?, ?, ?, ?)", scopeTenant(r.tenantID), key.Name, key.Prefix, key.Hash, scopes)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	return r.GetAPIKey(int(id))
+}
+
+// updateActiveKey aplica el UPDATE sobre una key no revocada y distingue si no
+// existe (sql.ErrNoRows) o si estaba revocada (ErrAPIKeyRevoked)
+func (r *APIKeyMySqlRepository) updateActiveKey(id int, query string, args ...any) (models.APIKey, error) {
+	result, err := r.db.Exec(query+" WHERE id = ? AND tenant_id = ? AND revoked_at IS NULL", append(args, id, scopeTenant(r.tenantID))...)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	key, err := r.GetAPIKey(id)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return models.APIKey{}, err
+	} else if affected == 0 {
+		return models.APIKey{}, ErrAPIKeyRevoked
+	}
+	return key, nil
+}
+
+func (r *APIKeyMySqlRepository) RevokeAPIKey(id int) (models.APIKey, error) {
+	return r.updateActiveKey(id, "UPDATE api_keys SET revoked_at = ?", time.Now().UTC())
+}
+
+func (r *APIKeyMySqlRepository) RotateAPIKey(id int, prefix string, hash string) (models.APIKey, error) {
+	return r.updateActiveKey(id, "UPDATE api_keys SET prefix = ?, key_hash = ?", prefix, hash)
+}
+
+func (r *APIKeyMySqlRepository) TouchAPIKey(id string, usedAt time.Time) error {
+	// No se toca updated_at: el último uso no es una modificación de la key
+	_, err := r.db.Exec("UPDATE api_keys SET last_used_at = ?, updated_at = updated_at WHERE id = ?", usedAt.UTC(), id)
+	return err
+}
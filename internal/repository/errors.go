@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+)
+
+// Errores que los handlers distinguen para responder con el código HTTP adecuado.
+// Si el recurso principal no existe se sigue reportando sql.ErrNoRows; los
+// ErrXNotFound son para entidades referenciadas (ej: el proyecto de un item).
+var (
+	ErrDuplicateTag    = errors.New(constants.EtiquetaDuplicada)
+	ErrProjectNotFound = errors.New(constants.ProyectoNoEncontrado)
+	ErrProjectArchived = errors.New(constants.ProyectoArchivadoErr)
+	ErrProjectNotEmpty = errors.New(constants.ProyectoConItems)
+	ErrParentNotFound  = errors.New(constants.PadreNoEncontrado)
+	ErrBlockerNotFound = errors.New(constants.BloqueanteNoEncontrado)
+	ErrItemNotDeleted  = errors.New(constants.ItemNoEliminado)
+	ErrAPIKeyRevoked   = errors.New(constants.APIKeyInactiva)
+	ErrDuplicateEmail  = errors.New(constants.EmailDuplicado)
+	ErrTokenReused     = errors.New(constants.RefreshTokenReutilizado)
+	// ErrItemForbidden es un cambio de un usuario sobre un item que no creó ni
+	// tiene asignado, ver policy.CanModify
+	ErrItemForbidden    = errors.New(constants.ItemDeOtroUsuario)
+	ErrAssigneeNotFound = errors.New(constants.ResponsableNoEncontrado)
+	// ErrPermissionDenied es un cambio que el rol del usuario no permite en el
+	// proyecto del item, ver policy.Can
+	ErrPermissionDenied = errors.New(constants.PermisoInsuficiente)
+	ErrUserNotFound     = errors.New(constants.UsuarioNoEncontrado)
+	ErrMemberNotFound   = errors.New(constants.MiembroNoEncontrado)
+	ErrTenantNotFound   = errors.New(constants.TenantNoEncontrado)
+	// ErrXQuotaExceeded indica que el tenant alcanzó su cuota, ver models.Tenant
+	ErrItemQuotaExceeded    = errors.New(constants.CuotaItemsExcedida)
+	ErrProjectQuotaExceeded = errors.New(constants.CuotaProyectosExcedida)
+	ErrShareRevoked         = errors.New(constants.CompartidoYaRevocado)
+	// ErrDependencyCycle es una dependencia cuyo bloqueante ya está bloqueado
+	// (directa o indirectamente) por el item
+	ErrDependencyCycle = errors.New(constants.CicloDependencias)
+	// ErrHierarchyCycle y ErrMaxDepth son un padre que es el mismo item o una de
+	// sus subtareas, y uno que supera la profundidad máxima (ver SetParent)
+	ErrHierarchyCycle = errors.New(constants.CicloJerarquia)
+	ErrMaxDepth       = errors.New(constants.ProfundidadMaxima)
+)
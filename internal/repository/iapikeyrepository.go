@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+type IAPIKeyRepository interface {
+	// GetAllAPIKeys devuelve todas las keys, también las revocadas, para auditarlas
+	GetAllAPIKeys() ([]models.APIKey, error)
+	GetAPIKey(id int) (models.APIKey, error)
+	// GetAPIKeyByHash busca la key por el hash de la key en claro; sql.ErrNoRows si no existe
+	GetAPIKeyByHash(hash string) (models.APIKey, error)
+	// CreateAPIKey guarda la key con su prefijo y su hash (nunca la key en claro)
+	CreateAPIKey(key models.APIKey) (models.APIKey, error)
+	// RevokeAPIKey revoca la key; ErrAPIKeyRevoked si ya estaba revocada
+	RevokeAPIKey(id int) (models.APIKey, error)
+	// RotateAPIKey reemplaza el prefijo y el hash de la key, que deja de aceptar la
+	// key anterior; ErrAPIKeyRevoked si estaba revocada
+	RotateAPIKey(id int, prefix string, hash string) (models.APIKey, error)
+	// TouchAPIKey registra el último uso de la key
+	TouchAPIKey(id string, usedAt time.Time) error
+	// APIKeysForTenant devuelve una vista limitada a las keys del tenant; sin
+	// vista se usa el tenant por defecto. GetAPIKeyByHash y TouchAPIKey, que
+	// usa la autenticación, no se limitan.
+	APIKeysForTenant(tenantID string) IAPIKeyRepository
+}
@@ -0,0 +1,27 @@
+package repository
+
+import "github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+
+type IProjectRepository interface {
+	GetAllProjects(includeArchived bool) ([]models.Project, error)
+	GetProject(id int) (models.Project, error)
+	// GetProjectsByIDs carga varios proyectos en una sola consulta; omite los que no existen
+	GetProjectsByIDs(ids []int) ([]models.Project, error)
+	CreateProject(project models.Project) (models.Project, error)
+	UpdateProject(project models.Project) (models.Project, error)
+	// DeleteProject devuelve ErrProjectNotEmpty si el proyecto aún tiene items
+	DeleteProject(id int) error
+	SetProjectArchived(id int, archived bool) (models.Project, error)
+
+	GetProjectMembers(projectID int) ([]models.ProjectMember, error)
+	// SetProjectMember agrega al usuario al proyecto o cambia su rol;
+	// ErrUserNotFound si el usuario no existe
+	SetProjectMember(member models.ProjectMember) (models.ProjectMember, error)
+	// RemoveProjectMember devuelve ErrMemberNotFound si el usuario no es miembro
+	RemoveProjectMember(projectID int, userID int) error
+
+	// ProjectsForTenant devuelve una vista limitada a los proyectos del tenant;
+	// sin vista se usa el tenant por defecto. Los miembros se agregan solo entre
+	// los usuarios del tenant (ErrUserNotFound para los demás).
+	ProjectsForTenant(tenantID string) IProjectRepository
+}
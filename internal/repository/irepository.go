@@ -1,12 +1,47 @@
 package repository
 
-import "github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+import (
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
 
 type IRepository interface {
 	Get(id int) (models.TodoItem, error)
-	GetAll() (	[]models.TodoItem, error)
+	GetAll(filter models.ItemFilter) (	[]models.TodoItem, error)
 	Create(item models.TodoItem)(models.TodoItem, error)
 	Update(item models.TodoItem) error
 	Delete(id string) error
-
+	// MoveItem cambia el proyecto de un item; projectID nil lo deja sin proyecto
+	MoveItem(id int, projectID *string) error
+	// GetSubtree devuelve todos los descendientes (no eliminados) de un item, sin incluirlo
+	GetSubtree(id int) ([]models.TodoItem, error)
+	// SetParent cambia el item padre; parentID nil lo convierte en item raíz.
+	// ErrHierarchyCycle si el padre es el item o una de sus subtareas, y
+	// ErrMaxDepth si el árbol supera maxDepth niveles (0 sin límite).
+	SetParent(id int, parentID *string, maxDepth int) error
+	// AssignItem cambia el responsable de un item; assigneeID nil lo deja sin
+	// responsable. ErrAssigneeNotFound si el usuario no existe.
+	AssignItem(id int, assigneeID *string) error
+	// AddDependency registra que id está bloqueado por blockerID (idempotente).
+	// ErrDependencyCycle si el bloqueante ya depende del item.
+	AddDependency(id int, blockerID int) error
+	RemoveDependency(id int, blockerID int) error
+	// GetBlockers devuelve los bloqueantes (no eliminados) directos o, con transitive, todos
+	GetBlockers(id int, transitive bool) ([]models.TodoItem, error)
+	// Restore deshace la eliminación de un item; ErrItemNotDeleted si no estaba eliminado
+	Restore(id int) error
+	// GetHistory devuelve una página de eventos del item (incluso si fue eliminado),
+	// del más reciente al más antiguo, junto con el total de eventos
+	GetHistory(id int, page int, pageSize int) ([]models.ItemEvent, int, error)
+	// GetAsOf devuelve el item como estaba en asOf; sql.ErrNoRows si todavía no
+	// existía o estaba eliminado. Los campos calculados no se reconstruyen.
+	GetAsOf(id int, asOf time.Time) (models.TodoItem, error)
+	// WithMeta devuelve una vista del repositorio que registra los cambios en el
+	// historial a nombre de meta. Los handlers la piden en cada petición. Los
+	// cambios de la vista sobre items que meta no puede modificar (ver
+	// policy.CanModify) devuelven ErrItemForbidden, y los que su rol no
+	// permite en el proyecto del item (ver policy.Can), ErrPermissionDenied.
+	// Eliminar y restaurar requieren el permiso delete.
+	WithMeta(meta models.RequestMeta) IRepository
 }
\ No newline at end of file
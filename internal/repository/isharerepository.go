@@ -0,0 +1,23 @@
+package repository
+
+import "github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+
+type IShareRepository interface {
+	// GetItemShares y GetProjectShares devuelven los compartidos del item o del
+	// proyecto, también los revocados y vencidos, para auditarlos
+	GetItemShares(itemID int) ([]models.Share, error)
+	GetProjectShares(projectID int) ([]models.Share, error)
+	GetShare(id int) (models.Share, error)
+	// GetShareByHash busca el enlace por el hash de su token; sql.ErrNoRows si no existe
+	GetShareByHash(hash string) (models.Share, error)
+	// CreateShare guarda el compartido con el hash del token (nunca el token en
+	// claro); ErrUserNotFound si el usuario destinatario no es del tenant. El
+	// item o el proyecto compartido se verifica en el handler.
+	CreateShare(share models.Share) (models.Share, error)
+	// RevokeShare revoca el compartido; ErrShareRevoked si ya estaba revocado
+	RevokeShare(id int) (models.Share, error)
+	// SharesForTenant devuelve una vista limitada a los compartidos del tenant;
+	// sin vista se usa el tenant por defecto. GetShareByHash, que usan los
+	// enlaces, no se limita.
+	SharesForTenant(tenantID string) IShareRepository
+}
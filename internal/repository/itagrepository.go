@@ -0,0 +1,15 @@
+package repository
+
+import "github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+
+type ITagRepository interface {
+	GetAllTags() ([]models.Tag, error)
+	CreateTag(name string) (models.Tag, error)
+	RenameTag(id int, name string) (models.Tag, error)
+	// MergeTags reasigna los items de sourceID a targetID y elimina sourceID
+	MergeTags(sourceID int, targetID int) (models.Tag, error)
+	DeleteTag(id int) error
+	// TagsForTenant devuelve una vista limitada a las etiquetas del tenant; sin
+	// vista se usa el tenant por defecto
+	TagsForTenant(tenantID string) ITagRepository
+}
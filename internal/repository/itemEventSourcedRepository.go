@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+// ItemEventSourcedRepository es la implementación de IRepository en la que los
+// items se derivan de un flujo append-only de eventos (item_stream_events).
+// Cada cambio agrega sus eventos y, en la misma transacción, vuelve a escribir
+// la proyección en todo_items desde el flujo con el mismo proyector que
+// RebuildProjections (ver project), por lo que las lecturas se hacen sobre la
+// proyección igual que en ItemMySqlRepository.
+type ItemEventSourcedRepository struct {
+	*ItemMySqlRepository
+	// snapshotEvery es cada cuántos eventos de un item se guarda un snapshot
+	snapshotEvery int
+}
+
+func NewItemEventSourcedRepository(db *sql.DB, snapshotEvery int) *ItemEventSourcedRepository {
+	r := &ItemEventSourcedRepository{
+		ItemMySqlRepository: NewItemMySqlRepository(db),
+		snapshotEvery:       snapshotEvery,
+	}
+	r.ItemMySqlRepository.onEvent = r.appendEvents
+	return r
+}
+
+// WithMeta devuelve una copia del repositorio que registra los cambios a nombre de meta
+func (r *ItemEventSourcedRepository) WithMeta(meta models.RequestMeta) IRepository {
+	return &ItemEventSourcedRepository{
+		ItemMySqlRepository: r.ItemMySqlRepository.WithMeta(meta).(*ItemMySqlRepository),
+		snapshotEvery:       r.snapshotEvery,
+	}
+}
+
+// queryer abstrae *sql.DB y *sql.Tx para las lecturas del flujo
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// appendEvents traduce un evento del historial a eventos del flujo, los agrega
+// a continuación de la última versión del item y proyecta el resultado
+func (r *ItemEventSourcedRepository) appendEvents(tx *sql.Tx, event models.ItemEvent) error {
+	var version int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM item_stream_events WHERE item_id = ?", event.ItemID).Scan(&version); err != nil {
+		return err
+	}
+
+	for _, streamEvent := range models.StreamEventsFor(event.Type, event.Changes) {
+		version++
+		data, err := json.Marshal(streamEvent.Data)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("INSERT INTO item_stream_events (tenant_id, item_id, version, event_type, data, actor, request_id) VALUES (?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''))", r.meta.Tenant(), event.ItemID, version, streamEvent.Type, data, event.Actor, event.RequestID); err != nil {
+			return err
+		}
+		if version%r.snapshotEvery == 0 {
+			if err := saveSnapshot(tx, r.meta.Tenant(), event.ItemID); err != nil {
+				return err
+			}
+		}
+	}
+	// La fila que escribió ItemMySqlRepository se reemplaza por la que resulta
+	// del flujo, para que nunca difiera de la que daría RebuildProjections
+	return project(tx, r.meta.Tenant(), event.ItemID)
+}
+
+// project escribe en todo_items el estado del item según su flujo. Es el único
+// camino del flujo a la proyección, tanto en cada cambio como en RebuildProjections.
+func project(tx *sql.Tx, tenantID string, itemID string) error {
+	item, _, _, err := loadStream(tx, itemID, nil)
+	if err != nil {
+		return err
+	}
+	// Items creados antes de activar este modo: su flujo no tiene ItemCreated
+	// y se conserva la fila actual
+	if item.CreatedAt == "" {
+		return nil
+	}
+	return writeProjection(tx, tenantID, item)
+}
+
+// projectItem aplica en orden los eventos del flujo sobre item, vacío o el
+// estado de un snapshot. Los campos calculados no forman parte del estado del flujo.
+func projectItem(item models.TodoItem, events []models.ItemStreamEvent) models.TodoItem {
+	for _, event := range events {
+		item.ApplyEvent(event.HistoryType(), event.Data, event.OccurredAt)
+	}
+	item.BlockedBy = []string{}
+	item.Overdue = false
+	item.Progress = nil
+	item.AllowedTransitions = nil
+	return item
+}
+
+// saveSnapshot guarda el estado actual del item según el flujo
+func saveSnapshot(tx *sql.Tx, tenantID string, itemID string) error {
+	item, version, occurredAt, err := loadStream(tx, itemID, nil)
+	if err != nil {
+		return err
+	}
+	state, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT IGNORE INTO item_snapshots (tenant_id, item_id, version, state, occurred_at) VALUES (?, ?, ?, ?, ?)", tenantID, itemID, version, state, occurredAt)
+	return err
+}
+
+// loadStream reconstruye un item desde su último snapshot y los eventos
+// posteriores, considerando solo lo ocurrido hasta asOf si no es nil. Devuelve
+// también la versión y la fecha del último evento aplicado.
+func loadStream(q queryer, itemID string, asOf *time.Time) (models.TodoItem, int, time.Time, error) {
+	item := models.TodoItem{ID: itemID}
+	var version int
+	var occurredAt time.Time
+
+	snapshotQuery := "SELECT version, state, occurred_at FROM item_snapshots WHERE item_id = ?"
+	eventsQuery := "SELECT version, event_type, data, occurred_at FROM item_stream_events WHERE item_id = ? AND version > ?"
+	snapshotArgs := []any{itemID}
+	if asOf != nil {
+		snapshotQuery += " AND occurred_at <= ?"
+		eventsQuery += " AND occurred_at <= ?"
+		snapshotArgs = append(snapshotArgs, asOf.UTC())
+	}
+	snapshotQuery += " ORDER BY version DESC LIMIT 1"
+	eventsQuery += " ORDER BY version"
+
+	var state []byte
+	err := q.QueryRow(snapshotQuery, snapshotArgs...).Scan(&version, &state, &occurredAt)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return models.TodoItem{}, 0, time.Time{}, err
+	default:
+		if err := json.Unmarshal(state, &item); err != nil {
+			return models.TodoItem{}, 0, time.Time{}, err
+		}
+	}
+
+	eventsArgs := []any{itemID, version}
+	if asOf != nil {
+		eventsArgs = append(eventsArgs, asOf.UTC())
+	}
+	rows, err := q.Query(eventsQuery, eventsArgs...)
+	if err != nil {
+		return models.TodoItem{}, 0, time.Time{}, err
+	}
+	defer rows.Close()
+
+	var events []models.ItemStreamEvent
+	for rows.Next() {
+		event := models.ItemStreamEvent{ItemID: itemID}
+		var data []byte
+		if err := rows.Scan(&event.Version, &event.Type, &data, &occurredAt); err != nil {
+			return models.TodoItem{}, 0, time.Time{}, err
+		}
+		if err := json.Unmarshal(data, &event.Data); err != nil {
+			return models.TodoItem{}, 0, time.Time{}, err
+		}
+		event.OccurredAt = occurredAt.UTC().Format(time.RFC3339Nano)
+		events = append(events, event)
+		version = event.Version
+	}
+	if err := rows.Err(); err != nil {
+		return models.TodoItem{}, 0, time.Time{}, err
+	}
+
+	item = projectItem(item, events)
+	item.ID = itemID
+	return item, version, occurredAt, nil
+}
+
+// GetAsOf reconstruye el item como estaba en asOf desde el flujo de eventos
+func (r *ItemEventSourcedRepository) GetAsOf(id int, asOf time.Time) (models.TodoItem, error) {
+	// Los flujos se leen por item; el de otro tenant es como si no existiera
+	var exists int
+	if err := r.db.QueryRow("SELECT 1 FROM item_stream_events WHERE item_id = ? AND tenant_id = ? LIMIT 1", id, r.meta.Tenant()).Scan(&exists); err != nil {
+		return models.TodoItem{}, err
+	}
+
+	item, _, _, err := loadStream(r.db, fmt.Sprintf("%d", id), &asOf)
+	if err != nil {
+		return models.TodoItem{}, err
+	}
+	// Sin ItemCreated hasta asOf el item todavía no existía
+	if item.CreatedAt == "" || item.DeletedAt != nil {
+		return models.TodoItem{}, sql.ErrNoRows
+	}
+	return item, nil
+}
+
+// RebuildProjections vuelve a escribir en todo_items (y sus etiquetas) el estado
+// que resulta del flujo de cada item, de todos los tenants. Los cambios hechos por fuera del flujo,
+// como renombrar o fusionar etiquetas, se pierden al reconstruir.
+func (r *ItemEventSourcedRepository) RebuildProjections() error {
+	// FOREIGN_KEY_CHECKS es de la sesión, así que usamos una conexión dedicada y
+	// la restauramos antes de devolverla al pool
+	ctx := context.Background()
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Los items se escriben en orden de ID, así que un padre puede llegar después que su subtarea
+	if _, err := conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 0"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 1")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT DISTINCT item_id, tenant_id FROM item_stream_events ORDER BY item_id")
+	if err != nil {
+		return err
+	}
+	var ids []string
+	tenants := map[string]string{}
+	for rows.Next() {
+		var id, tenantID string
+		if err := rows.Scan(&id, &tenantID); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+		tenants[id] = tenantID
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := project(tx, tenants[id], id); err != nil {
+			return fmt.Errorf("item %s: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// writeProjection inserta o reemplaza la fila del item en todo_items
+func writeProjection(tx *sql.Tx, tenantID string, item models.TodoItem) error {
+	values := []any{tenantID, item.ID, item.ProjectID, item.ParentID, item.Title, item.Description, item.State, item.Priority}
+	for _, value := range []*string{item.StartAt, item.DueAt, &item.CreatedAt, &item.UpdatedAt, item.DeletedAt} {
+		t, err := nullableTime(value)
+		if err != nil {
+			return err
+		}
+		values = append(values, t)
+	}
+
+	values = append(values, item.CreatedBy, item.AssigneeID)
+
+	_, err := tx.Exec("INSERT INTO todo_items (tenant_id, "+itemColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE project_id = VALUES(project_id), parent_id = VALUES(parent_id), title = VALUES(title), description = VALUES(description), "+
+		"state = VALUES(state), priority = VALUES(priority), start_at = VALUES(start_at), due_at = VALUES(due_at), "+
+		"created_at = VALUES(created_at), updated_at = VALUES(updated_at), deleted_at = VALUES(deleted_at), "+
+		"created_by = VALUES(created_by), assignee_id = VALUES(assignee_id)", values...)
+	if err != nil {
+		return err
+	}
+	return setItemTags(tx, tenantID, item.ID, item.Tags)
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/policy"
+)
+
+// WithMeta devuelve una copia del repositorio que registra los cambios a nombre
+// de meta y se limita a los items de meta.TenantID
+func (r *ItemMySqlRepository) WithMeta(meta models.RequestMeta) IRepository {
+	scoped := *r
+	scoped.meta = meta
+	return &scoped
+}
+
+// lockItem lee un item no eliminado del tenant, con sus etiquetas, bloqueando la fila hasta el fin de la transacción
+func lockItem(tx *sql.Tx, tenantID string, id any) (models.TodoItem, error) {
+	item, err := scanItem(tx.QueryRow("SELECT "+itemColumns+" FROM todo_items WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL FOR UPDATE", id, tenantID))
+	if err != nil {
+		return models.TodoItem{}, err
+	}
+
+	rows, err := tx.Query("SELECT t.name FROM todo_item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id = ? ORDER BY t.name", item.ID)
+	if err != nil {
+		return models.TodoItem{}, err
+	}
+	defer rows.Close()
+
+	item.Tags = []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return models.TodoItem{}, err
+		}
+		item.Tags = append(item.Tags, name)
+	}
+	return item, rows.Err()
+}
+
+// lockModifiable es lockItem para los cambios: devuelve el error de
+// authorizeChange si el usuario de r.meta no puede modificar el item
+func (r *ItemMySqlRepository) lockModifiable(tx *sql.Tx, id any) (models.TodoItem, error) {
+	item, err := lockItem(tx, r.meta.Tenant(), id)
+	if err != nil {
+		return models.TodoItem{}, err
+	}
+	if err := authorizeChange(r.meta, constants.PermissionWrite, item); err != nil {
+		return models.TodoItem{}, err
+	}
+	return item, nil
+}
+
+// authorizeChange verifica que meta pueda hacer un cambio que requiere permission
+// sobre el item: ErrPermissionDenied si su rol no lo permite en el proyecto del
+// item y ErrItemForbidden si no puede modificar ese item en particular
+func authorizeChange(meta models.RequestMeta, permission string, item models.TodoItem) error {
+	// Un compartido edit permite modificar el item aunque el rol no lo permita
+	// en su proyecto, pero nunca eliminarlo
+	if permission == constants.PermissionWrite && policy.SharedPermission(meta, item) == constants.SharePermissionEdit {
+		return nil
+	}
+	if !policy.Can(meta, permission, item.ProjectID) {
+		return ErrPermissionDenied
+	}
+	if !policy.CanModify(meta, item) {
+		return ErrItemForbidden
+	}
+	return nil
+}
+
+// recordEvent guarda un evento del historial dentro de la misma transacción del
+// cambio, junto con los eventos de webhook que genera (ver writeOutbox)
+func (r *ItemMySqlRepository) recordEvent(tx *sql.Tx, itemID string, eventType string, changes map[string]models.FieldChange) error {
+	event := models.ItemEvent{
+		ItemID:    itemID,
+		Type:      eventType,
+		Actor:     r.meta.Actor,
+		RequestID: r.meta.RequestID,
+		Changes:   changes,
+	}
+
+	payload, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO item_events (tenant_id, item_id, event_type, actor, request_id, changes) VALUES (?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?)", r.meta.Tenant(), itemID, eventType, r.meta.Actor, r.meta.RequestID, payload); err != nil {
+		return err
+	}
+	if err := writeOutbox(tx, r.meta.Tenant(), event); err != nil {
+		return err
+	}
+
+	if r.onEvent == nil {
+		return nil
+	}
+	return r.onEvent(tx, event)
+}
+
+// recordChanges registra un evento de actualización si algún campo cambió
+func (r *ItemMySqlRepository) recordChanges(tx *sql.Tx, old models.TodoItem, new models.TodoItem) error {
+	changes := models.DiffItems(&old, new)
+	if len(changes) == 0 {
+		return nil
+	}
+	return r.recordEvent(tx, new.ID, constants.EventUpdated, changes)
+}
+
+func (r *ItemMySqlRepository) Restore(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	item, err := scanItem(tx.QueryRow("SELECT "+itemColumns+" FROM todo_items WHERE id = ? AND tenant_id = ? FOR UPDATE", id, r.meta.Tenant()))
+	if err != nil {
+		return err
+	}
+	if item.DeletedAt == nil {
+		return ErrItemNotDeleted
+	}
+	// El item restaurado vuelve a contar en la cuota
+	if err := checkItemQuota(tx, r.meta.Tenant()); err != nil {
+		return err
+	}
+	// Restaurar deshace una eliminación y requiere el mismo permiso
+	if err := authorizeChange(r.meta, constants.PermissionDelete, item); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE todo_items SET deleted_at = NULL WHERE id = ?", id); err != nil {
+		return err
+	}
+	if err := r.recordEvent(tx, fmt.Sprintf("%d", id), constants.EventRestored, map[string]models.FieldChange{}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ItemMySqlRepository) GetHistory(id int, page int, pageSize int) ([]models.ItemEvent, int, error) {
+	// El historial sigue disponible para items eliminados
+	var exists int
+	if err := r.db.QueryRow("SELECT 1 FROM todo_items WHERE id = ? AND tenant_id = ?", id, r.meta.Tenant()).Scan(&exists); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM item_events WHERE item_id = ?", id).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query("SELECT id, item_id, event_type, COALESCE(actor, ''), COALESCE(request_id, ''), changes, created_at FROM item_events WHERE item_id = ? ORDER BY id DESC LIMIT ? OFFSET ?", id, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	events := []models.ItemEvent{}
+	for rows.Next() {
+		var event models.ItemEvent
+		var changes []byte
+		if err := rows.Scan(&event.ID, &event.ItemID, &event.Type, &event.Actor, &event.RequestID, &changes, &event.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		if err := json.Unmarshal(changes, &event.Changes); err != nil {
+			return nil, 0, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// GetAsOf reconstruye el item como estaba en asOf aplicando su historial. Los
+// items creados antes de que existiera el historial no se pueden reconstruir.
+func (r *ItemMySqlRepository) GetAsOf(id int, asOf time.Time) (models.TodoItem, error) {
+	rows, err := r.db.Query("SELECT event_type, changes, created_at FROM item_events WHERE item_id = ? AND tenant_id = ? AND created_at <= ? ORDER BY id", id, r.meta.Tenant(), asOf.UTC())
+	if err != nil {
+		return models.TodoItem{}, err
+	}
+	defer rows.Close()
+
+	item := models.TodoItem{ID: fmt.Sprintf("%d", id)}
+	created := false
+	for rows.Next() {
+		var eventType, createdAt string
+		var payload []byte
+		if err := rows.Scan(&eventType, &payload, &createdAt); err != nil {
+			return models.TodoItem{}, err
+		}
+		var changes map[string]models.FieldChange
+		if err := json.Unmarshal(payload, &changes); err != nil {
+			return models.TodoItem{}, err
+		}
+		created = created || eventType == constants.EventCreated
+		item.ApplyEvent(eventType, models.ChangeValues(changes), createdAt)
+	}
+	if err := rows.Err(); err != nil {
+		return models.TodoItem{}, err
+	}
+
+	if !created || item.DeletedAt != nil {
+		return models.TodoItem{}, sql.ErrNoRows
+	}
+	item.BlockedBy = []string{}
+	return item, nil
+}
@@ -5,14 +5,25 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/policy"
 	"github.com/go-sql-driver/mysql"
 )
 
+// itemColumns son las columnas que se leen de todo_items, en el orden que espera scanItem
+const itemColumns = "id, project_id, parent_id, title, description, state, priority, start_at, due_at, created_at, updated_at, deleted_at, created_by, assignee_id"
+
 type ItemMySqlRepository struct {
 	db *sql.DB
+	// meta identifica al autor de los cambios en el historial y el tenant al
+	// que se limitan todas las consultas, ver WithMeta
+	meta models.RequestMeta
+	// onEvent, si está definido, recibe cada evento del historial dentro de la
+	// misma transacción (lo usa ItemEventSourcedRepository)
+	onEvent func(tx *sql.Tx, event models.ItemEvent) error
 }
 
 func NewItemMySqlRepository(db *sql.DB) *ItemMySqlRepository {
@@ -31,14 +42,127 @@ func handleMySQLError(err error) error {
 			if strings.Contains(mysqlErr.Message, "state") {
 				return errors.New(constants.EstadoInvalido)
 			}
+			if strings.Contains(mysqlErr.Message, "priority") {
+				return errors.New(constants.PrioridadInvalida)
+			}
 		}
 	}
 
 	return err
 }
 
-func (r *ItemMySqlRepository) GetAll() ([]models.TodoItem, error) {
-	rows, err := r.db.Query("SELECT id, title, description, state, created_at, updated_at, deleted_at FROM todo_items WHERE deleted_at IS NULL")
+// rowScanner abstrae *sql.Row y *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanItem(row rowScanner) (models.TodoItem, error) {
+	var item models.TodoItem
+	err := row.Scan(&item.ID, &item.ProjectID, &item.ParentID, &item.Title, &item.Description, &item.State, &item.Priority, &item.StartAt, &item.DueAt, &item.CreatedAt, &item.UpdatedAt, &item.DeletedAt, &item.CreatedBy, &item.AssigneeID)
+	return item, err
+}
+
+// nullableTime convierte una fecha opcional en RFC3339 al valor que espera el driver
+func nullableTime(value *string) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := models.ParseTimestamp(*value)
+	if err != nil {
+		return nil, err
+	}
+	return t.UTC(), nil
+}
+
+// orderByClause traduce el ordenamiento del filtro a SQL. SortBy ya fue validado
+// contra models.ValidSortFields, que coinciden con nombres de columnas.
+// Las prioridades son un ENUM, por lo que MySQL las ordena por su posición (low < urgent).
+func orderByClause(filter models.ItemFilter) string {
+	column := models.SortByID
+	if filter.SortBy != "" && models.IsValidSortField(filter.SortBy) {
+		column = filter.SortBy
+	}
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id ASC", column, direction)
+}
+
+func (r *ItemMySqlRepository) GetAll(filter models.ItemFilter) ([]models.TodoItem, error) {
+	query := "SELECT " + itemColumns + " FROM todo_items WHERE tenant_id = ? AND deleted_at IS NULL"
+	args := []any{r.meta.Tenant()}
+
+	if filter.ProjectID != nil {
+		query += " AND project_id = ?"
+		args = append(args, *filter.ProjectID)
+	}
+	if filter.ParentID != nil {
+		query += " AND parent_id = ?"
+		args = append(args, *filter.ParentID)
+	}
+	if len(filter.IDs) > 0 {
+		query += " AND id IN (?" + strings.Repeat(", ?", len(filter.IDs)-1) + ")"
+		for _, id := range filter.IDs {
+			args = append(args, id)
+		}
+	}
+	if len(filter.ParentIDs) > 0 {
+		query += " AND parent_id IN (?" + strings.Repeat(", ?", len(filter.ParentIDs)-1) + ")"
+		for _, parentID := range filter.ParentIDs {
+			args = append(args, parentID)
+		}
+	}
+	if filter.Overdue != nil {
+		if *filter.Overdue {
+			query += " AND due_at IS NOT NULL AND due_at < ? AND state <> ?"
+		} else {
+			query += " AND (due_at IS NULL OR due_at >= ? OR state = ?)"
+		}
+		args = append(args, time.Now().UTC(), constants.StateCompleted)
+	}
+	if filter.DueBefore != nil {
+		query += " AND due_at IS NOT NULL AND due_at < ?"
+		args = append(args, filter.DueBefore.UTC())
+	}
+	if filter.AssigneeID != nil {
+		query += " AND assignee_id = ?"
+		args = append(args, *filter.AssigneeID)
+	}
+	if filter.Unassigned {
+		query += " AND assignee_id IS NULL"
+	}
+	if filter.CreatedBy != nil {
+		query += " AND created_by = ?"
+		args = append(args, *filter.CreatedBy)
+	}
+	if filter.InvolvedUserID != nil {
+		query += " AND (created_by = ? OR assignee_id = ?)"
+		args = append(args, *filter.InvolvedUserID, *filter.InvolvedUserID)
+	}
+	if len(filter.Priorities) > 0 {
+		query += " AND priority IN (?" + strings.Repeat(", ?", len(filter.Priorities)-1) + ")"
+		for _, priority := range filter.Priorities {
+			args = append(args, priority)
+		}
+	}
+
+	if len(filter.Tags) > 0 {
+		placeholders := "?" + strings.Repeat(", ?", len(filter.Tags)-1)
+		subquery := "SELECT it.item_id FROM todo_item_tags it JOIN tags t ON t.id = it.tag_id WHERE t.name IN (" + placeholders + ")"
+		for _, tag := range filter.Tags {
+			args = append(args, tag)
+		}
+		if filter.TagMatchAll {
+			subquery += " GROUP BY it.item_id HAVING COUNT(DISTINCT t.id) = ?"
+			args = append(args, len(filter.Tags))
+		}
+		query += " AND id IN (" + subquery + ")"
+	}
+
+	query += orderByClause(filter)
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -46,26 +170,75 @@ func (r *ItemMySqlRepository) GetAll() ([]models.TodoItem, error) {
 
 	var items []models.TodoItem
 	for rows.Next() {
-		var item models.TodoItem
-		if err := rows.Scan(&item.ID, &item.Title, &item.Description, &item.State, &item.CreatedAt, &item.UpdatedAt, &item.DeletedAt); err != nil {
+		item, err := scanItem(rows)
+		if err != nil {
 			return nil, err
 		}
 		items = append(items, item)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadDetails(items); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
 
 func (r *ItemMySqlRepository) Get(id int) (models.TodoItem, error) {
-	var item models.TodoItem
-	err := r.db.QueryRow("SELECT id, title, description, state, created_at, updated_at, deleted_at FROM todo_items WHERE id = ? AND deleted_at IS NULL", id).Scan(&item.ID, &item.Title, &item.Description, &item.State, &item.CreatedAt, &item.UpdatedAt, &item.DeletedAt)
+	item, err := scanItem(r.db.QueryRow("SELECT "+itemColumns+" FROM todo_items WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL", id, r.meta.Tenant()))
 	if err != nil {
 		return models.TodoItem{}, err
 	}
-	return item, nil
+
+	items := []models.TodoItem{item}
+	if err := r.loadDetails(items); err != nil {
+		return models.TodoItem{}, err
+	}
+	return items[0], nil
 }
 
 func (r *ItemMySqlRepository) Create(item models.TodoItem) (models.TodoItem, error) {
-	result, err := r.db.Exec("INSERT INTO todo_items (title, description, state) VALUES (?, ?, ?)", item.Title, item.Description, item.State)
+	if !policy.Can(r.meta, constants.PermissionWrite, item.ProjectID) {
+		return models.TodoItem{}, ErrPermissionDenied
+	}
+	startAt, err := nullableTime(item.StartAt)
+	if err != nil {
+		return models.TodoItem{}, err
+	}
+	dueAt, err := nullableTime(item.DueAt)
+	if err != nil {
+		return models.TodoItem{}, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.TodoItem{}, err
+	}
+	defer tx.Rollback()
+
+	tenantID := r.meta.Tenant()
+	if err := checkItemQuota(tx, tenantID); err != nil {
+		return models.TodoItem{}, err
+	}
+	if item.ProjectID != nil {
+		if err := checkProjectWritable(tx, tenantID, *item.ProjectID); err != nil {
+			return models.TodoItem{}, err
+		}
+	}
+	if item.ParentID != nil {
+		if err := checkParentExists(tx, tenantID, *item.ParentID); err != nil {
+			return models.TodoItem{}, err
+		}
+	}
+	if item.AssigneeID != nil {
+		if err := checkAssigneeExists(tx, tenantID, *item.AssigneeID); err != nil {
+			return models.TodoItem{}, err
+		}
+	}
+
+	result, err := tx.Exec("INSERT INTO todo_items (tenant_id, project_id, parent_id, title, description, state, priority, start_at, due_at, created_by, assignee_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", tenantID, item.ProjectID, item.ParentID, item.Title, item.Description, item.State, item.Priority, startAt, dueAt, item.CreatedBy, item.AssigneeID)
 	if err != nil {
 		return models.TodoItem{}, handleMySQLError(err)
 	}
@@ -74,15 +247,597 @@ func (r *ItemMySqlRepository) Create(item models.TodoItem) (models.TodoItem, err
 		return models.TodoItem{}, err
 	}
 	item.ID = fmt.Sprintf("%d", id)
+
+	if item.Tags == nil {
+		item.Tags = []string{}
+	}
+	item.BlockedBy = []string{}
+	item.Progress = nil
+	if err := setItemTags(tx, tenantID, item.ID, item.Tags); err != nil {
+		return models.TodoItem{}, err
+	}
+	if err := r.recordEvent(tx, item.ID, constants.EventCreated, models.DiffItems(nil, item)); err != nil {
+		return models.TodoItem{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.TodoItem{}, err
+	}
 	return item, nil
 }
 
 func (r *ItemMySqlRepository) Update(item models.TodoItem) error {
-	_, err := r.db.Exec("UPDATE todo_items SET title = ?, description = ?, state = ? WHERE id = ?", item.Title, item.Description, item.State, item.ID)
-	return handleMySQLError(err)
+	startAt, err := nullableTime(item.StartAt)
+	if err != nil {
+		return err
+	}
+	dueAt, err := nullableTime(item.DueAt)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := r.lockModifiable(tx, item.ID)
+	if err != nil {
+		return err
+	}
+
+	// Una prioridad vacía conserva la actual. El proyecto, el padre, el creador y el
+	// responsable no se modifican aquí, ver MoveItem, SetParent y AssignItem.
+	_, err = tx.Exec("UPDATE todo_items SET title = ?, description = ?, state = ?, priority = COALESCE(NULLIF(?, ''), priority), start_at = ?, due_at = ? WHERE id = ?", item.Title, item.Description, item.State, item.Priority, startAt, dueAt, item.ID)
+	if err != nil {
+		return handleMySQLError(err)
+	}
+
+	// Tags nil conserva las etiquetas actuales; un slice vacío las quita todas
+	if item.Tags != nil {
+		if err := setItemTags(tx, r.meta.Tenant(), item.ID, item.Tags); err != nil {
+			return err
+		}
+	} else {
+		item.Tags = current.Tags
+	}
+
+	if item.Priority == "" {
+		item.Priority = current.Priority
+	}
+	item.ProjectID = current.ProjectID
+	item.ParentID = current.ParentID
+	item.CreatedBy = current.CreatedBy
+	item.AssigneeID = current.AssigneeID
+	if err := r.recordChanges(tx, current, item); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *ItemMySqlRepository) Delete(id string) error {
-	_, err := r.db.Exec("UPDATE todo_items SET deleted_at = NOW() WHERE id = ?", id)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	item, err := r.lockModifiable(tx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeChange(r.meta, constants.PermissionDelete, item); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE todo_items SET deleted_at = NOW() WHERE id = ?", id); err != nil {
+		return err
+	}
+	if err := r.recordEvent(tx, id, constants.EventDeleted, map[string]models.FieldChange{}); err != nil {
+		return err
+	}
+
+	// Las subtareas de un item eliminado pasan a ser items raíz
+	children, err := childIDs(tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE todo_items SET parent_id = NULL WHERE parent_id = ?", id); err != nil {
+		return err
+	}
+	for _, childID := range children {
+		changes := map[string]models.FieldChange{"parent_id": {Old: id, New: nil}}
+		if err := r.recordEvent(tx, childID, constants.EventUpdated, changes); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *ItemMySqlRepository) GetSubtree(id int) ([]models.TodoItem, error) {
+	var exists int
+	if err := r.db.QueryRow("SELECT 1 FROM todo_items WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL", id, r.meta.Tenant()).Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`WITH RECURSIVE subtree AS (
+			SELECT id FROM todo_items WHERE parent_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id FROM todo_items t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL
+		)
+		SELECT `+itemColumns+` FROM todo_items WHERE id IN (SELECT id FROM subtree) ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.TodoItem{}
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadDetails(items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *ItemMySqlRepository) SetParent(id int, parentID *string, maxDepth int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockGraph(tx, r.meta.Tenant()); err != nil {
+		return err
+	}
+	current, err := r.lockModifiable(tx, id)
+	if err != nil {
+		return err
+	}
+	if parentID != nil {
+		if err := checkParentExists(tx, r.meta.Tenant(), *parentID); err != nil {
+			return err
+		}
+		// Con la jerarquía del tenant bloqueada, nadie más mueve items mientras se verifica
+		if err := checkHierarchy(tx, r.meta.Tenant(), current.ID, *parentID, maxDepth); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE todo_items SET parent_id = ? WHERE id = ?", parentID, id); err != nil {
+		return err
+	}
+	updated := current
+	updated.ParentID = parentID
+	if err := r.recordChanges(tx, current, updated); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ItemMySqlRepository) AddDependency(id int, blockerID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockGraph(tx, r.meta.Tenant()); err != nil {
+		return err
+	}
+	if _, err := r.lockModifiable(tx, id); err != nil {
+		return err
+	}
+	var exists int
+	err = tx.QueryRow("SELECT 1 FROM todo_items WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL FOR UPDATE", blockerID, r.meta.Tenant()).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrBlockerNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// Con el grafo del tenant bloqueado ninguna otra dependencia cambia mientras
+	// se recorre; hay ciclo si el item ya bloquea (directa o indirectamente) al
+	// nuevo bloqueante
+	if blockerID == id {
+		return ErrDependencyCycle
+	}
+	var cycle int
+	err = tx.QueryRow(`WITH RECURSIVE blockers AS (
+			SELECT blocker_id AS id FROM item_dependencies WHERE item_id = ?
+			UNION
+			SELECT d.blocker_id FROM item_dependencies d JOIN blockers b ON d.item_id = b.id
+		)
+		SELECT COUNT(*) FROM blockers WHERE id = ?`, blockerID, id).Scan(&cycle)
+	if err != nil {
+		return err
+	}
+	if cycle > 0 {
+		return ErrDependencyCycle
+	}
+
+	if _, err := tx.Exec("INSERT IGNORE INTO item_dependencies (item_id, blocker_id) VALUES (?, ?)", id, blockerID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ItemMySqlRepository) RemoveDependency(id int, blockerID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := r.lockModifiable(tx, id); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	// lockModifiable no encuentra los items eliminados, cuyas dependencias
+	// también se pueden quitar, así que el tenant se verifica en la consulta
+	result, err := tx.Exec("DELETE FROM item_dependencies WHERE item_id = ? AND blocker_id = ? AND item_id IN (SELECT id FROM todo_items WHERE tenant_id = ?)", id, blockerID, r.meta.Tenant())
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (r *ItemMySqlRepository) GetBlockers(id int, transitive bool) ([]models.TodoItem, error) {
+	var exists int
+	if err := r.db.QueryRow("SELECT 1 FROM todo_items WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL", id, r.meta.Tenant()).Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + itemColumns + " FROM todo_items WHERE deleted_at IS NULL AND id IN (SELECT blocker_id FROM item_dependencies WHERE item_id = ?) ORDER BY id"
+	if transitive {
+		// UNION (sin ALL) descarta repetidos, por lo que la recursión termina aunque hubiera ciclos
+		query = `WITH RECURSIVE blockers AS (
+				SELECT blocker_id AS id FROM item_dependencies WHERE item_id = ?
+				UNION
+				SELECT d.blocker_id FROM item_dependencies d JOIN blockers b ON d.item_id = b.id
+			)
+			SELECT ` + itemColumns + ` FROM todo_items WHERE deleted_at IS NULL AND id IN (SELECT id FROM blockers) ORDER BY id`
+	}
+
+	rows, err := r.db.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.TodoItem{}
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadDetails(items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// lockGraph bloquea hasta el fin de la transacción la fila del tenant, como
+// checkQuota, para serializar los cambios de sus dependencias y su jerarquía:
+// las consultas que buscan ciclos no bloquean las filas que recorren, y sin
+// esto dos cambios simultáneos (A→B y B→A) podrían cerrar un ciclo entre los
+// dos. Se toma antes que las filas de los items para bloquear siempre en el
+// mismo orden.
+func lockGraph(tx *sql.Tx, tenantID string) error {
+	var exists int
+	err := tx.QueryRow("SELECT 1 FROM tenants WHERE id = ? FOR UPDATE", tenantID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTenantNotFound
+	}
+	return err
+}
+
+// checkParentExists verifica que el item padre exista en el tenant y no esté eliminado
+func checkParentExists(tx *sql.Tx, tenantID string, parentID string) error {
+	var exists int
+	err := tx.QueryRow("SELECT 1 FROM todo_items WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL FOR UPDATE", parentID, tenantID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrParentNotFound
+	}
+	return err
+}
+
+// MaxAncestors corta el recorrido hacia la raíz si los datos tuvieran un ciclo
+const MaxAncestors = 1000
+
+// checkHierarchy verifica que colgar el item itemID de parentID no genere ciclos
+// ni supere maxDepth niveles (0 sin límite). Recorre filas sin bloquearlas, así
+// que la transacción debe haber tomado antes lockGraph.
+func checkHierarchy(tx *sql.Tx, tenantID string, itemID string, parentID string, maxDepth int) error {
+	// Niveles desde parentID hasta la raíz, incluido parentID
+	depth := 0
+	current := &parentID
+	for current != nil && depth < MaxAncestors {
+		if *current == itemID {
+			return ErrHierarchyCycle
+		}
+		var next *string
+		err := tx.QueryRow("SELECT parent_id FROM todo_items WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL", *current, tenantID).Scan(&next)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrParentNotFound
+		}
+		if err != nil {
+			return err
+		}
+		depth++
+		current = next
+	}
+
+	if maxDepth == 0 {
+		return nil
+	}
+	// Niveles del subárbol que se mueve, incluido el item
+	var height int
+	err := tx.QueryRow(`WITH RECURSIVE subtree AS (
+			SELECT id, 1 AS level FROM todo_items WHERE id = ?
+			UNION ALL
+			SELECT t.id, s.level + 1 FROM todo_items t JOIN subtree s ON t.parent_id = s.id WHERE t.deleted_at IS NULL AND s.level < ?
+		)
+		SELECT MAX(level) FROM subtree`, itemID, MaxAncestors).Scan(&height)
+	if err != nil {
+		return err
+	}
+	if depth+height > maxDepth {
+		return ErrMaxDepth
+	}
+	return nil
+}
+
+// checkAssigneeExists verifica que el usuario responsable exista en el tenant
+func checkAssigneeExists(tx *sql.Tx, tenantID string, assigneeID string) error {
+	var exists int
+	err := tx.QueryRow("SELECT 1 FROM users WHERE id = ? AND tenant_id = ?", assigneeID, tenantID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAssigneeNotFound
+	}
 	return err
 }
+
+func (r *ItemMySqlRepository) AssignItem(id int, assigneeID *string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := r.lockModifiable(tx, id)
+	if err != nil {
+		return err
+	}
+	if assigneeID != nil {
+		if err := checkAssigneeExists(tx, r.meta.Tenant(), *assigneeID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE todo_items SET assignee_id = ? WHERE id = ?", assigneeID, id); err != nil {
+		return err
+	}
+	updated := current
+	updated.AssigneeID = assigneeID
+	if err := r.recordChanges(tx, current, updated); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ItemMySqlRepository) MoveItem(id int, projectID *string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if projectID != nil {
+		if err := checkProjectWritable(tx, r.meta.Tenant(), *projectID); err != nil {
+			return err
+		}
+		if !policy.Can(r.meta, constants.PermissionWrite, projectID) {
+			return ErrPermissionDenied
+		}
+	}
+
+	current, err := r.lockModifiable(tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE todo_items SET project_id = ? WHERE id = ?", projectID, id); err != nil {
+		return err
+	}
+	updated := current
+	updated.ProjectID = projectID
+	if err := r.recordChanges(tx, current, updated); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// childIDs devuelve los IDs de las subtareas directas de un item
+func childIDs(tx *sql.Tx, parentID string) ([]string, error) {
+	rows, err := tx.Query("SELECT id FROM todo_items WHERE parent_id = ? FOR UPDATE", parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// checkProjectWritable verifica que el proyecto exista en el tenant y no esté
+// archivado, bloqueando la fila hasta el fin de la transacción
+func checkProjectWritable(tx *sql.Tx, tenantID string, projectID string) error {
+	var archivedAt sql.NullTime
+	err := tx.QueryRow("SELECT archived_at FROM projects WHERE id = ? AND tenant_id = ? FOR UPDATE", projectID, tenantID).Scan(&archivedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrProjectNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if archivedAt.Valid {
+		return ErrProjectArchived
+	}
+	return nil
+}
+
+// setItemTags reemplaza las etiquetas de un item, creando en su tenant las que no existan
+func setItemTags(tx *sql.Tx, tenantID string, itemID string, tags []string) error {
+	if _, err := tx.Exec("DELETE FROM todo_item_tags WHERE item_id = ?", itemID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec("INSERT IGNORE INTO tags (tenant_id, name) VALUES (?, ?)", tenantID, tag); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("INSERT INTO todo_item_tags (item_id, tag_id) SELECT ?, id FROM tags WHERE tenant_id = ? AND name = ?", itemID, tenantID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDetails completa los datos de los items que viven en otras tablas o filas
+func (r *ItemMySqlRepository) loadDetails(items []models.TodoItem) error {
+	if err := r.loadTags(items); err != nil {
+		return err
+	}
+	if err := r.loadBlockedBy(items); err != nil {
+		return err
+	}
+	return r.loadProgress(items)
+}
+
+// loadBlockedBy completa los IDs de los bloqueantes directos (no eliminados) de los items
+func (r *ItemMySqlRepository) loadBlockedBy(items []models.TodoItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(items))
+	args := make([]any, 0, len(items))
+	for i := range items {
+		items[i].BlockedBy = []string{}
+		index[items[i].ID] = i
+		args = append(args, items[i].ID)
+	}
+
+	rows, err := r.db.Query("SELECT d.item_id, d.blocker_id FROM item_dependencies d JOIN todo_items b ON b.id = d.blocker_id WHERE b.deleted_at IS NULL AND d.item_id IN (?"+strings.Repeat(", ?", len(items)-1)+") ORDER BY d.blocker_id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID, blockerID string
+		if err := rows.Scan(&itemID, &blockerID); err != nil {
+			return err
+		}
+		if i, ok := index[itemID]; ok {
+			items[i].BlockedBy = append(items[i].BlockedBy, blockerID)
+		}
+	}
+	return rows.Err()
+}
+
+// loadProgress calcula el progreso de los items a partir del estado de sus subtareas directas
+func (r *ItemMySqlRepository) loadProgress(items []models.TodoItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(items))
+	args := make([]any, 0, len(items)+1)
+	args = append(args, constants.StateCompleted)
+	for i := range items {
+		items[i].Progress = nil
+		index[items[i].ID] = i
+		args = append(args, items[i].ID)
+	}
+
+	rows, err := r.db.Query("SELECT parent_id, COUNT(*), COALESCE(SUM(state = ?), 0) FROM todo_items WHERE deleted_at IS NULL AND parent_id IN (?"+strings.Repeat(", ?", len(items)-1)+") GROUP BY parent_id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentID string
+		var total, completed int
+		if err := rows.Scan(&parentID, &total, &completed); err != nil {
+			return err
+		}
+		if i, ok := index[parentID]; ok {
+			items[i].Progress = models.ComputeProgress(completed, total)
+		}
+	}
+	return rows.Err()
+}
+
+// loadTags completa las etiquetas de los items con una sola consulta
+func (r *ItemMySqlRepository) loadTags(items []models.TodoItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(items))
+	args := make([]any, 0, len(items))
+	for i := range items {
+		items[i].Tags = []string{}
+		index[items[i].ID] = i
+		args = append(args, items[i].ID)
+	}
+
+	rows, err := r.db.Query("SELECT it.item_id, t.name FROM todo_item_tags it JOIN tags t ON t.id = it.tag_id WHERE it.item_id IN (?"+strings.Repeat(", ?", len(items)-1)+") ORDER BY t.name", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID, name string
+		if err := rows.Scan(&itemID, &name); err != nil {
+			return err
+		}
+		if i, ok := index[itemID]; ok {
+			items[i].Tags = append(items[i].Tags, name)
+		}
+	}
+	return rows.Err()
+}
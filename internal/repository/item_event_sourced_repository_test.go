@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// streamOf traduce el historial de un item (del más reciente al más antiguo) a
+// su flujo, como appendEvents, pasando los datos por JSON como al guardarlos
+func streamOf(t *testing.T, history []models.ItemEvent) []models.ItemStreamEvent {
+	var stream []models.ItemStreamEvent
+	for _, event := range slices.Backward(history) {
+		for _, streamEvent := range models.StreamEventsFor(event.Type, event.Changes) {
+			data, err := json.Marshal(streamEvent.Data)
+			assert.NoError(t, err)
+			streamEvent.Data = nil
+			assert.NoError(t, json.Unmarshal(data, &streamEvent.Data))
+			streamEvent.Version = len(stream) + 1
+			streamEvent.OccurredAt = event.CreatedAt
+			stream = append(stream, streamEvent)
+		}
+	}
+	return stream
+}
+
+// assertSameState compara los campos del estado del flujo de dos items
+func assertSameState(t *testing.T, expected models.TodoItem, actual models.TodoItem) {
+	assert.Equal(t, expected.Title, actual.Title)
+	assert.Equal(t, expected.Description, actual.Description)
+	assert.Equal(t, expected.State, actual.State)
+	assert.Equal(t, expected.Priority, actual.Priority)
+	assert.ElementsMatch(t, expected.Tags, actual.Tags)
+	assert.Equal(t, expected.ProjectID, actual.ProjectID)
+	assert.Equal(t, expected.ParentID, actual.ParentID)
+	assert.Equal(t, expected.CreatedBy, actual.CreatedBy)
+	assert.Equal(t, expected.AssigneeID, actual.AssigneeID)
+	assert.Equal(t, expected.StartAt, actual.StartAt)
+	assert.Equal(t, expected.DueAt, actual.DueAt)
+	assert.Equal(t, expected.DeletedAt != nil, actual.DeletedAt != nil)
+}
+
+func TestProjectItem_ReplaysStream(t *testing.T) {
+	repo := NewMockRepository()
+	user, _ := repo.CreateUser(models.User{Email: "ana@example.com", Name: "Ana"})
+	project, _ := repo.CreateProject(models.Project{Name: "Proyecto"})
+	repo.Create(models.TodoItem{Title: "Padre", State: constants.StatePending})
+
+	due := "2030-01-02T15:04:05Z"
+	created, err := repo.Create(models.TodoItem{Title: "Tarea", Description: "Inicial", State: constants.StatePending, Priority: "low", Tags: []string{"casa"}})
+	assert.NoError(t, err)
+	id := 2
+	parentID := "1"
+	assert.NoError(t, repo.Update(models.TodoItem{ID: created.ID, Title: "Tarea editada", Description: "Editada", State: constants.StateInProgress,
+		Priority: "high", Tags: []string{"casa", "urgente"}, DueAt: &due}))
+	assert.NoError(t, repo.AssignItem(id, &user.ID))
+	assert.NoError(t, repo.MoveItem(id, &project.ID))
+	assert.NoError(t, repo.SetParent(id, &parentID, 0))
+
+	history, _, _ := repo.GetHistory(id, 1, 100)
+	stream := streamOf(t, history)
+	current, _ := repo.Get(id)
+
+	// Al reproducir el flujo completo se obtiene el item actual
+	projected := projectItem(models.TodoItem{ID: created.ID}, stream)
+	assertSameState(t, current, projected)
+	assert.Equal(t, history[len(history)-1].CreatedAt, projected.CreatedAt)
+	assert.Equal(t, []string{}, projected.BlockedBy)
+
+	// Y lo mismo desde un snapshot intermedio y los eventos posteriores
+	snapshot := projectItem(models.TodoItem{ID: created.ID}, stream[:2])
+	state, _ := json.Marshal(snapshot)
+	var restored models.TodoItem
+	assert.NoError(t, json.Unmarshal(state, &restored))
+	assertSameState(t, current, projectItem(restored, stream[2:]))
+
+	// Eliminado y restaurado
+	assert.NoError(t, repo.Delete(created.ID))
+	history, _, _ = repo.GetHistory(id, 1, 100)
+	assert.NotNil(t, projectItem(models.TodoItem{ID: created.ID}, streamOf(t, history)).DeletedAt)
+	assert.NoError(t, repo.Restore(id))
+	history, _, _ = repo.GetHistory(id, 1, 100)
+	current, _ = repo.Get(id)
+	assertSameState(t, current, projectItem(models.TodoItem{ID: created.ID}, streamOf(t, history)))
+}
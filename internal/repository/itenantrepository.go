@@ -0,0 +1,15 @@
+package repository
+
+import "github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+
+// ITenantRepository lee los tenants. El resto de los repositorios se limita a
+// los datos de un tenant: IRepository con RequestMeta.TenantID y los demás con
+// su vista XForTenant; sin vista usan constants.DefaultTenantID.
+type ITenantRepository interface {
+	// GetTenant devuelve sql.ErrNoRows si el tenant no existe
+	GetTenant(id string) (models.Tenant, error)
+	// GetTenantBySlug devuelve sql.ErrNoRows si el tenant no existe
+	GetTenantBySlug(slug string) (models.Tenant, error)
+	// GetTenantUsage cuenta los items (no eliminados) y los proyectos del tenant
+	GetTenantUsage(id string) (models.TenantUsage, error)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+// IUserRepository no se limita a un tenant: los usuarios se buscan al
+// autenticar, antes de conocerlo, y cada uno lleva el suyo en User.TenantID
+type IUserRepository interface {
+	// CreateUser guarda el usuario en user.TenantID (el tenant por defecto si está
+	// vacío); ErrDuplicateEmail si el email ya está registrado en cualquier tenant
+	CreateUser(user models.User) (models.User, error)
+	GetUser(id int) (models.User, error)
+	// GetUserByEmail busca por email (en minúsculas); sql.ErrNoRows si no existe
+	GetUserByEmail(email string) (models.User, error)
+	// GetUserByIdentity busca al usuario de un proveedor OIDC por emisor y sub;
+	// sql.ErrNoRows si no existe
+	GetUserByIdentity(issuer string, subject string) (models.User, error)
+	// LinkUserIdentity asocia un usuario existente a su identidad en un proveedor OIDC
+	LinkUserIdentity(id string, issuer string, subject string) (models.User, error)
+	// SetUserRole cambia el rol global del usuario; sql.ErrNoRows si no existe
+	SetUserRole(id string, role string) (models.User, error)
+	// GetUserProjectRoles devuelve el rol del usuario en cada proyecto del que es
+	// miembro, por ID de proyecto
+	GetUserProjectRoles(userID string) (map[string]string, error)
+	// GetUserShares devuelve los compartidos vigentes (no revocados ni vencidos)
+	// con el usuario
+	GetUserShares(userID string) ([]models.Share, error)
+
+	// CreateRefreshToken guarda el refresh token (solo su hash)
+	CreateRefreshToken(token models.RefreshToken) error
+	// GetRefreshToken busca el refresh token por su hash; sql.ErrNoRows si no existe
+	GetRefreshToken(hash string) (models.RefreshToken, error)
+	// RotateRefreshToken revoca el token oldHash y guarda next en la misma
+	// transacción; ErrTokenReused si oldHash ya estaba revocado, para que
+	// dos usos simultáneos del mismo token no obtengan dos tokens nuevos
+	RotateRefreshToken(oldHash string, next models.RefreshToken) error
+	// RevokeRefreshToken revoca el refresh token, si existe y no estaba revocado
+	RevokeRefreshToken(hash string) error
+	// RevokeUserRefreshTokens revoca todos los refresh tokens del usuario
+	RevokeUserRefreshTokens(userID string) error
+
+	// RevokeAccessToken registra el jti de un token de acceso como revocado hasta
+	// que vence (expiresAt)
+	RevokeAccessToken(jti string, expiresAt time.Time) error
+	// IsAccessTokenRevoked indica si el jti fue revocado
+	IsAccessTokenRevoked(jti string) (bool, error)
+}
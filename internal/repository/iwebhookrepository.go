@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+// DeliveryFilter filtra el listado de entregas; los campos vacíos no filtran
+type DeliveryFilter struct {
+	WebhookID string
+	Status    string
+}
+
+type IWebhookRepository interface {
+	GetAllWebhooks() ([]models.Webhook, error)
+	GetWebhook(id int) (models.Webhook, error)
+	CreateWebhook(webhook models.Webhook) (models.Webhook, error)
+	// UpdateWebhook conserva el secreto actual si webhook.Secret está vacío
+	UpdateWebhook(webhook models.Webhook) (models.Webhook, error)
+	DeleteWebhook(id int) error
+
+	// GetDeliveries devuelve una página de entregas, de la más reciente a la más antigua, y el total
+	GetDeliveries(filter DeliveryFilter, page int, pageSize int) ([]models.WebhookDelivery, int, error)
+	// Redeliver vuelve a dejar pendiente una entrega (por ejemplo una en dead-letter) para enviarla ya
+	Redeliver(id int) (models.WebhookDelivery, error)
+
+	// WebhooksForTenant devuelve una vista limitada a los webhooks (y sus
+	// entregas) del tenant; sin vista se usa el tenant por defecto. FanOutOutbox
+	// y las entregas que toma el dispatcher son de todos los tenants.
+	WebhooksForTenant(tenantID string) IWebhookRepository
+
+	// FanOutOutbox crea las entregas de hasta limit eventos del outbox para los
+	// webhooks suscritos y los quita del outbox. Devuelve cuántos eventos procesó.
+	FanOutOutbox(limit int) (int, error)
+	// ClaimDueDeliveries toma hasta limit entregas pendientes vencidas y posterga
+	// su próximo intento en lease, para que otra instancia no las envíe a la vez
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	// CompleteDelivery marca la entrega como realizada
+	CompleteDelivery(id string, statusCode int) error
+	// FailDelivery registra un intento fallido; nextAttempt nil la pasa a dead-letter
+	FailDelivery(id string, statusCode *int, errMsg string, nextAttempt *time.Time) error
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+func (r *MockRepository) GetAllAPIKeys() ([]models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	ids := make([]int, 0, len(r.apiKeys))
+	for id, key := range r.apiKeys {
+		if key.TenantID == r.tenant() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	keys := make([]models.APIKey, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, r.apiKeys[id])
+	}
+	return keys, nil
+}
+
+func (r *MockRepository) GetAPIKey(id int) (models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.APIKey{}, errors.New("simulated database error")
+	}
+
+	key, exists := r.apiKeys[id]
+	if !exists || key.TenantID != r.tenant() {
+		return models.APIKey{}, sql.ErrNoRows
+	}
+	return key, nil
+}
+
+func (r *MockRepository) GetAPIKeyByHash(hash string) (models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.APIKey{}, errors.New("simulated database error")
+	}
+
+	for _, key := range r.apiKeys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return models.APIKey{}, sql.ErrNoRows
+}
+
+func (r *MockRepository) CreateAPIKey(key models.APIKey) (models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.APIKey{}, errors.New("simulated database error")
+	}
+
+	key.ID = strconv.Itoa(r.nextAPIKeyID)
+	key.TenantID = r.tenant()
+	key.Key = ""
+	key.LastUsedAt = nil
+	key.RevokedAt = nil
+	key.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	key.UpdatedAt = key.CreatedAt
+	r.apiKeys[r.nextAPIKeyID] = key
+	r.nextAPIKeyID++
+	return key, nil
+}
+
+// updateActiveKey aplica update sobre una key no revocada
+func (r *MockRepository) updateActiveKey(id int, update func(key *models.APIKey)) (models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.APIKey{}, errors.New("simulated database error")
+	}
+
+	key, exists := r.apiKeys[id]
+	if !exists || key.TenantID != r.tenant() {
+		return models.APIKey{}, sql.ErrNoRows
+	}
+	if key.Revoked() {
+		return models.APIKey{}, ErrAPIKeyRevoked
+	}
+	update(&key)
+	key.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	r.apiKeys[id] = key
+	return key, nil
+}
+
+func (r *MockRepository) RevokeAPIKey(id int) (models.APIKey, error) {
+	return r.updateActiveKey(id, func(key *models.APIKey) {
+		now := time.Now().UTC().Format(time.RFC3339)
+		key.RevokedAt = &now
+	})
+}
+
+func (r *MockRepository) RotateAPIKey(id int, prefix string, hash string) (models.APIKey, error) {
+	return r.updateActiveKey(id, func(key *models.APIKey) {
+		key.Prefix = prefix
+		key.Hash = hash
+	})
+}
+
+func (r *MockRepository) TouchAPIKey(id string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	keyID, _ := strconv.Atoi(id)
+	key, exists := r.apiKeys[keyID]
+	if !exists {
+		return sql.ErrNoRows
+	}
+	lastUsed := usedAt.UTC().Format(time.RFC3339)
+	key.LastUsedAt = &lastUsed
+	r.apiKeys[keyID] = key
+	return nil
+}
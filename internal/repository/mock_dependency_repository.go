@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+func (r *MockRepository) AddDependency(id int, blockerID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	idStr, blockerStr := strconv.Itoa(id), strconv.Itoa(blockerID)
+	if _, err := r.modifiable(idStr); err != nil {
+		return err
+	}
+	if blocker, exists := r.tenantItem(blockerStr); !exists || blocker.DeletedAt != nil {
+		return ErrBlockerNotFound
+	}
+	if r.blocks(idStr, blockerStr) {
+		return ErrDependencyCycle
+	}
+
+	if !slices.Contains(r.dependencies[idStr], blockerStr) {
+		r.dependencies[idStr] = append(r.dependencies[idStr], blockerStr)
+	}
+	return nil
+}
+
+// blocks indica si itemID bloquea, directa o indirectamente, a blockerID (o si
+// son el mismo item). Requiere r.mu tomado.
+func (r *MockRepository) blocks(itemID string, blockerID string) bool {
+	visited := map[string]bool{}
+	pending := []string{blockerID}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		if current == itemID {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		pending = append(pending, r.dependencies[current]...)
+	}
+	return false
+}
+
+func (r *MockRepository) RemoveDependency(id int, blockerID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	idStr, blockerStr := strconv.Itoa(id), strconv.Itoa(blockerID)
+	if _, err := r.modifiable(idStr); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	index := slices.Index(r.dependencies[idStr], blockerStr)
+	if _, exists := r.tenantItem(idStr); !exists || index < 0 {
+		return sql.ErrNoRows
+	}
+	r.dependencies[idStr] = slices.Delete(r.dependencies[idStr], index, index+1)
+	return nil
+}
+
+func (r *MockRepository) GetBlockers(id int, transitive bool) ([]models.TodoItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	idStr := strconv.Itoa(id)
+	if item, exists := r.tenantItem(idStr); !exists || item.DeletedAt != nil {
+		return nil, sql.ErrNoRows
+	}
+
+	// Recorrido en anchura; visited evita repetir (y colgarse si hubiera ciclos)
+	visited := map[string]bool{}
+	pending := slices.Clone(r.dependencies[idStr])
+	items := []models.TodoItem{}
+	for len(pending) > 0 {
+		blockerID := pending[0]
+		pending = pending[1:]
+		if visited[blockerID] {
+			continue
+		}
+		visited[blockerID] = true
+
+		if blocker, exists := r.items[blockerID]; exists && blocker.DeletedAt == nil {
+			items = append(items, r.withDetails(blocker))
+		}
+		if transitive {
+			pending = append(pending, r.dependencies[blockerID]...)
+		}
+	}
+	sortItems(items, models.ItemFilter{})
+	return items, nil
+}
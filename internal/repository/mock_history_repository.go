@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+// recordEvent agrega un evento al historial a nombre de r.meta, y sus eventos de
+// webhook al outbox. Requiere r.mu tomado.
+func (r *MockRepository) recordEvent(itemID string, eventType string, changes map[string]models.FieldChange) {
+	event := models.ItemEvent{
+		ID:        strconv.Itoa(len(r.events) + 1),
+		ItemID:    itemID,
+		Type:      eventType,
+		Actor:     r.meta.Actor,
+		RequestID: r.meta.RequestID,
+		Changes:   changes,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	r.events = append(r.events, event)
+
+	// Los cambios de campos simples siempre se pueden serializar
+	outbox, _ := outboxEventsFor(event)
+	for i := range outbox {
+		outbox[i].tenantID = r.meta.Tenant()
+	}
+	r.outbox = append(r.outbox, outbox...)
+}
+
+// recordChanges registra un evento de actualización si algún campo cambió. Requiere r.mu tomado.
+func (r *MockRepository) recordChanges(old models.TodoItem, new models.TodoItem) {
+	if changes := models.DiffItems(&old, new); len(changes) > 0 {
+		r.recordEvent(new.ID, constants.EventUpdated, changes)
+	}
+}
+
+func (r *MockRepository) Restore(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	idStr := strconv.Itoa(id)
+	item, exists := r.tenantItem(idStr)
+	if !exists {
+		return sql.ErrNoRows
+	}
+	if item.DeletedAt == nil {
+		return ErrItemNotDeleted
+	}
+	if err := r.checkItemQuota(r.meta.Tenant()); err != nil {
+		return err
+	}
+	if err := authorizeChange(r.meta, constants.PermissionDelete, item); err != nil {
+		return err
+	}
+	item.DeletedAt = nil
+	r.items[idStr] = item
+	r.recordEvent(idStr, constants.EventRestored, map[string]models.FieldChange{})
+	return nil
+}
+
+func (r *MockRepository) GetHistory(id int, page int, pageSize int) ([]models.ItemEvent, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, 0, errors.New("simulated database error")
+	}
+
+	idStr := strconv.Itoa(id)
+	if _, exists := r.tenantItem(idStr); !exists {
+		return nil, 0, sql.ErrNoRows
+	}
+
+	// Del más reciente al más antiguo, como el repositorio MySQL
+	history := []models.ItemEvent{}
+	for i := len(r.events) - 1; i >= 0; i-- {
+		if r.events[i].ItemID == idStr {
+			history = append(history, r.events[i])
+		}
+	}
+
+	total := len(history)
+	start := min((page-1)*pageSize, total)
+	end := min(start+pageSize, total)
+	return history[start:end], total, nil
+}
+
+func (r *MockRepository) GetAsOf(id int, asOf time.Time) (models.TodoItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.TodoItem{}, errors.New("simulated database error")
+	}
+
+	idStr := strconv.Itoa(id)
+	if _, exists := r.tenantItem(idStr); !exists {
+		return models.TodoItem{}, sql.ErrNoRows
+	}
+	item := models.TodoItem{ID: idStr}
+	created := false
+	for _, event := range r.events {
+		if event.ItemID != idStr {
+			continue
+		}
+		at, err := models.ParseTimestamp(event.CreatedAt)
+		if err != nil || at.After(asOf) {
+			continue
+		}
+		created = created || event.Type == constants.EventCreated
+		item.ApplyEvent(event.Type, models.ChangeValues(event.Changes), event.CreatedAt)
+	}
+
+	if !created || item.DeletedAt != nil {
+		return models.TodoItem{}, sql.ErrNoRows
+	}
+	item.BlockedBy = []string{}
+	return item, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+func (r *MockRepository) GetProjectMembers(projectID int) ([]models.ProjectMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	members := []models.ProjectMember{}
+	if _, exists := r.tenantProject(projectID); !exists {
+		return members, nil
+	}
+	for _, member := range r.projectMembers[projectID] {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		a, _ := strconv.Atoi(members[i].UserID)
+		b, _ := strconv.Atoi(members[j].UserID)
+		return a < b
+	})
+	return members, nil
+}
+
+func (r *MockRepository) SetProjectMember(member models.ProjectMember) (models.ProjectMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.ProjectMember{}, errors.New("simulated database error")
+	}
+
+	projectID, _ := strconv.Atoi(member.ProjectID)
+	userID, _ := strconv.Atoi(member.UserID)
+	if user, exists := r.users[userID]; !exists || scopeTenant(user.TenantID) != r.tenant() {
+		return models.ProjectMember{}, ErrUserNotFound
+	}
+	if r.projectMembers[projectID] == nil {
+		r.projectMembers[projectID] = make(map[int]models.ProjectMember)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	member.CreatedAt = now
+	if existing, exists := r.projectMembers[projectID][userID]; exists {
+		member.CreatedAt = existing.CreatedAt
+	}
+	member.UpdatedAt = now
+	r.projectMembers[projectID][userID] = member
+	return member, nil
+}
+
+func (r *MockRepository) RemoveProjectMember(projectID int, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	if _, exists := r.tenantProject(projectID); !exists {
+		return ErrMemberNotFound
+	}
+	if _, exists := r.projectMembers[projectID][userID]; !exists {
+		return ErrMemberNotFound
+	}
+	delete(r.projectMembers[projectID], userID)
+	return nil
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+// checkProjectWritable replica la validación del repositorio MySQL. Requiere r.mu tomado.
+func (r *MockRepository) checkProjectWritable(tenantID string, projectID string) error {
+	id, err := strconv.Atoi(projectID)
+	if err != nil {
+		return ErrProjectNotFound
+	}
+	project, exists := r.projects[id]
+	if !exists || !belongs(r.projectTenants, id, tenantID) {
+		return ErrProjectNotFound
+	}
+	if project.Archived {
+		return ErrProjectArchived
+	}
+	return nil
+}
+
+// tenantProject devuelve el proyecto si es del tenant de la vista. Requiere r.mu tomado.
+func (r *MockRepository) tenantProject(id int) (models.Project, bool) {
+	project, exists := r.projects[id]
+	if !exists || !belongs(r.projectTenants, id, r.tenant()) {
+		return models.Project{}, false
+	}
+	return project, true
+}
+
+// withCounts completa los conteos por estado de un proyecto. Requiere r.mu tomado.
+func (r *MockRepository) withCounts(project models.Project) models.Project {
+	project.Counts = models.NewStateCounts()
+	for _, item := range r.items {
+		if item.DeletedAt == nil && item.ProjectID != nil && *item.ProjectID == project.ID {
+			project.Counts[item.State]++
+		}
+	}
+	return project
+}
+
+func (r *MockRepository) GetAllProjects(includeArchived bool) ([]models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	ids := make([]int, 0, len(r.projects))
+	for id, project := range r.projects {
+		if belongs(r.projectTenants, id, r.tenant()) && (includeArchived || !project.Archived) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	projects := make([]models.Project, 0, len(ids))
+	for _, id := range ids {
+		projects = append(projects, r.withCounts(r.projects[id]))
+	}
+	return projects, nil
+}
+
+func (r *MockRepository) GetProject(id int) (models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.Project{}, errors.New("simulated database error")
+	}
+
+	project, exists := r.tenantProject(id)
+	if !exists {
+		return models.Project{}, sql.ErrNoRows
+	}
+	return r.withCounts(project), nil
+}
+
+func (r *MockRepository) GetProjectsByIDs(ids []int) ([]models.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	sorted := slices.Clone(ids)
+	sort.Ints(sorted)
+	projects := []models.Project{}
+	for _, id := range slices.Compact(sorted) {
+		if project, exists := r.tenantProject(id); exists {
+			projects = append(projects, r.withCounts(project))
+		}
+	}
+	return projects, nil
+}
+
+func (r *MockRepository) CreateProject(project models.Project) (models.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.Project{}, errors.New("simulated database error")
+	}
+
+	if err := r.checkProjectQuota(r.tenant()); err != nil {
+		return models.Project{}, err
+	}
+
+	id := r.nextProjectID
+	r.nextProjectID++
+	project.ID = strconv.Itoa(id)
+	project.Archived = false
+	project.ArchivedAt = nil
+	r.projects[id] = project
+	r.projectTenants[id] = r.tenant()
+	return r.withCounts(project), nil
+}
+
+func (r *MockRepository) UpdateProject(project models.Project) (models.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.Project{}, errors.New("simulated database error")
+	}
+
+	id, err := strconv.Atoi(project.ID)
+	if err != nil {
+		return models.Project{}, sql.ErrNoRows
+	}
+	current, exists := r.tenantProject(id)
+	if !exists {
+		return models.Project{}, sql.ErrNoRows
+	}
+	current.Name = project.Name
+	current.Description = project.Description
+	r.projects[id] = current
+	return r.withCounts(current), nil
+}
+
+func (r *MockRepository) DeleteProject(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	project, exists := r.tenantProject(id)
+	if !exists {
+		return sql.ErrNoRows
+	}
+	for _, count := range r.withCounts(project).Counts {
+		if count > 0 {
+			return ErrProjectNotEmpty
+		}
+	}
+
+	// Igual que ON DELETE SET NULL para los items eliminados
+	for itemID, item := range r.items {
+		if item.ProjectID != nil && *item.ProjectID == project.ID {
+			item.ProjectID = nil
+			r.items[itemID] = item
+		}
+	}
+	delete(r.projects, id)
+	delete(r.projectMembers, id)
+	return nil
+}
+
+func (r *MockRepository) SetProjectArchived(id int, archived bool) (models.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.Project{}, errors.New("simulated database error")
+	}
+
+	project, exists := r.tenantProject(id)
+	if !exists {
+		return models.Project{}, sql.ErrNoRows
+	}
+	if archived && !project.Archived {
+		archivedAt := "archived"
+		project.ArchivedAt = &archivedAt
+	}
+	if !archived {
+		project.ArchivedAt = nil
+	}
+	project.Archived = archived
+	r.projects[id] = project
+	return r.withCounts(project), nil
+}
@@ -4,26 +4,107 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/policy"
 )
 
 // MockRepository implementa IRepository usando un map en memoria para testing
+// además de ITagRepository e IProjectRepository, compartiendo los datos para que
+// etiquetas y proyectos se reflejen en los items
 type MockRepository struct {
+	*mockStore
+	// meta identifica al autor de los cambios en el historial y el tenant de los
+	// items, ver WithMeta
+	meta models.RequestMeta
+	// tenantID es el tenant del resto de los datos, ver ProjectsForTenant y las
+	// demás vistas XForTenant
+	tenantID string
+}
+
+// mockStore son los datos en memoria, compartidos por las vistas que devuelve WithMeta
+type mockStore struct {
 	items        map[string]models.TodoItem
 	nextID       int
+	tags         map[int]models.Tag
+	nextTagID    int
+	projects     map[int]models.Project
+	nextProjectID int
+	// dependencies guarda, por ID de item, los IDs de sus bloqueantes directos
+	dependencies map[string][]string
+	events       []models.ItemEvent
+	webhooks     map[int]models.Webhook
+	nextWebhookID int
+	outbox       []outboxEvent
+	deliveries   map[int]models.WebhookDelivery
+	nextDeliveryID int
+	apiKeys      map[int]models.APIKey
+	nextAPIKeyID int
+	users        map[int]models.User
+	nextUserID   int
+	// refreshTokens guarda los refresh tokens por hash y revokedTokens el
+	// vencimiento de los tokens de acceso revocados, por jti
+	refreshTokens map[string]models.RefreshToken
+	revokedTokens map[string]time.Time
+	// projectMembers guarda las membresías por ID de proyecto y de usuario
+	projectMembers map[int]map[int]models.ProjectMember
+	tenants      map[string]models.Tenant
+	nextTenantID int
+	// xTenants guardan el tenant de cada entidad que no lo tiene en su modelo,
+	// ver belongs
+	itemTenants    map[string]string
+	projectTenants map[int]string
+	tagTenants     map[int]string
+	webhookTenants map[int]string
+	shares       map[int]models.Share
+	nextShareID  int
 	mu           sync.RWMutex
 	simulateError bool
 }
 
 func NewMockRepository() *MockRepository {
-	return &MockRepository{
+	return &MockRepository{mockStore: &mockStore{
 		items:        make(map[string]models.TodoItem),
 		nextID:       1,
+		tags:         make(map[int]models.Tag),
+		nextTagID:    1,
+		projects:     make(map[int]models.Project),
+		nextProjectID: 1,
+		dependencies: make(map[string][]string),
+		webhooks:     make(map[int]models.Webhook),
+		nextWebhookID: 1,
+		deliveries:   make(map[int]models.WebhookDelivery),
+		nextDeliveryID: 1,
+		apiKeys:      make(map[int]models.APIKey),
+		nextAPIKeyID: 1,
+		users:        make(map[int]models.User),
+		nextUserID:   1,
+		refreshTokens: make(map[string]models.RefreshToken),
+		revokedTokens: make(map[string]time.Time),
+		projectMembers: make(map[int]map[int]models.ProjectMember),
+		tenants:      map[string]models.Tenant{defaultTenant.ID: defaultTenant},
+		nextTenantID: 2,
+		itemTenants:    make(map[string]string),
+		projectTenants: make(map[int]string),
+		tagTenants:     make(map[int]string),
+		webhookTenants: make(map[int]string),
+		shares:       make(map[int]models.Share),
+		nextShareID:  1,
 		simulateError: false,
-	}
+	}}
+}
+
+// WithMeta devuelve una vista sobre los mismos datos que registra los cambios a
+// nombre de meta y se limita a los items de meta.TenantID
+func (r *MockRepository) WithMeta(meta models.RequestMeta) IRepository {
+	return &MockRepository{mockStore: r.mockStore, meta: meta, tenantID: r.tenantID}
 }
 
 // SimulateError activa la simulación de errores de base de datos
@@ -33,7 +114,98 @@ func (r *MockRepository) SimulateError(enable bool) {
 	r.simulateError = enable
 }
 
-func (r *MockRepository) GetAll() ([]models.TodoItem, error) {
+// matchesFilter replica en memoria los filtros que aplica el repositorio MySQL
+func matchesFilter(item models.TodoItem, filter models.ItemFilter, now time.Time) bool {
+	if filter.ProjectID != nil && (item.ProjectID == nil || *item.ProjectID != *filter.ProjectID) {
+		return false
+	}
+	if filter.ParentID != nil && (item.ParentID == nil || *item.ParentID != *filter.ParentID) {
+		return false
+	}
+	if len(filter.IDs) > 0 && !slices.Contains(filter.IDs, item.ID) {
+		return false
+	}
+	if len(filter.ParentIDs) > 0 && (item.ParentID == nil || !slices.Contains(filter.ParentIDs, *item.ParentID)) {
+		return false
+	}
+	if filter.Overdue != nil && item.IsOverdue(now) != *filter.Overdue {
+		return false
+	}
+	if filter.DueBefore != nil {
+		if item.DueAt == nil {
+			return false
+		}
+		dueAt, err := models.ParseTimestamp(*item.DueAt)
+		if err != nil || !dueAt.Before(*filter.DueBefore) {
+			return false
+		}
+	}
+	if filter.AssigneeID != nil && (item.AssigneeID == nil || *item.AssigneeID != *filter.AssigneeID) {
+		return false
+	}
+	if filter.Unassigned && item.AssigneeID != nil {
+		return false
+	}
+	if filter.CreatedBy != nil && (item.CreatedBy == nil || *item.CreatedBy != *filter.CreatedBy) {
+		return false
+	}
+	if filter.InvolvedUserID != nil && !item.InvolvesUser(*filter.InvolvedUserID) {
+		return false
+	}
+	if len(filter.Priorities) > 0 && !slices.Contains(filter.Priorities, item.Priority) {
+		return false
+	}
+	if len(filter.Tags) > 0 {
+		matched := 0
+		for _, tag := range filter.Tags {
+			if slices.Contains(item.Tags, tag) {
+				matched++
+			}
+		}
+		if matched == 0 || (filter.TagMatchAll && matched < len(filter.Tags)) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortItems ordena en memoria igual que el ORDER BY del repositorio MySQL
+func sortItems(items []models.TodoItem, filter models.ItemFilter) {
+	numericID := func(item models.TodoItem) int {
+		id, _ := strconv.Atoi(item.ID)
+		return id
+	}
+	optional := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		var cmp int
+		switch filter.SortBy {
+		case models.SortByPriority:
+			cmp = constants.PriorityRank(a.Priority) - constants.PriorityRank(b.Priority)
+		case models.SortByTitle:
+			cmp = strings.Compare(a.Title, b.Title)
+		case models.SortByDueAt:
+			cmp = strings.Compare(optional(a.DueAt), optional(b.DueAt))
+		case models.SortByCreatedAt:
+			cmp = strings.Compare(a.CreatedAt, b.CreatedAt)
+		}
+		if filter.SortDesc {
+			cmp = -cmp
+		}
+		if cmp == 0 {
+			return numericID(a) < numericID(b)
+		}
+		return cmp < 0
+	})
+}
+
+func (r *MockRepository) GetAll(filter models.ItemFilter) ([]models.TodoItem, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -41,12 +213,14 @@ func (r *MockRepository) GetAll() ([]models.TodoItem, error) {
 		return nil, errors.New("simulated database error")
 	}
 
+	now := time.Now()
 	items := make([]models.TodoItem, 0, len(r.items))
-	for _, item := range r.items {
-		if item.DeletedAt == nil {
-			items = append(items, item)
+	for id, item := range r.items {
+		if item.DeletedAt == nil && belongs(r.itemTenants, id, r.meta.Tenant()) && matchesFilter(item, filter, now) {
+			items = append(items, r.withDetails(item))
 		}
 	}
+	sortItems(items, filter)
 	return items, nil
 }
 
@@ -58,12 +232,11 @@ func (r *MockRepository) Get(id int) (models.TodoItem, error) {
 		return models.TodoItem{}, errors.New("simulated database error")
 	}
 
-	idStr := strconv.Itoa(id)
-	item, exists := r.items[idStr]
+	item, exists := r.tenantItem(strconv.Itoa(id))
 	if !exists || item.DeletedAt != nil {
 		return models.TodoItem{}, sql.ErrNoRows
 	}
-	return item, nil
+	return r.withDetails(item), nil
 }
 
 func (r *MockRepository) Create(item models.TodoItem) (models.TodoItem, error) {
@@ -74,9 +247,41 @@ func (r *MockRepository) Create(item models.TodoItem) (models.TodoItem, error) {
 		return models.TodoItem{}, errors.New("simulated database error")
 	}
 
+	tenantID := r.meta.Tenant()
+	if item.ProjectID != nil {
+		if err := r.checkProjectWritable(tenantID, *item.ProjectID); err != nil {
+			return models.TodoItem{}, err
+		}
+	}
+	if !policy.Can(r.meta, constants.PermissionWrite, item.ProjectID) {
+		return models.TodoItem{}, ErrPermissionDenied
+	}
+	if err := r.checkItemQuota(tenantID); err != nil {
+		return models.TodoItem{}, err
+	}
+
+	if item.ParentID != nil {
+		if parent, exists := r.tenantItem(*item.ParentID); !exists || parent.DeletedAt != nil {
+			return models.TodoItem{}, ErrParentNotFound
+		}
+	}
+	if item.AssigneeID != nil {
+		if err := r.checkAssigneeExists(tenantID, *item.AssigneeID); err != nil {
+			return models.TodoItem{}, err
+		}
+	}
+
 	item.ID = fmt.Sprintf("%d", r.nextID)
 	r.nextID++
+	if item.Tags == nil {
+		item.Tags = []string{}
+	}
+	item.BlockedBy = []string{}
+	item.Progress = nil
+	r.ensureTags(tenantID, item.Tags)
 	r.items[item.ID] = item
+	r.itemTenants[item.ID] = tenantID
+	r.recordEvent(item.ID, constants.EventCreated, models.DiffItems(nil, item))
 	return item, nil
 }
 
@@ -88,10 +293,212 @@ func (r *MockRepository) Update(item models.TodoItem) error {
 		return errors.New("simulated database error")
 	}
 
-	if _, exists := r.items[item.ID]; !exists {
-		return sql.ErrNoRows
+	current, err := r.modifiable(item.ID)
+	if err != nil {
+		return err
+	}
+	// Una prioridad vacía conserva la actual
+	if item.Priority == "" {
+		item.Priority = current.Priority
+	}
+	// El proyecto, el padre, el creador y el responsable solo cambian con
+	// MoveItem, SetParent y AssignItem
+	item.ProjectID = current.ProjectID
+	item.ParentID = current.ParentID
+	item.CreatedBy = current.CreatedBy
+	item.AssigneeID = current.AssigneeID
+	// Tags nil conserva las etiquetas actuales
+	if item.Tags == nil {
+		item.Tags = current.Tags
 	}
+	r.ensureTags(r.meta.Tenant(), item.Tags)
 	r.items[item.ID] = item
+	r.recordChanges(current, item)
+	return nil
+}
+
+func (r *MockRepository) MoveItem(id int, projectID *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	if projectID != nil {
+		if err := r.checkProjectWritable(r.meta.Tenant(), *projectID); err != nil {
+			return err
+		}
+		if !policy.Can(r.meta, constants.PermissionWrite, projectID) {
+			return ErrPermissionDenied
+		}
+	}
+
+	idStr := strconv.Itoa(id)
+	item, err := r.modifiable(idStr)
+	if err != nil {
+		return err
+	}
+	current := item
+	item.ProjectID = projectID
+	r.items[idStr] = item
+	r.recordChanges(current, item)
+	return nil
+}
+
+// modifiable devuelve el item (no eliminado) del tenant de r.meta si r.meta
+// puede modificarlo, ver authorizeChange. Requiere r.mu tomado.
+func (r *MockRepository) modifiable(id string) (models.TodoItem, error) {
+	item, exists := r.tenantItem(id)
+	if !exists || item.DeletedAt != nil {
+		return models.TodoItem{}, sql.ErrNoRows
+	}
+	if err := authorizeChange(r.meta, constants.PermissionWrite, item); err != nil {
+		return models.TodoItem{}, err
+	}
+	return item, nil
+}
+
+// checkAssigneeExists verifica que el usuario responsable exista en el tenant. Requiere r.mu tomado.
+func (r *MockRepository) checkAssigneeExists(tenantID string, assigneeID string) error {
+	id, err := strconv.Atoi(assigneeID)
+	user, exists := r.users[id]
+	if err != nil || !exists || scopeTenant(user.TenantID) != tenantID {
+		return ErrAssigneeNotFound
+	}
+	return nil
+}
+
+func (r *MockRepository) AssignItem(id int, assigneeID *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	idStr := strconv.Itoa(id)
+	item, err := r.modifiable(idStr)
+	if err != nil {
+		return err
+	}
+	if assigneeID != nil {
+		if err := r.checkAssigneeExists(r.meta.Tenant(), *assigneeID); err != nil {
+			return err
+		}
+	}
+	current := item
+	item.AssigneeID = assigneeID
+	r.items[idStr] = item
+	r.recordChanges(current, item)
+	return nil
+}
+
+// withDetails completa los campos calculados del item. Requiere r.mu tomado.
+func (r *MockRepository) withDetails(item models.TodoItem) models.TodoItem {
+	item = r.withProgress(item)
+	item.BlockedBy = []string{}
+	for _, blockerID := range r.dependencies[item.ID] {
+		if blocker, exists := r.items[blockerID]; exists && blocker.DeletedAt == nil {
+			item.BlockedBy = append(item.BlockedBy, blockerID)
+		}
+	}
+	return item
+}
+
+// withProgress calcula el progreso del item a partir de sus subtareas directas. Requiere r.mu tomado.
+func (r *MockRepository) withProgress(item models.TodoItem) models.TodoItem {
+	total, completed := 0, 0
+	for _, child := range r.items {
+		if child.DeletedAt == nil && child.ParentID != nil && *child.ParentID == item.ID {
+			total++
+			if child.State == constants.StateCompleted {
+				completed++
+			}
+		}
+	}
+	item.Progress = models.ComputeProgress(completed, total)
+	return item
+}
+
+// checkHierarchy verifica que colgar el item de parentID no genere ciclos ni
+// supere maxDepth niveles (0 sin límite). Requiere r.mu tomado.
+func (r *MockRepository) checkHierarchy(item models.TodoItem, parentID string, maxDepth int) error {
+	depth := 0
+	current := &parentID
+	for current != nil && depth < MaxAncestors {
+		if *current == item.ID {
+			return ErrHierarchyCycle
+		}
+		ancestor, exists := r.tenantItem(*current)
+		if !exists || ancestor.DeletedAt != nil {
+			return ErrParentNotFound
+		}
+		depth++
+		current = ancestor.ParentID
+	}
+
+	if maxDepth > 0 && depth+models.BuildTree(item, r.subtree(item.ID)).Height() > maxDepth {
+		return ErrMaxDepth
+	}
+	return nil
+}
+
+func (r *MockRepository) GetSubtree(id int) ([]models.TodoItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	root, exists := r.tenantItem(strconv.Itoa(id))
+	if !exists || root.DeletedAt != nil {
+		return nil, sql.ErrNoRows
+	}
+	return r.subtree(root.ID), nil
+}
+
+// subtree devuelve los descendientes no eliminados del item. Requiere r.mu tomado.
+func (r *MockRepository) subtree(rootID string) []models.TodoItem {
+	items := []models.TodoItem{}
+	pending := []string{rootID}
+	for len(pending) > 0 {
+		parentID := pending[0]
+		pending = pending[1:]
+		for _, child := range r.items {
+			if child.DeletedAt == nil && child.ParentID != nil && *child.ParentID == parentID {
+				items = append(items, r.withDetails(child))
+				pending = append(pending, child.ID)
+			}
+		}
+	}
+	sortItems(items, models.ItemFilter{})
+	return items
+}
+
+func (r *MockRepository) SetParent(id int, parentID *string, maxDepth int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	idStr := strconv.Itoa(id)
+	item, err := r.modifiable(idStr)
+	if err != nil {
+		return err
+	}
+	if parentID != nil {
+		if err := r.checkHierarchy(item, *parentID, maxDepth); err != nil {
+			return err
+		}
+	}
+	current := item
+	item.ParentID = parentID
+	r.items[idStr] = item
+	r.recordChanges(current, item)
 	return nil
 }
 
@@ -103,12 +510,26 @@ func (r *MockRepository) Delete(id string) error {
 		return errors.New("simulated database error")
 	}
 
-	item, exists := r.items[id]
-	if !exists {
-		return sql.ErrNoRows
+	item, err := r.modifiable(id)
+	if err != nil {
+		return err
 	}
-	deleted := "deleted"
+	if err := authorizeChange(r.meta, constants.PermissionDelete, item); err != nil {
+		return err
+	}
+	deleted := time.Now().UTC().Format(time.RFC3339)
 	item.DeletedAt = &deleted
 	r.items[id] = item
+	r.recordEvent(id, constants.EventDeleted, map[string]models.FieldChange{})
+
+	// Las subtareas de un item eliminado pasan a ser items raíz
+	for childID, child := range r.items {
+		if child.ParentID != nil && *child.ParentID == id {
+			current := child
+			child.ParentID = nil
+			r.items[childID] = child
+			r.recordChanges(current, child)
+		}
+	}
 	return nil
 }
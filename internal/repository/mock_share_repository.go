@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+// ExpireShare adelanta el vencimiento del compartido a now (solo existe en el
+// mock, para probar los vencimientos sin esperarlos)
+func (r *MockRepository) ExpireShare(id string, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shareID, _ := strconv.Atoi(id)
+	share := r.shares[shareID]
+	expiresAt := now.UTC().Format(time.RFC3339)
+	share.ExpiresAt = &expiresAt
+	r.shares[shareID] = share
+}
+
+func (r *MockRepository) SharesForTenant(tenantID string) IShareRepository {
+	return r.forTenant(tenantID)
+}
+
+// sharesWhere devuelve, ordenados por ID, los compartidos que cumplen match. Requiere r.mu tomado.
+func (r *MockRepository) sharesWhere(match func(share models.Share) bool) []models.Share {
+	ids := make([]int, 0, len(r.shares))
+	for id, share := range r.shares {
+		if match(share) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	shares := make([]models.Share, 0, len(ids))
+	for _, id := range ids {
+		shares = append(shares, r.shares[id])
+	}
+	return shares
+}
+
+func (r *MockRepository) GetItemShares(itemID int) ([]models.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	id := strconv.Itoa(itemID)
+	return r.sharesWhere(func(share models.Share) bool {
+		return share.TenantID == r.tenant() && share.ItemID != nil && *share.ItemID == id
+	}), nil
+}
+
+func (r *MockRepository) GetProjectShares(projectID int) ([]models.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	id := strconv.Itoa(projectID)
+	return r.sharesWhere(func(share models.Share) bool {
+		return share.TenantID == r.tenant() && share.ProjectID != nil && *share.ProjectID == id
+	}), nil
+}
+
+func (r *MockRepository) GetShare(id int) (models.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.Share{}, errors.New("simulated database error")
+	}
+
+	share, exists := r.shares[id]
+	if !exists || share.TenantID != r.tenant() {
+		return models.Share{}, sql.ErrNoRows
+	}
+	return share, nil
+}
+
+func (r *MockRepository) GetShareByHash(hash string) (models.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.Share{}, errors.New("simulated database error")
+	}
+
+	for _, share := range r.shares {
+		if share.Hash != "" && share.Hash == hash {
+			return share, nil
+		}
+	}
+	return models.Share{}, sql.ErrNoRows
+}
+
+func (r *MockRepository) CreateShare(share models.Share) (models.Share, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.Share{}, errors.New("simulated database error")
+	}
+
+	if share.UserID != nil {
+		userID, _ := strconv.Atoi(*share.UserID)
+		if user, exists := r.users[userID]; !exists || scopeTenant(user.TenantID) != r.tenant() {
+			return models.Share{}, ErrUserNotFound
+		}
+	}
+
+	share.ID = strconv.Itoa(r.nextShareID)
+	share.TenantID = r.tenant()
+	share.Token = ""
+	share.RevokedAt = nil
+	share.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	share.UpdatedAt = share.CreatedAt
+	r.shares[r.nextShareID] = share
+	r.nextShareID++
+	return share, nil
+}
+
+func (r *MockRepository) RevokeShare(id int) (models.Share, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.Share{}, errors.New("simulated database error")
+	}
+
+	share, exists := r.shares[id]
+	if !exists || share.TenantID != r.tenant() {
+		return models.Share{}, sql.ErrNoRows
+	}
+	if share.RevokedAt != nil {
+		return models.Share{}, ErrShareRevoked
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	share.RevokedAt = &now
+	share.UpdatedAt = now
+	r.shares[id] = share
+	return share, nil
+}
+
+func (r *MockRepository) GetUserShares(userID string) ([]models.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	now := time.Now()
+	return r.sharesWhere(func(share models.Share) bool {
+		return share.UserID != nil && *share.UserID == userID && share.Active(now)
+	}), nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+// ensureTags registra en el tenant las etiquetas que aún no existen. Requiere r.mu tomado.
+func (r *MockRepository) ensureTags(tenantID string, names []string) {
+	for _, name := range names {
+		if _, exists := r.findTagByName(tenantID, name); !exists {
+			id := r.nextTagID
+			r.nextTagID++
+			r.tags[id] = models.Tag{ID: strconv.Itoa(id), Name: name}
+			r.tagTenants[id] = tenantID
+		}
+	}
+}
+
+func (r *MockRepository) findTagByName(tenantID string, name string) (models.Tag, bool) {
+	for id, tag := range r.tags {
+		if tag.Name == name && belongs(r.tagTenants, id, tenantID) {
+			return tag, true
+		}
+	}
+	return models.Tag{}, false
+}
+
+// tenantTag devuelve la etiqueta si es del tenant de la vista. Requiere r.mu tomado.
+func (r *MockRepository) tenantTag(id int) (models.Tag, bool) {
+	tag, exists := r.tags[id]
+	if !exists || !belongs(r.tagTenants, id, r.tenant()) {
+		return models.Tag{}, false
+	}
+	return tag, true
+}
+
+// withItemCount completa ItemCount contando los items no eliminados del
+// tenant de la vista. Requiere r.mu tomado.
+func (r *MockRepository) withItemCount(tag models.Tag) models.Tag {
+	tag.ItemCount = 0
+	for id, item := range r.items {
+		if item.DeletedAt == nil && belongs(r.itemTenants, id, r.tenant()) && slices.Contains(item.Tags, tag.Name) {
+			tag.ItemCount++
+		}
+	}
+	return tag
+}
+
+// replaceItemTag cambia (o quita, si newName es vacío) una etiqueta en todos
+// los items del tenant de la vista
+func (r *MockRepository) replaceItemTag(oldName string, newName string) {
+	for id, item := range r.items {
+		if !belongs(r.itemTenants, id, r.tenant()) || !slices.Contains(item.Tags, oldName) {
+			continue
+		}
+		tags := make([]string, 0, len(item.Tags))
+		for _, tag := range item.Tags {
+			if tag == oldName {
+				tag = newName
+			}
+			if tag != "" && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		item.Tags = tags
+		r.items[id] = item
+	}
+}
+
+func (r *MockRepository) GetAllTags() ([]models.Tag, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	tags := make([]models.Tag, 0, len(r.tags))
+	for id, tag := range r.tags {
+		if belongs(r.tagTenants, id, r.tenant()) {
+			tags = append(tags, r.withItemCount(tag))
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+func (r *MockRepository) CreateTag(name string) (models.Tag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.Tag{}, errors.New("simulated database error")
+	}
+
+	if _, exists := r.findTagByName(r.tenant(), name); exists {
+		return models.Tag{}, ErrDuplicateTag
+	}
+	r.ensureTags(r.tenant(), []string{name})
+	tag, _ := r.findTagByName(r.tenant(), name)
+	return tag, nil
+}
+
+func (r *MockRepository) RenameTag(id int, name string) (models.Tag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.Tag{}, errors.New("simulated database error")
+	}
+
+	tag, exists := r.tenantTag(id)
+	if !exists {
+		return models.Tag{}, sql.ErrNoRows
+	}
+	if other, exists := r.findTagByName(r.tenant(), name); exists && other.ID != tag.ID {
+		return models.Tag{}, ErrDuplicateTag
+	}
+
+	r.replaceItemTag(tag.Name, name)
+	tag.Name = name
+	r.tags[id] = tag
+	return r.withItemCount(tag), nil
+}
+
+func (r *MockRepository) MergeTags(sourceID int, targetID int) (models.Tag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.Tag{}, errors.New("simulated database error")
+	}
+
+	source, exists := r.tenantTag(sourceID)
+	if !exists {
+		return models.Tag{}, sql.ErrNoRows
+	}
+	target, exists := r.tenantTag(targetID)
+	if !exists {
+		return models.Tag{}, sql.ErrNoRows
+	}
+
+	r.replaceItemTag(source.Name, target.Name)
+	delete(r.tags, sourceID)
+	return r.withItemCount(target), nil
+}
+
+func (r *MockRepository) DeleteTag(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	tag, exists := r.tenantTag(id)
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	r.replaceItemTag(tag.Name, "")
+	delete(r.tags, id)
+	return nil
+}
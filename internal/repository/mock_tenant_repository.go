@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+// belongs indica si la entidad key es del tenant. Las entidades sin tenant
+// registrado son del tenant por defecto, como con DEFAULT 1 en MySQL.
+func belongs[K comparable](tenants map[K]string, key K, tenantID string) bool {
+	return scopeTenant(tenants[key]) == tenantID
+}
+
+// AddTenant registra un tenant (solo existe en el mock; en MySQL los tenants se
+// dan de alta por migración o a mano) y lo devuelve con su ID
+func (r *MockRepository) AddTenant(tenant models.Tenant) models.Tenant {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tenant.ID = strconv.Itoa(r.nextTenantID)
+	r.nextTenantID++
+	tenant.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	tenant.UpdatedAt = tenant.CreatedAt
+	r.tenants[tenant.ID] = tenant
+	return tenant
+}
+
+func (r *MockRepository) GetTenant(id string) (models.Tenant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.Tenant{}, errors.New("simulated database error")
+	}
+
+	tenant, exists := r.tenants[id]
+	if !exists {
+		return models.Tenant{}, sql.ErrNoRows
+	}
+	return tenant, nil
+}
+
+func (r *MockRepository) GetTenantBySlug(slug string) (models.Tenant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.Tenant{}, errors.New("simulated database error")
+	}
+
+	for _, tenant := range r.tenants {
+		if tenant.Slug == slug {
+			return tenant, nil
+		}
+	}
+	return models.Tenant{}, sql.ErrNoRows
+}
+
+func (r *MockRepository) GetTenantUsage(id string) (models.TenantUsage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.TenantUsage{}, errors.New("simulated database error")
+	}
+
+	return r.usage(id), nil
+}
+
+// usage cuenta los items (no eliminados) y los proyectos del tenant. Requiere r.mu tomado.
+func (r *MockRepository) usage(tenantID string) models.TenantUsage {
+	var usage models.TenantUsage
+	for id, item := range r.items {
+		if item.DeletedAt == nil && belongs(r.itemTenants, id, tenantID) {
+			usage.Items++
+		}
+	}
+	for id := range r.projects {
+		if belongs(r.projectTenants, id, tenantID) {
+			usage.Projects++
+		}
+	}
+	return usage
+}
+
+// checkItemQuota replica la validación del repositorio MySQL. Requiere r.mu tomado.
+func (r *MockRepository) checkItemQuota(tenantID string) error {
+	tenant, exists := r.tenants[tenantID]
+	if !exists {
+		return ErrTenantNotFound
+	}
+	if models.Exceeds(tenant.MaxItems, r.usage(tenantID).Items) {
+		return ErrItemQuotaExceeded
+	}
+	return nil
+}
+
+// checkProjectQuota replica la validación del repositorio MySQL. Requiere r.mu tomado.
+func (r *MockRepository) checkProjectQuota(tenantID string) error {
+	tenant, exists := r.tenants[tenantID]
+	if !exists {
+		return ErrTenantNotFound
+	}
+	if models.Exceeds(tenant.MaxProjects, r.usage(tenantID).Projects) {
+		return ErrProjectQuotaExceeded
+	}
+	return nil
+}
+
+// tenantItem devuelve el item (incluso eliminado) si es del tenant de r.meta. Requiere r.mu tomado.
+func (r *MockRepository) tenantItem(id string) (models.TodoItem, bool) {
+	item, exists := r.items[id]
+	if !exists || !belongs(r.itemTenants, id, r.meta.Tenant()) {
+		return models.TodoItem{}, false
+	}
+	return item, true
+}
+
+// tenant es el tenant de la vista XForTenant, o el tenant por defecto
+func (r *MockRepository) tenant() string {
+	return scopeTenant(r.tenantID)
+}
+
+// forTenant devuelve una vista sobre los mismos datos limitada al tenant
+func (r *MockRepository) forTenant(tenantID string) *MockRepository {
+	return &MockRepository{mockStore: r.mockStore, meta: r.meta, tenantID: tenantID}
+}
+
+func (r *MockRepository) ProjectsForTenant(tenantID string) IProjectRepository {
+	return r.forTenant(tenantID)
+}
+
+func (r *MockRepository) TagsForTenant(tenantID string) ITagRepository {
+	return r.forTenant(tenantID)
+}
+
+func (r *MockRepository) WebhooksForTenant(tenantID string) IWebhookRepository {
+	return r.forTenant(tenantID)
+}
+
+func (r *MockRepository) APIKeysForTenant(tenantID string) IAPIKeyRepository {
+	return r.forTenant(tenantID)
+}
+
+// defaultTenant es el tenant que crea la migración; admite el registro
+var defaultTenant = models.Tenant{ID: constants.DefaultTenantID, Slug: "default", Name: "Default", AllowRegistration: true}
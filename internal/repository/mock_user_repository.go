@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+func (r *MockRepository) CreateUser(user models.User) (models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.User{}, errors.New("simulated database error")
+	}
+
+	for _, existing := range r.users {
+		if existing.Email == user.Email {
+			return models.User{}, ErrDuplicateEmail
+		}
+	}
+	if user.Role == "" {
+		user.Role = constants.DefaultRole
+	}
+	user.TenantID = scopeTenant(user.TenantID)
+	user.ID = strconv.Itoa(r.nextUserID)
+	user.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	user.UpdatedAt = user.CreatedAt
+	r.users[r.nextUserID] = user
+	r.nextUserID++
+	return user, nil
+}
+
+func (r *MockRepository) GetUser(id int) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.User{}, errors.New("simulated database error")
+	}
+
+	user, exists := r.users[id]
+	if !exists {
+		return models.User{}, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+func (r *MockRepository) GetUserByEmail(email string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.User{}, errors.New("simulated database error")
+	}
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return models.User{}, sql.ErrNoRows
+}
+
+func (r *MockRepository) GetUserByIdentity(issuer string, subject string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.User{}, errors.New("simulated database error")
+	}
+
+	for _, user := range r.users {
+		if user.Issuer == issuer && user.Subject == subject {
+			return user, nil
+		}
+	}
+	return models.User{}, sql.ErrNoRows
+}
+
+func (r *MockRepository) LinkUserIdentity(id string, issuer string, subject string) (models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.User{}, errors.New("simulated database error")
+	}
+
+	userID, _ := strconv.Atoi(id)
+	user, exists := r.users[userID]
+	if !exists {
+		return models.User{}, sql.ErrNoRows
+	}
+	user.Issuer = issuer
+	user.Subject = subject
+	user.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	r.users[userID] = user
+	return user, nil
+}
+
+func (r *MockRepository) SetUserRole(id string, role string) (models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.User{}, errors.New("simulated database error")
+	}
+
+	userID, _ := strconv.Atoi(id)
+	user, exists := r.users[userID]
+	if !exists {
+		return models.User{}, sql.ErrNoRows
+	}
+	user.Role = role
+	user.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	r.users[userID] = user
+	return user, nil
+}
+
+func (r *MockRepository) GetUserProjectRoles(userID string) (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	roles := make(map[string]string)
+	for _, members := range r.projectMembers {
+		for _, member := range members {
+			if member.UserID == userID {
+				roles[member.ProjectID] = member.Role
+			}
+		}
+	}
+	return roles, nil
+}
+
+func (r *MockRepository) CreateRefreshToken(token models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	r.saveRefreshToken(token)
+	return nil
+}
+
+// saveRefreshToken guarda un refresh token nuevo. Requiere r.mu tomado.
+func (r *MockRepository) saveRefreshToken(token models.RefreshToken) {
+	token.ID = strconv.Itoa(len(r.refreshTokens) + 1)
+	token.RevokedAt = nil
+	token.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	r.refreshTokens[token.Hash] = token
+}
+
+func (r *MockRepository) GetRefreshToken(hash string) (models.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.RefreshToken{}, errors.New("simulated database error")
+	}
+
+	token, exists := r.refreshTokens[hash]
+	if !exists {
+		return models.RefreshToken{}, sql.ErrNoRows
+	}
+	return token, nil
+}
+
+func (r *MockRepository) RotateRefreshToken(oldHash string, next models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	old, exists := r.refreshTokens[oldHash]
+	if !exists || old.Revoked() {
+		return ErrTokenReused
+	}
+	r.revokeRefreshToken(old)
+	r.saveRefreshToken(next)
+	return nil
+}
+
+// revokeRefreshToken marca el token como revocado. Requiere r.mu tomado.
+func (r *MockRepository) revokeRefreshToken(token models.RefreshToken) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	token.RevokedAt = &now
+	r.refreshTokens[token.Hash] = token
+}
+
+func (r *MockRepository) RevokeRefreshToken(hash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	if token, exists := r.refreshTokens[hash]; exists && !token.Revoked() {
+		r.revokeRefreshToken(token)
+	}
+	return nil
+}
+
+func (r *MockRepository) RevokeUserRefreshTokens(userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	for _, token := range r.refreshTokens {
+		if token.UserID == userID && !token.Revoked() {
+			r.revokeRefreshToken(token)
+		}
+	}
+	return nil
+}
+
+func (r *MockRepository) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	r.revokedTokens[jti] = expiresAt
+	return nil
+}
+
+func (r *MockRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return false, errors.New("simulated database error")
+	}
+
+	_, revoked := r.revokedTokens[jti]
+	return revoked, nil
+}
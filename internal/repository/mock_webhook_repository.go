@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+func (r *MockRepository) GetAllWebhooks() ([]models.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	return r.sortedWebhooks(r.tenant()), nil
+}
+
+// sortedWebhooks devuelve los webhooks del tenant ordenados por ID. Requiere r.mu tomado.
+func (r *MockRepository) sortedWebhooks(tenantID string) []models.Webhook {
+	ids := make([]int, 0, len(r.webhooks))
+	for id := range r.webhooks {
+		if belongs(r.webhookTenants, id, tenantID) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	webhooks := make([]models.Webhook, 0, len(ids))
+	for _, id := range ids {
+		webhooks = append(webhooks, r.webhooks[id])
+	}
+	return webhooks
+}
+
+func (r *MockRepository) GetWebhook(id int) (models.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return models.Webhook{}, errors.New("simulated database error")
+	}
+
+	webhook, exists := r.webhooks[id]
+	if !exists || !belongs(r.webhookTenants, id, r.tenant()) {
+		return models.Webhook{}, sql.ErrNoRows
+	}
+	return webhook, nil
+}
+
+func (r *MockRepository) CreateWebhook(webhook models.Webhook) (models.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.Webhook{}, errors.New("simulated database error")
+	}
+
+	webhook.ID = strconv.Itoa(r.nextWebhookID)
+	webhook.Events = webhookEvents(webhook)
+	webhook.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	webhook.UpdatedAt = webhook.CreatedAt
+	r.webhooks[r.nextWebhookID] = webhook
+	r.webhookTenants[r.nextWebhookID] = r.tenant()
+	r.nextWebhookID++
+	return webhook, nil
+}
+
+func (r *MockRepository) UpdateWebhook(webhook models.Webhook) (models.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.Webhook{}, errors.New("simulated database error")
+	}
+
+	id, _ := strconv.Atoi(webhook.ID)
+	current, exists := r.webhooks[id]
+	if !exists || !belongs(r.webhookTenants, id, r.tenant()) {
+		return models.Webhook{}, sql.ErrNoRows
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = current.Secret
+	}
+	webhook.Events = webhookEvents(webhook)
+	webhook.CreatedAt = current.CreatedAt
+	webhook.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	r.webhooks[id] = webhook
+	return webhook, nil
+}
+
+func (r *MockRepository) DeleteWebhook(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	if _, exists := r.webhooks[id]; !exists || !belongs(r.webhookTenants, id, r.tenant()) {
+		return sql.ErrNoRows
+	}
+	delete(r.webhooks, id)
+
+	webhookID := strconv.Itoa(id)
+	for deliveryID, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+// withWebhook completa la URL y el secreto del webhook de la entrega. Requiere r.mu tomado.
+func (r *MockRepository) withWebhook(delivery models.WebhookDelivery) models.WebhookDelivery {
+	id, _ := strconv.Atoi(delivery.WebhookID)
+	webhook := r.webhooks[id]
+	delivery.URL = webhook.URL
+	delivery.Secret = webhook.Secret
+	return delivery
+}
+
+// deliveryInTenant indica si la entrega es de un webhook del tenant de la vista. Requiere r.mu tomado.
+func (r *MockRepository) deliveryInTenant(delivery models.WebhookDelivery) bool {
+	id, _ := strconv.Atoi(delivery.WebhookID)
+	return belongs(r.webhookTenants, id, r.tenant())
+}
+
+// sortedDeliveryIDs devuelve los IDs de las entregas en orden. Requiere r.mu tomado.
+func (r *MockRepository) sortedDeliveryIDs() []int {
+	ids := make([]int, 0, len(r.deliveries))
+	for id := range r.deliveries {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (r *MockRepository) GetDeliveries(filter DeliveryFilter, page int, pageSize int) ([]models.WebhookDelivery, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.simulateError {
+		return nil, 0, errors.New("simulated database error")
+	}
+
+	// De la más reciente a la más antigua, como el repositorio MySQL
+	ids := r.sortedDeliveryIDs()
+	deliveries := []models.WebhookDelivery{}
+	for i := len(ids) - 1; i >= 0; i-- {
+		delivery := r.deliveries[ids[i]]
+		if !r.deliveryInTenant(delivery) {
+			continue
+		}
+		if filter.WebhookID != "" && delivery.WebhookID != filter.WebhookID {
+			continue
+		}
+		if filter.Status != "" && delivery.Status != filter.Status {
+			continue
+		}
+		deliveries = append(deliveries, r.withWebhook(delivery))
+	}
+
+	total := len(deliveries)
+	start := min((page-1)*pageSize, total)
+	end := min(start+pageSize, total)
+	return deliveries[start:end], total, nil
+}
+
+func (r *MockRepository) Redeliver(id int) (models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return models.WebhookDelivery{}, errors.New("simulated database error")
+	}
+
+	delivery, exists := r.deliveries[id]
+	if !exists || !r.deliveryInTenant(delivery) {
+		return models.WebhookDelivery{}, sql.ErrNoRows
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	delivery.Status = constants.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	r.deliveries[id] = delivery
+	return r.withWebhook(delivery), nil
+}
+
+func (r *MockRepository) FanOutOutbox(limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return 0, errors.New("simulated database error")
+	}
+
+	count := min(limit, len(r.outbox))
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	// Cada evento se reparte solo a los webhooks de su tenant
+	for _, event := range r.outbox[:count] {
+		for _, webhook := range r.sortedWebhooks(scopeTenant(event.tenantID)) {
+			if !webhook.Accepts(event.event) {
+				continue
+			}
+			next := now
+			r.deliveries[r.nextDeliveryID] = models.WebhookDelivery{
+				ID:            strconv.Itoa(r.nextDeliveryID),
+				WebhookID:     webhook.ID,
+				Event:         event.event,
+				Payload:       event.payload,
+				Status:        constants.DeliveryPending,
+				NextAttemptAt: &next,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			}
+			r.nextDeliveryID++
+		}
+	}
+	r.outbox = r.outbox[count:]
+	return count, nil
+}
+
+func (r *MockRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return nil, errors.New("simulated database error")
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	for _, id := range r.sortedDeliveryIDs() {
+		if len(deliveries) == limit {
+			break
+		}
+		delivery := r.deliveries[id]
+		if delivery.Status != constants.DeliveryPending || delivery.NextAttemptAt == nil {
+			continue
+		}
+		if next, err := models.ParseTimestamp(*delivery.NextAttemptAt); err != nil || next.After(now) {
+			continue
+		}
+		leased := now.Add(lease).UTC().Format(time.RFC3339Nano)
+		delivery.NextAttemptAt = &leased
+		r.deliveries[id] = delivery
+		deliveries = append(deliveries, r.withWebhook(delivery))
+	}
+	return deliveries, nil
+}
+
+func (r *MockRepository) CompleteDelivery(id string, statusCode int) error {
+	return r.finishAttempt(id, func(delivery *models.WebhookDelivery) {
+		delivery.Status = constants.DeliveryDelivered
+		delivery.NextAttemptAt = nil
+		delivery.LastStatusCode = &statusCode
+		delivery.LastError = ""
+	})
+}
+
+func (r *MockRepository) FailDelivery(id string, statusCode *int, errMsg string, nextAttempt *time.Time) error {
+	return r.finishAttempt(id, func(delivery *models.WebhookDelivery) {
+		delivery.Status = constants.DeliveryDead
+		delivery.NextAttemptAt = nil
+		if nextAttempt != nil {
+			next := nextAttempt.UTC().Format(time.RFC3339Nano)
+			delivery.Status = constants.DeliveryPending
+			delivery.NextAttemptAt = &next
+		}
+		delivery.LastStatusCode = statusCode
+		delivery.LastError = errMsg
+	})
+}
+
+// finishAttempt registra un intento de entrega aplicando update sobre la entrega
+func (r *MockRepository) finishAttempt(id string, update func(delivery *models.WebhookDelivery)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.simulateError {
+		return errors.New("simulated database error")
+	}
+
+	deliveryID, _ := strconv.Atoi(id)
+	delivery, exists := r.deliveries[deliveryID]
+	if !exists {
+		return sql.ErrNoRows
+	}
+	delivery.Attempts++
+	delivery.UpdatedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	update(&delivery)
+	r.deliveries[deliveryID] = delivery
+	return nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/go-sql-driver/mysql"
+)
+
+const projectMemberColumns = "project_id, user_id, role, created_at, updated_at"
+
+func scanProjectMember(row rowScanner) (models.ProjectMember, error) {
+	var member models.ProjectMember
+	err := row.Scan(&member.ProjectID, &member.UserID, &member.Role, &member.CreatedAt, &member.UpdatedAt)
+	return member, err
+}
+
+// project_members no tiene tenant_id: las consultas lo toman del proyecto
+
+func (r *ProjectMySqlRepository) GetProjectMembers(projectID int) ([]models.ProjectMember, error) {
+	rows, err := r.db.Query("SELECT "+projectMemberColumns+" FROM project_members WHERE project_id = ? AND project_id IN (SELECT id FROM projects WHERE tenant_id = ?) ORDER BY user_id", projectID, scopeTenant(r.tenantID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.ProjectMember{}
+	for rows.Next() {
+		member, err := scanProjectMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+func (r *ProjectMySqlRepository) SetProjectMember(member models.ProjectMember) (models.ProjectMember, error) {
+	// Solo los usuarios del tenant del proyecto pueden ser miembros
+	var exists int
+	err := r.db.QueryRow("SELECT 1 FROM users WHERE id = ? AND tenant_id = ?", member.UserID, scopeTenant(r.tenantID)).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ProjectMember{}, ErrUserNotFound
+	}
+	if err != nil {
+		return models.ProjectMember{}, err
+	}
+
+	_, err = r.db.Exec("INSERT INTO project_members (project_id, user_id, role) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE role = VALUES(role)",
+		member.ProjectID, member.UserID, member.Role)
+	if err != nil {
+		// 1452 es una clave foránea inexistente; el proyecto ya se verificó en el handler
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1452 {
+			return models.ProjectMember{}, ErrUserNotFound
+		}
+		return models.ProjectMember{}, err
+	}
+	return scanProjectMember(r.db.QueryRow("SELECT "+projectMemberColumns+" FROM project_members WHERE project_id = ? AND user_id = ?", member.ProjectID, member.UserID))
+}
+
+func (r *ProjectMySqlRepository) RemoveProjectMember(projectID int, userID int) error {
+	result, err := r.db.Exec("DELETE FROM project_members WHERE project_id = ? AND user_id = ? AND project_id IN (SELECT id FROM projects WHERE tenant_id = ?)", projectID, userID, scopeTenant(r.tenantID))
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
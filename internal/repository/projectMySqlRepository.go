@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+const projectColumns = "id, name, description, archived_at, created_at, updated_at"
+
+type ProjectMySqlRepository struct {
+	db *sql.DB
+	// tenantID limita los proyectos a un tenant, ver ProjectsForTenant
+	tenantID string
+}
+
+func NewProjectMySqlRepository(db *sql.DB) *ProjectMySqlRepository {
+	return &ProjectMySqlRepository{db: db}
+}
+
+// ProjectsForTenant devuelve una copia del repositorio limitada a los proyectos del tenant
+func (r *ProjectMySqlRepository) ProjectsForTenant(tenantID string) IProjectRepository {
+	scoped := *r
+	scoped.tenantID = tenantID
+	return &scoped
+}
+
+func scanProject(row rowScanner) (models.Project, error) {
+	var project models.Project
+	var description sql.NullString
+	err := row.Scan(&project.ID, &project.Name, &description, &project.ArchivedAt, &project.CreatedAt, &project.UpdatedAt)
+	project.Description = description.String
+	project.Archived = project.ArchivedAt != nil
+	project.Counts = models.NewStateCounts()
+	return project, err
+}
+
+// loadCounts completa los conteos por estado de los proyectos con una sola consulta
+func (r *ProjectMySqlRepository) loadCounts(projects []models.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(projects))
+	args := make([]any, 0, len(projects))
+	placeholders := ""
+	for i := range projects {
+		index[projects[i].ID] = i
+		args = append(args, projects[i].ID)
+		if i > 0 {
+			placeholders += ", "
+		}
+		placeholders += "?"
+	}
+
+	rows, err := r.db.Query("SELECT project_id, state, COUNT(*) FROM todo_items WHERE deleted_at IS NULL AND project_id IN ("+placeholders+") GROUP BY project_id, state", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var projectID, state string
+		var count int
+		if err := rows.Scan(&projectID, &state, &count); err != nil {
+			return err
+		}
+		if i, ok := index[projectID]; ok {
+			projects[i].Counts[state] = count
+		}
+	}
+	return rows.Err()
+}
+
+func (r *ProjectMySqlRepository) GetAllProjects(includeArchived bool) ([]models.Project, error) {
+	query := "SELECT " + projectColumns + " FROM projects WHERE tenant_id = ?"
+	if !includeArchived {
+		query += " AND archived_at IS NULL"
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Query(query, scopeTenant(r.tenantID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadCounts(projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func (r *ProjectMySqlRepository) GetProject(id int) (models.Project, error) {
+	project, err := scanProject(r.db.QueryRow("SELECT "+projectColumns+" FROM projects WHERE id = ? AND tenant_id = ?", id, scopeTenant(r.tenantID)))
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	projects := []models.Project{project}
+	if err := r.loadCounts(projects); err != nil {
+		return models.Project{}, err
+	}
+	return projects[0], nil
+}
+
+func (r *ProjectMySqlRepository) GetProjectsByIDs(ids []int) ([]models.Project, error) {
+	projects := []models.Project{}
+	if len(ids) == 0 {
+		return projects, nil
+	}
+
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, scopeTenant(r.tenantID))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := r.db.Query("SELECT "+projectColumns+" FROM projects WHERE tenant_id = ? AND id IN (?"+strings.Repeat(", ?", len(ids)-1)+") ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadCounts(projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func (r *ProjectMySqlRepository) CreateProject(project models.Project) (models.Project, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Project{}, err
+	}
+	defer tx.Rollback()
+
+	tenantID := scopeTenant(r.tenantID)
+	if err := checkProjectQuota(tx, tenantID); err != nil {
+		return models.Project{}, err
+	}
+	result, err := tx.Exec("INSERT INTO projects (tenant_id, name, description) VALUES (?, ?, ?)", tenantID, project.Name, project.Description)
+	if err != nil {
+		return models.Project{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return models.Project{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return models.Project{}, err
+	}
+	return r.GetProject(int(id))
+}
+
+func (r *ProjectMySqlRepository) UpdateProject(project models.Project) (models.Project, error) {
+	id, err := strconv.Atoi(project.ID)
+	if err != nil {
+		return models.Project{}, sql.ErrNoRows
+	}
+	if _, err := r.db.Exec("UPDATE projects SET name = ?, description = ? WHERE id = ? AND tenant_id = ?", project.Name, project.Description, id, scopeTenant(r.tenantID)); err != nil {
+		return models.Project{}, err
+	}
+	// GetProject devuelve sql.ErrNoRows si el proyecto no existe
+	return r.GetProject(id)
+}
+
+func (r *ProjectMySqlRepository) DeleteProject(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRow("SELECT 1 FROM projects WHERE id = ? AND tenant_id = ? FOR UPDATE", id, scopeTenant(r.tenantID)).Scan(&exists); err != nil {
+		return err
+	}
+
+	var items int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM todo_items WHERE project_id = ? AND deleted_at IS NULL", id).Scan(&items); err != nil {
+		return err
+	}
+	if items > 0 {
+		return ErrProjectNotEmpty
+	}
+
+	// Los items eliminados (soft delete) quedan sin proyecto por ON DELETE SET NULL
+	if _, err := tx.Exec("DELETE FROM projects WHERE id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ProjectMySqlRepository) SetProjectArchived(id int, archived bool) (models.Project, error) {
+	query := "UPDATE projects SET archived_at = NULL WHERE id = ? AND tenant_id = ?"
+	if archived {
+		// COALESCE conserva la fecha original si ya estaba archivado
+		query = "UPDATE projects SET archived_at = COALESCE(archived_at, NOW()) WHERE id = ? AND tenant_id = ?"
+	}
+	if _, err := r.db.Exec(query, id, scopeTenant(r.tenantID)); err != nil {
+		return models.Project{}, err
+	}
+	return r.GetProject(id)
+}
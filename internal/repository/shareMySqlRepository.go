@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/go-sql-driver/mysql"
+)
+
+const shareColumns = "id, tenant_id, item_id, project_id, user_id, permission, COALESCE(prefix, ''), COALESCE(token_hash, ''), expires_at, revoked_at, created_by, created_at, updated_at"
+
+type ShareMySqlRepository struct {
+	db *sql.DB
+	// tenantID limita los compartidos a un tenant, ver SharesForTenant
+	tenantID string
+}
+
+func NewShareMySqlRepository(db *sql.DB) *ShareMySqlRepository {
+	return &ShareMySqlRepository{db: db}
+}
+
+// SharesForTenant devuelve una copia del repositorio limitada a los compartidos del tenant
+func (r *ShareMySqlRepository) SharesForTenant(tenantID string) IShareRepository {
+	scoped := *r
+	scoped.tenantID = tenantID
+	return &scoped
+}
+
+func scanShare(row rowScanner) (models.Share, error) {
+	var share models.Share
+	err := row.Scan(&share.ID, &share.TenantID, &share.ItemID, &share.ProjectID, &share.UserID, &share.Permission, &share.Prefix, &share.Hash,
+		&share.ExpiresAt, &share.RevokedAt, &share.CreatedBy, &share.CreatedAt, &share.UpdatedAt)
+	return share, err
+}
+
+// queryShares devuelve los compartidos de la consulta
+func queryShares(db *sql.DB, query string, args ...any) ([]models.Share, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := []models.Share{}
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, rows.Err()
+}
+
+func (r *ShareMySqlRepository) GetItemShares(itemID int) ([]models.Share, error) {
+	return queryShares(r.db, "SELECT "+shareColumns+" FROM shares WHERE item_id = ? AND tenant_id = ? ORDER BY id", itemID, scopeTenant(r.tenantID))
+}
+
+func (r *ShareMySqlRepository) GetProjectShares(projectID int) ([]models.Share, error) {
+	return queryShares(r.db, "SELECT "+shareColumns+" FROM shares WHERE project_id = ? AND tenant_id = ? ORDER BY id", projectID, scopeTenant(r.tenantID))
+}
+
+func (r *ShareMySqlRepository) GetShare(id int) (models.Share, error) {
+	return scanShare(r.db.QueryRow("SELECT "+shareColumns+" FROM shares WHERE id = ? AND tenant_id = ?", id, scopeTenant(r.tenantID)))
+}
+
+// GetShareByHash busca en todos los tenants: el enlace identifica al suyo
+func (r *ShareMySqlRepository) GetShareByHash(hash string) (models.Share, error) {
+	return scanShare(r.db.QueryRow("SELECT "+shareColumns+" FROM shares WHERE token_hash = ?", hash))
+}
+
+func (r *ShareMySqlRepository) CreateShare(share models.Share) (models.Share, error) {
+	tenantID := scopeTenant(r.tenantID)
+	// Solo se comparte con usuarios del tenant
+	if share.UserID != nil {
+		var exists int
+		err := r.db.QueryRow("SELECT 1 FROM users WHERE id = ? AND tenant_id = ?", *share.UserID, tenantID).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Share{}, ErrUserNotFound
+		}
+		if err != nil {
+			return models.Share{}, err
+		}
+	}
+
+	expiresAt, err := nullableTime(share.ExpiresAt)
+	if err != nil {
+		return models.Share{}, err
+	}
+	result, err := r.db.Exec("INSERT INTO shares (tenant_id, item_id, project_id, user_id, permission, prefix, token_hash, expires_at, created_by) VALUES (?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)",
+		tenantID, share.ItemID, share.ProjectID, share.UserID, share.Permission, share.Prefix, share.Hash, expiresAt, share.CreatedBy)
+	if err != nil {
+		// 1452 es una clave foránea inexistente; el item o proyecto ya se verificó en el handler
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1452 {
+			return models.Share{}, ErrUserNotFound
+		}
+		return models.Share{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return models.Share{}, err
+	}
+	return r.GetShare(int(id))
+}
+
+// RevokeShare distingue si el compartido no existe (sql.ErrNoRows) o si ya
+// estaba revocado (ErrShareRevoked)
+func (r *ShareMySqlRepository) RevokeShare(id int) (models.Share, error) {
+	result, err := r.db.Exec("UPDATE shares SET revoked_at = ? WHERE id = ? AND tenant_id = ? AND revoked_at IS NULL", time.Now().UTC(), id, scopeTenant(r.tenantID))
+	if err != nil {
+		return models.Share{}, err
+	}
+	share, err := r.GetShare(id)
+	if err != nil {
+		return models.Share{}, err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return models.Share{}, err
+	} else if affected == 0 {
+		return models.Share{}, ErrShareRevoked
+	}
+	return share, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/go-sql-driver/mysql"
+)
+
+// tagSelect lee las etiquetas junto con la cantidad de items (no eliminados) que las usan
+const tagSelect = `SELECT t.id, t.name, t.created_at, COUNT(i.id)
+	FROM tags t
+	LEFT JOIN todo_item_tags it ON it.tag_id = t.id
+	LEFT JOIN todo_items i ON i.id = it.item_id AND i.deleted_at IS NULL`
+
+type TagMySqlRepository struct {
+	db *sql.DB
+	// tenantID limita las etiquetas a un tenant, ver TagsForTenant
+	tenantID string
+}
+
+func NewTagMySqlRepository(db *sql.DB) *TagMySqlRepository {
+	return &TagMySqlRepository{db: db}
+}
+
+// TagsForTenant devuelve una copia del repositorio limitada a las etiquetas del tenant
+func (r *TagMySqlRepository) TagsForTenant(tenantID string) ITagRepository {
+	scoped := *r
+	scoped.tenantID = tenantID
+	return &scoped
+}
+
+// handleTagError traduce el error de clave única duplicada (1062) a ErrDuplicateTag
+func handleTagError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return ErrDuplicateTag
+	}
+	return err
+}
+
+func (r *TagMySqlRepository) GetAllTags() ([]models.Tag, error) {
+	rows, err := r.db.Query(tagSelect+" WHERE t.tenant_id = ? GROUP BY t.id, t.name, t.created_at ORDER BY t.name", scopeTenant(r.tenantID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt, &tag.ItemCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+func (r *TagMySqlRepository) getTag(id int) (models.Tag, error) {
+	var tag models.Tag
+	err := r.db.QueryRow(tagSelect+" WHERE t.id = ? AND t.tenant_id = ? GROUP BY t.id, t.name, t.created_at", id, scopeTenant(r.tenantID)).Scan(&tag.ID, &tag.Name, &tag.CreatedAt, &tag.ItemCount)
+	return tag, err
+}
+
+func (r *TagMySqlRepository) CreateTag(name string) (models.Tag, error) {
+	result, err := r.db.Exec("INSERT INTO tags (tenant_id, name) VALUES (?, ?)", scopeTenant(r.tenantID), name)
+	if err != nil {
+		return models.Tag{}, handleTagError(err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return models.Tag{}, err
+	}
+	return r.getTag(int(id))
+}
+
+func (r *TagMySqlRepository) RenameTag(id int, name string) (models.Tag, error) {
+	if _, err := r.db.Exec("UPDATE tags SET name = ? WHERE id = ? AND tenant_id = ?", name, id, scopeTenant(r.tenantID)); err != nil {
+		return models.Tag{}, handleTagError(err)
+	}
+	// getTag devuelve sql.ErrNoRows si la etiqueta no existe
+	return r.getTag(id)
+}
+
+func (r *TagMySqlRepository) MergeTags(sourceID int, targetID int) (models.Tag, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Tag{}, err
+	}
+	defer tx.Rollback()
+
+	for _, id := range []int{sourceID, targetID} {
+		var exists int
+		if err := tx.QueryRow("SELECT 1 FROM tags WHERE id = ? AND tenant_id = ? FOR UPDATE", id, scopeTenant(r.tenantID)).Scan(&exists); err != nil {
+			return models.Tag{}, err
+		}
+	}
+
+	// INSERT IGNORE evita duplicar la relación si el item ya tenía ambas etiquetas
+	if _, err := tx.Exec("INSERT IGNORE INTO todo_item_tags (item_id, tag_id) SELECT item_id, ? FROM todo_item_tags WHERE tag_id = ?", targetID, sourceID); err != nil {
+		return models.Tag{}, err
+	}
+	if _, err := tx.Exec("DELETE FROM tags WHERE id = ?", sourceID); err != nil {
+		return models.Tag{}, fmt.Errorf("error al eliminar la etiqueta fusionada: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Tag{}, err
+	}
+	return r.getTag(targetID)
+}
+
+func (r *TagMySqlRepository) DeleteTag(id int) error {
+	// Las relaciones con items se eliminan por ON DELETE CASCADE
+	result, err := r.db.Exec("DELETE FROM tags WHERE id = ? AND tenant_id = ?", id, scopeTenant(r.tenantID))
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+const tenantColumns = "id, slug, name, max_items, max_projects, allow_registration, created_at, updated_at"
+
+type TenantMySqlRepository struct {
+	db *sql.DB
+}
+
+func NewTenantMySqlRepository(db *sql.DB) *TenantMySqlRepository {
+	return &TenantMySqlRepository{db: db}
+}
+
+// scopeTenant devuelve el tenant de una vista; el repositorio sin vista usa el tenant por defecto
+func scopeTenant(tenantID string) string {
+	if tenantID == "" {
+		return constants.DefaultTenantID
+	}
+	return tenantID
+}
+
+func scanTenant(row rowScanner) (models.Tenant, error) {
+	var tenant models.Tenant
+	err := row.Scan(&tenant.ID, &tenant.Slug, &tenant.Name, &tenant.MaxItems, &tenant.MaxProjects, &tenant.AllowRegistration, &tenant.CreatedAt, &tenant.UpdatedAt)
+	return tenant, err
+}
+
+func (r *TenantMySqlRepository) GetTenant(id string) (models.Tenant, error) {
+	return scanTenant(r.db.QueryRow("SELECT "+tenantColumns+" FROM tenants WHERE id = ?", id))
+}
+
+func (r *TenantMySqlRepository) GetTenantBySlug(slug string) (models.Tenant, error) {
+	return scanTenant(r.db.QueryRow("SELECT "+tenantColumns+" FROM tenants WHERE slug = ?", slug))
+}
+
+func (r *TenantMySqlRepository) GetTenantUsage(id string) (models.TenantUsage, error) {
+	var usage models.TenantUsage
+	err := r.db.QueryRow(`SELECT
+			(SELECT COUNT(*) FROM todo_items WHERE tenant_id = ? AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM projects WHERE tenant_id = ?)`, id, id).Scan(&usage.Items, &usage.Projects)
+	return usage, err
+}
+
+// checkQuota verifica que el tenant pueda agregar un item (column max_items) o
+// un proyecto (max_projects) más. Bloquea la fila del tenant hasta el fin de la
+// transacción para que dos altas simultáneas no superen juntas la cuota.
+func checkQuota(tx *sql.Tx, tenantID string, column string, countQuery string, exceeded error) error {
+	var max int
+	err := tx.QueryRow("SELECT "+column+" FROM tenants WHERE id = ? FOR UPDATE", tenantID).Scan(&max)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTenantNotFound
+	}
+	if err != nil || max == 0 {
+		return err
+	}
+
+	var used int
+	if err := tx.QueryRow(countQuery, tenantID).Scan(&used); err != nil {
+		return err
+	}
+	if models.Exceeds(max, used) {
+		return exceeded
+	}
+	return nil
+}
+
+// checkItemQuota es checkQuota para los items (no eliminados) del tenant
+func checkItemQuota(tx *sql.Tx, tenantID string) error {
+	return checkQuota(tx, tenantID, "max_items", "SELECT COUNT(*) FROM todo_items WHERE tenant_id = ? AND deleted_at IS NULL", ErrItemQuotaExceeded)
+}
+
+// checkProjectQuota es checkQuota para los proyectos del tenant
+func checkProjectQuota(tx *sql.Tx, tenantID string) error {
+	return checkQuota(tx, tenantID, "max_projects", "SELECT COUNT(*) FROM projects WHERE tenant_id = ?", ErrProjectQuotaExceeded)
+}
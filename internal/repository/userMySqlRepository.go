@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	userColumns         = "id, tenant_id, email, name, role, password_hash, oidc_issuer, oidc_subject, created_at, updated_at"
+	refreshTokenColumns = "id, user_id, token_hash, expires_at, revoked_at, created_at"
+)
+
+type UserMySqlRepository struct {
+	db *sql.DB
+}
+
+func NewUserMySqlRepository(db *sql.DB) *UserMySqlRepository {
+	return &UserMySqlRepository{db: db}
+}
+
+func scanUser(row rowScanner) (models.User, error) {
+	var user models.User
+	var issuer, subject sql.NullString
+	err := row.Scan(&user.ID, &user.TenantID, &user.Email, &user.Name, &user.Role, &user.PasswordHash, &issuer, &subject, &user.CreatedAt, &user.UpdatedAt)
+	user.Issuer = issuer.String
+	user.Subject = subject.String
+	return user, err
+}
+
+// nullableString guarda las cadenas vacías como NULL, para que la clave única de
+// la identidad OIDC no choque entre usuarios locales
+func nullableString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func (r *UserMySqlRepository) CreateUser(user models.User) (models.User, error) {
+	if user.Role == "" {
+		user.Role = constants.DefaultRole
+	}
+	result, err := r.db.Exec("INSERT INTO users (tenant_id, email, name, role, password_hash, oidc_issuer, oidc_subject) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		scopeTenant(user.TenantID), user.Email, user.Name, user.Role, user.PasswordHash, nullableString(user.Issuer), nullableString(user.Subject))
+	if err != nil {
+		// 1062 es una clave única duplicada (el email o la identidad OIDC)
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			return models.User{}, ErrDuplicateEmail
+		}
+		return models.User{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return models.User{}, err
+	}
+	return r.GetUser(int(id))
+}
+
+func (r *UserMySqlRepository) GetUser(id int) (models.User, error) {
+	return scanUser(r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE id = ?", id))
+}
+
+func (r *UserMySqlRepository) GetUserByEmail(email string) (models.User, error) {
+	return scanUser(r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE email = ?", email))
+}
+
+func (r *UserMySqlRepository) GetUserByIdentity(issuer string, subject string) (models.User, error) {
+	return scanUser(r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE oidc_issuer = ? AND oidc_subject = ?", issuer, subject))
+}
+
+func (r *UserMySqlRepository) LinkUserIdentity(id string, issuer string, subject string) (models.User, error) {
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		return models.User{}, sql.ErrNoRows
+	}
+	if _, err := r.db.Exec("UPDATE users SET oidc_issuer = ?, oidc_subject = ? WHERE id = ?", issuer, subject, userID); err != nil {
+		return models.User{}, err
+	}
+	return r.GetUser(userID)
+}
+
+func (r *UserMySqlRepository) SetUserRole(id string, role string) (models.User, error) {
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		return models.User{}, sql.ErrNoRows
+	}
+	if _, err := r.db.Exec("UPDATE users SET role = ? WHERE id = ?", role, userID); err != nil {
+		return models.User{}, err
+	}
+	return r.GetUser(userID)
+}
+
+func (r *UserMySqlRepository) GetUserProjectRoles(userID string) (map[string]string, error) {
+	rows, err := r.db.Query("SELECT project_id, role FROM project_members WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make(map[string]string)
+	for rows.Next() {
+		var projectID, role string
+		if err := rows.Scan(&projectID, &role); err != nil {
+			return nil, err
+		}
+		roles[projectID] = role
+	}
+	return roles, rows.Err()
+}
+
+func (r *UserMySqlRepository) GetUserShares(userID string) ([]models.Share, error) {
+	return queryShares(r.db, "SELECT "+shareColumns+" FROM shares WHERE user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?) ORDER BY id", userID, time.Now().UTC())
+}
+
+func (r *UserMySqlRepository) CreateRefreshToken(token models.RefreshToken) error {
+	_, err := r.db.Exec("INSERT INTO refresh_tokens (user_id, token_hash, expires_at) VALUES (?, ?, ?)", token.UserID, token.Hash, token.ExpiresAt)
+	return err
+}
+
+func (r *UserMySqlRepository) GetRefreshToken(hash string) (models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.QueryRow("SELECT "+refreshTokenColumns+" FROM refresh_tokens WHERE token_hash = ?", hash).
+		Scan(&token.ID, &token.UserID, &token.Hash, &token.ExpiresAt, &token.RevokedAt, &token.CreatedAt)
+	return token, err
+}
+
+func (r *UserMySqlRepository) RotateRefreshToken(oldHash string, next models.RefreshToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE refresh_tokens SET revoked_at = ? WHERE token_hash = ? AND revoked_at IS NULL", time.Now().UTC(), oldHash)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrTokenReused
+	}
+	if _, err := tx.Exec("INSERT INTO refresh_tokens (user_id, token_hash, expires_at) VALUES (?, ?, ?)", next.UserID, next.Hash, next.ExpiresAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *UserMySqlRepository) RevokeRefreshToken(hash string) error {
+	_, err := r.db.Exec("UPDATE refresh_tokens SET revoked_at = ? WHERE token_hash = ? AND revoked_at IS NULL", time.Now().UTC(), hash)
+	return err
+}
+
+func (r *UserMySqlRepository) RevokeUserRefreshTokens(userID string) error {
+	_, err := r.db.Exec("UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL", time.Now().UTC(), userID)
+	return err
+}
+
+func (r *UserMySqlRepository) RevokeAccessToken(jti string, expiresAt time.Time) error {
+	// Aprovechamos para borrar los que ya vencieron, que no hace falta recordar
+	if _, err := r.db.Exec("DELETE FROM revoked_tokens WHERE expires_at < ?", time.Now().UTC()); err != nil {
+		return err
+	}
+	_, err := r.db.Exec("INSERT IGNORE INTO revoked_tokens (jti, expires_at) VALUES (?, ?)", jti, expiresAt.UTC())
+	return err
+}
+
+func (r *UserMySqlRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var exists int
+	err := r.db.QueryRow("SELECT 1 FROM revoked_tokens WHERE jti = ?", jti).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
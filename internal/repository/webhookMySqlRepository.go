@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+)
+
+const (
+	webhookColumns  = "id, url, secret, events, active, created_at, updated_at"
+	deliveryColumns = "d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at, d.last_status_code, COALESCE(d.last_error, ''), d.created_at, d.updated_at, w.url, w.secret"
+)
+
+type WebhookMySqlRepository struct {
+	db *sql.DB
+	// tenantID limita los webhooks y las entregas a un tenant, ver WebhooksForTenant
+	tenantID string
+}
+
+func NewWebhookMySqlRepository(db *sql.DB) *WebhookMySqlRepository {
+	return &WebhookMySqlRepository{db: db}
+}
+
+// WebhooksForTenant devuelve una copia del repositorio limitada a los webhooks del tenant
+func (r *WebhookMySqlRepository) WebhooksForTenant(tenantID string) IWebhookRepository {
+	scoped := *r
+	scoped.tenantID = tenantID
+	return &scoped
+}
+
+// outboxEvent es un evento de webhook pendiente de repartir a los webhooks
+// suscritos de su tenant
+type outboxEvent struct {
+	id       int64
+	tenantID string
+	event    string
+	payload  []byte
+}
+
+// outboxEventsFor arma los eventos de webhook que genera un evento del historial
+func outboxEventsFor(event models.ItemEvent) ([]outboxEvent, error) {
+	var events []outboxEvent
+	for _, webhookEvent := range models.WebhookEventsFor(event) {
+		payload, err := json.Marshal(models.WebhookPayload{
+			Event:      webhookEvent,
+			ItemID:     event.ItemID,
+			Changes:    event.Changes,
+			Actor:      event.Actor,
+			RequestID:  event.RequestID,
+			OccurredAt: time.Now().UTC().Format(time.RFC3339Nano),
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, outboxEvent{event: webhookEvent, payload: payload})
+	}
+	return events, nil
+}
+
+// writeOutbox guarda en el outbox los eventos de webhook dentro de la transacción
+// del cambio, para que no se pierdan si el proceso termina antes de enviarlos
+func writeOutbox(tx *sql.Tx, tenantID string, event models.ItemEvent) error {
+	events, err := outboxEventsFor(event)
+	if err != nil {
+		return err
+	}
+	for _, outbox := range events {
+		if _, err := tx.Exec("INSERT INTO webhook_outbox (tenant_id, event, payload) VALUES (?, ?, ?)", tenantID, outbox.event, outbox.payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanWebhook(row rowScanner) (models.Webhook, error) {
+	var webhook models.Webhook
+	var events []byte
+	if err := row.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &events, &webhook.Active, &webhook.CreatedAt, &webhook.UpdatedAt); err != nil {
+		return webhook, err
+	}
+	err := json.Unmarshal(events, &webhook.Events)
+	return webhook, err
+}
+
+func scanDelivery(row rowScanner) (models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var statusCode sql.NullInt64
+	err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.Event, &delivery.Payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &statusCode, &delivery.LastError, &delivery.CreatedAt, &delivery.UpdatedAt, &delivery.URL, &delivery.Secret)
+	if statusCode.Valid {
+		code := int(statusCode.Int64)
+		delivery.LastStatusCode = &code
+	}
+	return delivery, err
+}
+
+func (r *WebhookMySqlRepository) GetAllWebhooks() ([]models.Webhook, error) {
+	rows, err := r.db.Query("SELECT "+webhookColumns+" FROM webhooks WHERE tenant_id = ? ORDER BY id", scopeTenant(r.tenantID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r *WebhookMySqlRepository) GetWebhook(id int) (models.Webhook, error) {
+	return scanWebhook(r.db.QueryRow("SELECT "+webhookColumns+" FROM webhooks WHERE id = ? AND tenant_id = ?", id, scopeTenant(r.tenantID)))
+}
+
+func (r *WebhookMySqlRepository) CreateWebhook(webhook models.Webhook) (models.Webhook, error) {
+	events, err := json.Marshal(webhookEvents(webhook))
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	result, err := r.db.Exec("INSERT INTO webhooks (tenant_id, url, secret, events, active) VALUES (?, ?, ?, ?, ?)", scopeTenant(r.tenantID), webhook.URL, webhook.Secret, events, webhook.Active)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	return r.GetWebhook(int(id))
+}
+
+func (r *WebhookMySqlRepository) UpdateWebhook(webhook models.Webhook) (models.Webhook, error) {
+	events, err := json.Marshal(webhookEvents(webhook))
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	result, err := r.db.Exec("UPDATE webhooks SET url = ?, secret = COALESCE(NULLIF(?, ''), secret), events = ?, active = ? WHERE id = ? AND tenant_id = ?", webhook.URL, webhook.Secret, events, webhook.Active, webhook.ID, scopeTenant(r.tenantID))
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return models.Webhook{}, err
+	} else if affected == 0 {
+		// RowsAffected es 0 también si nada cambió, así que confirmamos que existe
+		var exists int
+		if err := r.db.QueryRow("SELECT 1 FROM webhooks WHERE id = ? AND tenant_id = ?", webhook.ID, scopeTenant(r.tenantID)).Scan(&exists); err != nil {
+			return models.Webhook{}, err
+		}
+	}
+
+	id, err := strconv.Atoi(webhook.ID)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	return r.GetWebhook(id)
+}
+
+// webhookEvents devuelve los eventos del webhook, nunca nil para que se guarde como []
+func webhookEvents(webhook models.Webhook) []string {
+	if webhook.Events == nil {
+		return []string{}
+	}
+	return webhook.Events
+}
+
+func (r *WebhookMySqlRepository) DeleteWebhook(id int) error {
+	result, err := r.db.Exec("DELETE FROM webhooks WHERE id = ? AND tenant_id = ?", id, scopeTenant(r.tenantID))
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *WebhookMySqlRepository) GetDeliveries(filter DeliveryFilter, page int, pageSize int) ([]models.WebhookDelivery, int, error) {
+	conditions := []string{"w.tenant_id = ?"}
+	args := []any{scopeTenant(r.tenantID)}
+	if filter.WebhookID != "" {
+		conditions = append(conditions, "d.webhook_id = ?")
+		args = append(args, filter.WebhookID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "d.status = ?")
+		args = append(args, filter.Status)
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query("SELECT "+deliveryColumns+" FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id"+where+" ORDER BY d.id DESC LIMIT ? OFFSET ?", append(args, pageSize, (page-1)*pageSize)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, total, rows.Err()
+}
+
+func (r *WebhookMySqlRepository) getDelivery(id any) (models.WebhookDelivery, error) {
+	return scanDelivery(r.db.QueryRow("SELECT "+deliveryColumns+" FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id WHERE d.id = ? AND w.tenant_id = ?", id, scopeTenant(r.tenantID)))
+}
+
+func (r *WebhookMySqlRepository) Redeliver(id int) (models.WebhookDelivery, error) {
+	result, err := r.db.Exec("UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ? WHERE id = ? AND tenant_id = ?", constants.DeliveryPending, time.Now().UTC(), id, scopeTenant(r.tenantID))
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return models.WebhookDelivery{}, err
+	} else if affected == 0 {
+		return models.WebhookDelivery{}, sql.ErrNoRows
+	}
+	return r.getDelivery(id)
+}
+
+func (r *WebhookMySqlRepository) FanOutOutbox(limit int) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED permite que varias instancias procesen el outbox sin repetir eventos
+	rows, err := tx.Query("SELECT id, tenant_id, event, payload FROM webhook_outbox ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED", limit)
+	if err != nil {
+		return 0, err
+	}
+	var events []outboxEvent
+	for rows.Next() {
+		var event outboxEvent
+		if err := rows.Scan(&event.id, &event.tenantID, &event.event, &event.payload); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	// Cada evento se reparte solo a los webhooks de su tenant
+	webhooks := map[string][]models.Webhook{}
+	now := time.Now().UTC()
+	for _, event := range events {
+		if _, ok := webhooks[event.tenantID]; !ok {
+			tenantWebhooks, err := r.WebhooksForTenant(event.tenantID).GetAllWebhooks()
+			if err != nil {
+				return 0, err
+			}
+			webhooks[event.tenantID] = tenantWebhooks
+		}
+		for _, webhook := range webhooks[event.tenantID] {
+			if !webhook.Accepts(event.event) {
+				continue
+			}
+			if _, err := tx.Exec("INSERT INTO webhook_deliveries (tenant_id, webhook_id, event, payload, status, next_attempt_at) VALUES (?, ?, ?, ?, ?, ?)", event.tenantID, webhook.ID, event.event, event.payload, constants.DeliveryPending, now); err != nil {
+				return 0, err
+			}
+		}
+		if _, err := tx.Exec("DELETE FROM webhook_outbox WHERE id = ?", event.id); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), tx.Commit()
+}
+
+func (r *WebhookMySqlRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT "+deliveryColumns+" FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id WHERE d.status = ? AND d.next_attempt_at <= ? ORDER BY d.next_attempt_at LIMIT ? FOR UPDATE OF d SKIP LOCKED", constants.DeliveryPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, delivery := range deliveries {
+		if _, err := tx.Exec("UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ?", now.Add(lease).UTC(), delivery.ID); err != nil {
+			return nil, err
+		}
+	}
+	return deliveries, tx.Commit()
+}
+
+func (r *WebhookMySqlRepository) CompleteDelivery(id string, statusCode int) error {
+	_, err := r.db.Exec("UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1, next_attempt_at = NULL, last_status_code = ?, last_error = NULL WHERE id = ?", constants.DeliveryDelivered, statusCode, id)
+	return err
+}
+
+func (r *WebhookMySqlRepository) FailDelivery(id string, statusCode *int, errMsg string, nextAttempt *time.Time) error {
+	status := constants.DeliveryPending
+	var next any
+	if nextAttempt != nil {
+		next = nextAttempt.UTC()
+	} else {
+		status = constants.DeliveryDead
+	}
+	_, err := r.db.Exec("UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1, next_attempt_at = ?, last_status_code = ?, last_error = ? WHERE id = ?", status, next, statusCode, errMsg, id)
+	return err
+}
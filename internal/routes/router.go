@@ -1,15 +1,195 @@
 package routes
 
 import (
+	"github.com/Milagrosgzmn/devops_todo_go.git/api"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/gql"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/handlers"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/i18n"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/middleware"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/realtime"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(repo repository.IRepository) *gin.Engine {
+// Repositories agrupa los repositorios que usan los handlers
+type Repositories struct {
+	Items    repository.IRepository
+	Tags     repository.ITagRepository
+	Projects repository.IProjectRepository
+	Webhooks repository.IWebhookRepository
+	APIKeys  repository.IAPIKeyRepository
+	Users    repository.IUserRepository
+	Tenants  repository.ITenantRepository
+	Shares   repository.IShareRepository
+}
+
+// Scopes que exigen las rutas: items:read/items:write para items, etiquetas y
+// proyectos (lecturas y escrituras) y admin para la administración. Los scopes
+// de un usuario salen de su rol (ver policy); los handlers y el repositorio de
+// items consultan además el rol en cada proyecto.
+var (
+	itemScopes  = middleware.Authorize(constants.ScopeItemsRead, constants.ScopeItemsWrite)
+	adminScopes = middleware.Authorize(constants.ScopeAdmin, constants.ScopeAdmin)
+)
+
+// itemMutations atiende las mutaciones que llegan por el WebSocket con los
+// mismos handlers (y validaciones) que la API REST. Cada mutación se autentica
+// con las credenciales y el tenant de la conexión y exige items:write.
+func itemMutations(itemHandler *handlers.ItemHandler, resolveTenant gin.HandlerFunc, authenticate gin.HandlerFunc) *gin.Engine {
+	mutations := gin.New()
+	mutations.Use(middleware.RequestMeta(), resolveTenant, authenticate, itemScopes)
+	mutations.POST("/items", itemHandler.CreateItem)
+	mutations.PUT("/items/:id", itemHandler.UpdateItem)
+	mutations.DELETE("/items/:id", itemHandler.DeleteItem)
+	mutations.POST("/items/:id/transitions", itemHandler.TransitionItem)
+	return mutations
+}
+
+// restHandlers agrupa los handlers de la API REST, que se montan en cada versión
+type restHandlers struct {
+	items    *handlers.ItemHandler
+	stream   *handlers.StreamHandler
+	tags     *handlers.TagHandler
+	projects *handlers.ProjectHandler
+	webhooks *handlers.WebhookHandler
+	apiKeys  *handlers.APIKeyHandler
+	auth     *handlers.AuthHandler
+	users    *handlers.UserHandler
+	tenants  *handlers.TenantHandler
+	shares   *handlers.ShareHandler
+}
+
+// registerItemRoutes monta las rutas del ItemHandler, las únicas que cambian en la versión 2
+func registerItemRoutes(group *gin.RouterGroup, items *handlers.ItemHandler) {
+	group.GET("/items", items.GetItems)
+	group.GET("/items/:id", items.GetItemByID)
+	group.POST("/items", items.CreateItem)
+	group.PUT("/items/:id", items.UpdateItem)
+	group.DELETE("/items/:id", items.DeleteItem)
+	group.POST("/items/:id/restore", items.RestoreItem)
+	group.GET("/items/:id/history", items.GetHistory)
+	group.POST("/items/:id/transitions", items.TransitionItem)
+	group.PUT("/items/:id/project", items.MoveItem)
+	group.GET("/items/:id/children", items.GetChildren)
+	group.GET("/items/:id/subtree", items.GetSubtree)
+	group.PUT("/items/:id/parent", items.SetParent)
+	group.POST("/items/:id/dependencies", items.AddDependency)
+	group.DELETE("/items/:id/dependencies/:blocker_id", items.RemoveDependency)
+	group.GET("/items/:id/blockers", items.GetBlockers)
+	group.PUT("/items/:id/assignee", items.AssignItem)
+	group.DELETE("/items/:id/assignee", items.UnassignItem)
+	group.GET("/me/items", items.GetMyItems)
+}
+
+// registerRESTRoutes monta todas las rutas REST: el registro, el inicio de
+// sesión y los enlaces compartidos son públicos y el resto exige autenticarse,
+// con el scope de cada grupo.
+// En la versión 1 las rutas de items informan su deprecación y la ruta
+// equivalente de la versión 2.
+func registerRESTRoutes(group *gin.RouterGroup, rest restHandlers, versionConfig config.VersionConfig, authenticate gin.HandlerFunc) {
+	group.POST("/auth/register", rest.auth.Register)
+	group.POST("/auth/login", rest.auth.Login)
+	group.POST("/auth/refresh", rest.auth.Refresh)
+	group.POST("/auth/logout", authenticate, rest.auth.Logout)
+	group.GET("/auth/me", authenticate, rest.auth.Me)
+
+	// Los enlaces compartidos no se autentican: el token de la ruta es la credencial
+	group.GET("/shared/:token", rest.shares.GetSharedContent)
+	group.PUT("/shared/:token/items/:id", rest.shares.UpdateSharedItem)
+
+	group = group.Group("", authenticate)
+	admin := group.Group("", adminScopes)
+	group = group.Group("", itemScopes)
+
+	group.GET("/tenant", rest.tenants.GetTenant)
+
+	group.GET("/items/stream", rest.stream.StreamItems)
+	registerItemRoutes(group.Group("", middleware.Deprecated(versionConfig)), rest.items)
+
+	group.GET("/tags", rest.tags.GetTags)
+	group.POST("/tags", rest.tags.CreateTag)
+	group.PUT("/tags/:id", rest.tags.RenameTag)
+	group.DELETE("/tags/:id", rest.tags.DeleteTag)
+	group.POST("/tags/:id/merge", rest.tags.MergeTags)
+
+	group.GET("/projects", rest.projects.GetProjects)
+	group.GET("/projects/:id", rest.projects.GetProjectByID)
+	group.POST("/projects", rest.projects.CreateProject)
+	group.PUT("/projects/:id", rest.projects.UpdateProject)
+	group.DELETE("/projects/:id", rest.projects.DeleteProject)
+	group.POST("/projects/:id/archive", rest.projects.ArchiveProject)
+	group.POST("/projects/:id/unarchive", rest.projects.UnarchiveProject)
+	group.GET("/projects/:id/items", rest.projects.GetProjectItems)
+	group.POST("/projects/:id/items", rest.projects.CreateProjectItem)
+	group.GET("/projects/:id/members", rest.projects.GetProjectMembers)
+	group.PUT("/projects/:id/members/:user_id", rest.projects.SetProjectMember)
+	group.DELETE("/projects/:id/members/:user_id", rest.projects.RemoveProjectMember)
+
+	group.GET("/shares", rest.shares.GetShares)
+	group.POST("/shares", rest.shares.CreateShare)
+	group.GET("/shares/received", rest.shares.GetReceivedShares)
+	group.POST("/shares/:id/revoke", rest.shares.RevokeShare)
+
+	admin.GET("/webhooks", rest.webhooks.GetWebhooks)
+	admin.POST("/webhooks", rest.webhooks.CreateWebhook)
+	admin.GET("/webhooks/deliveries", rest.webhooks.GetDeliveries)
+	admin.POST("/webhooks/deliveries/:id/redeliver", rest.webhooks.RedeliverDelivery)
+	admin.GET("/webhooks/:id", rest.webhooks.GetWebhookByID)
+	admin.PUT("/webhooks/:id", rest.webhooks.UpdateWebhook)
+	admin.DELETE("/webhooks/:id", rest.webhooks.DeleteWebhook)
+
+	admin.GET("/api-keys", rest.apiKeys.GetAPIKeys)
+	admin.POST("/api-keys", rest.apiKeys.CreateAPIKey)
+	admin.GET("/api-keys/:id", rest.apiKeys.GetAPIKeyByID)
+	admin.POST("/api-keys/:id/revoke", rest.apiKeys.RevokeAPIKey)
+	admin.POST("/api-keys/:id/rotate", rest.apiKeys.RotateAPIKey)
+
+	admin.PUT("/users/:id/role", rest.users.SetUserRole)
+}
+
+// NewAuthenticator crea el verificador de credenciales de la configuración: API
+// keys, tokens de acceso propios y, con OIDC_ISSUER, los del proveedor de
+// identidad. Lo usan la API REST y la gRPC.
+func NewAuthenticator(repos Repositories, authConfig config.AuthConfig) *middleware.Authenticator {
+	tokens := auth.NewTokenIssuer(authConfig.JWTSecret, authConfig.AccessTokenTTL)
+	var oidc *auth.OIDCVerifier
+	if authConfig.OIDC.Issuer != "" {
+		oidc = auth.NewOIDCVerifier(authConfig.OIDC)
+	}
+	return middleware.NewAuthenticator(repos.APIKeys, repos.Users, repos.Tenants, tokens, oidc, authConfig)
+}
+
+func SetupRouter(repos Repositories, itemRules config.ItemRules, hub *events.Hub, streamConfig config.StreamConfig, wsConfig config.WebSocketConfig, graphQLConfig config.GraphQLConfig, versionConfig config.VersionConfig, authConfig config.AuthConfig, tenantConfig config.TenantConfig) *gin.Engine {
 	router := gin.Default()
+	router.Use(middleware.RequestMeta())
+	router.Use(middleware.Language(i18n.Default))
+	// El tenant pedido (X-Tenant o subdominio); Authenticate verifica que las
+	// credenciales sean de ese tenant
+	resolveTenant := middleware.ResolveTenant(repos.Tenants, tenantConfig)
+	router.Use(resolveTenant)
+
+	// Todas las rutas salvo /health, la documentación, el registro y el inicio de
+	// sesión exigen una API key o el token de acceso de un usuario
+	authenticator := NewAuthenticator(repos, authConfig)
+	authenticate := middleware.Authenticate(authenticator)
 
-	itemHandler := handlers.NewItemHandler(repo)
+	itemHandler := handlers.NewItemHandler(repos.Items, itemRules)
+	tagHandler := handlers.NewTagHandler(repos.Tags)
+	projectHandler := handlers.NewProjectHandler(repos.Projects, itemHandler)
+	webhookHandler := handlers.NewWebhookHandler(repos.Webhooks)
+	apiKeyHandler := handlers.NewAPIKeyHandler(repos.APIKeys)
+	authHandler := handlers.NewAuthHandler(repos.Users, repos.Tenants, authenticator.Tokens(), authConfig.RefreshTokenTTL)
+	userHandler := handlers.NewUserHandler(repos.Users)
+	tenantHandler := handlers.NewTenantHandler(repos.Tenants)
+	shareHandler := handlers.NewShareHandler(repos.Shares, repos.Projects, itemHandler)
+	streamHandler := handlers.NewStreamHandler(hub, streamConfig.Heartbeat)
+	webSocketHandler := handlers.NewWebSocketHandler(realtime.NewServer(hub, itemMutations(itemHandler, resolveTenant, authenticate), wsConfig))
+	docsHandler := handlers.NewDocsHandler(api.OpenAPI)
+	graphQLHandler := gql.NewHandler(gql.NewServer(repos.Items, repos.Projects, repos.Tags, itemRules, hub, graphQLConfig), streamConfig.Heartbeat)
 
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -17,11 +197,34 @@ func SetupRouter(repo repository.IRepository) *gin.Engine {
 		})
 	});
 
-	router.GET("/items", itemHandler.GetItems)
-	router.GET("/items/:id", itemHandler.GetItemByID)
-	router.POST("/items", itemHandler.CreateItem)
-	router.PUT("/items/:id", itemHandler.UpdateItem)
-	router.DELETE("/items/:id", itemHandler.DeleteItem)
+	router.GET("/openapi.json", docsHandler.GetSpec)
+	router.GET("/docs/*filepath", docsHandler.SwaggerUI)
+
+	// El WebSocket y GraphQL piden items:read para conectarse o consultar; las
+	// mutaciones exigen además items:write. El WebSocket acepta además las
+	// credenciales de los navegadores en Sec-WebSocket-Protocol.
+	readScope := middleware.Authorize(constants.ScopeItemsRead, constants.ScopeItemsRead)
+	router.GET("/ws", middleware.WebSocketCredentials(), authenticate, readScope, webSocketHandler.Connect)
+
+	router.GET("/graphql", authenticate, readScope, graphQLHandler.Serve)
+	router.POST("/graphql", authenticate, readScope, graphQLHandler.Serve)
+
+	rest := restHandlers{
+		items:    itemHandler,
+		stream:   streamHandler,
+		tags:     tagHandler,
+		projects: projectHandler,
+		webhooks: webhookHandler,
+		apiKeys:  apiKeyHandler,
+		auth:     authHandler,
+		users:    userHandler,
+		tenants:  tenantHandler,
+		shares:   shareHandler,
+	}
+	// Sin prefijo la versión se elige con la cabecera API-Version (por defecto la 1)
+	registerRESTRoutes(router.Group("/", middleware.NegotiateVersion()), rest, versionConfig, authenticate)
+	registerRESTRoutes(router.Group("/v1", middleware.APIVersion(constants.APIVersion1)), rest, versionConfig, authenticate)
+	registerItemRoutes(router.Group("/v2", middleware.APIVersion(constants.APIVersion2), authenticate, itemScopes), itemHandler)
 
 	return router
-}
\ No newline at end of file
+}
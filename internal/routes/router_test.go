@@ -0,0 +1,425 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/api"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth/oidctest"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// ginParam son los parámetros de ruta de gin (:id, *filepath)
+var ginParam = regexp.MustCompile(`[:*](\w+)`)
+
+// bootstrapKey es la key admin de las pruebas
+const bootstrapKey = "tdk_bootstrap"
+
+func setupRouter() *gin.Engine {
+	router, _ := setupRouterWithRepo()
+	return router
+}
+
+func setupRouterWithRepo() (*gin.Engine, *repository.MockRepository) {
+	gin.SetMode(gin.TestMode)
+	repo := repository.NewMockRepository()
+	repos := Repositories{Items: repo, Tags: repo, Projects: repo, Webhooks: repo, APIKeys: repo, Users: repo, Tenants: repo, Shares: repo}
+	hub := events.NewHub(events.NewMemoryPubSub(), 10)
+	authConfig := config.DefaultAuthConfig()
+	authConfig.BootstrapKey = bootstrapKey
+	return SetupRouter(repos, config.DefaultItemRules(), hub, config.DefaultStreamConfig(),
+		config.DefaultWebSocketConfig(), config.DefaultGraphQLConfig(), config.DefaultVersionConfig(), authConfig, config.DefaultTenantConfig()), repo
+}
+
+// send hace la petición con la API key key (vacía no la envía)
+func send(router *gin.Engine, method string, path string, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func loadSpec(t *testing.T) *openapi3.T {
+	spec, err := openapi3.NewLoader().LoadFromData(api.OpenAPI)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return spec
+}
+
+func TestOpenAPISpecIsValid(t *testing.T) {
+	spec := loadSpec(t)
+	assert.NoError(t, spec.Validate(context.Background()))
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	spec := loadSpec(t)
+	router := setupRouter()
+
+	registered := map[string]bool{}
+	for _, route := range router.Routes() {
+		path := ginParam.ReplaceAllString(route.Path, "{$1}")
+		registered[route.Method+" "+path] = true
+
+		item := spec.Paths.Value(path)
+		if !assert.NotNil(t, item, "la ruta %s no está en api/openapi.json", path) {
+			continue
+		}
+		assert.NotNil(t, item.GetOperation(route.Method), "%s %s no está en api/openapi.json", route.Method, path)
+	}
+
+	// Y al revés: la especificación no describe rutas que no existen
+	for path, item := range spec.Paths.Map() {
+		for method := range item.Operations() {
+			assert.True(t, registered[method+" "+path], "%s %s está en api/openapi.json pero no se registra", method, path)
+		}
+	}
+}
+
+func TestDocsRoutes(t *testing.T) {
+	router := setupRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, api.OpenAPI, w.Body.Bytes())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/docs", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/docs/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `url: "/openapi.json"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/docs/swagger-ui-bundle.js", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.Contains(w.Header().Get("Content-Type"), "javascript"))
+}
+
+// adminRequest arma una petición con la key de bootstrap
+func adminRequest(method string, path string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("X-API-Key", bootstrapKey)
+	return req
+}
+
+func TestAPIVersions(t *testing.T) {
+	router := setupRouter()
+
+	// v1 está deprecada y enlaza a la misma ruta en v2
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("GET", "/v1/items"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("API-Version"))
+	assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</v2/items>; rel="successor-version"`, w.Header().Get("Link"))
+	assert.Contains(t, w.Body.String(), `"message"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("GET", "/v2/items"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("API-Version"))
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.NotContains(t, w.Body.String(), `"message"`)
+
+	// Sin prefijo la versión se negocia con la cabecera API-Version
+	req := adminRequest("GET", "/items")
+	req.Header.Set("API-Version", "v2")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("API-Version"))
+	assert.Empty(t, w.Header().Get("Deprecation"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("GET", "/items"))
+	assert.Equal(t, "1", w.Header().Get("API-Version"))
+	assert.NotEmpty(t, w.Header().Get("Deprecation"))
+
+	req = adminRequest("GET", "/items")
+	req.Header.Set("API-Version", "3")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"unsupported_version"`)
+
+	// Las rutas sin versión 2 no se deprecan
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest("GET", "/v1/tags"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Deprecation"))
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	router, repo := setupRouterWithRepo()
+
+	// /health y la documentación son públicas; el resto exige una key
+	assert.Equal(t, http.StatusOK, send(router, "GET", "/health", "", "").Code)
+	w := send(router, "GET", "/items", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"authentication_required"`)
+	assert.Equal(t, http.StatusUnauthorized, send(router, "GET", "/items", "tdk_inventada", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, send(router, "GET", "/graphql?query={items{id}}", "", "").Code)
+
+	// La key de bootstrap (admin) emite una key de solo lectura
+	w = send(router, "POST", "/api-keys", bootstrapKey, `{"name": "dashboard", "scopes": ["items:read"]}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created struct {
+		Data models.APIKey `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	reader := created.Data.Key
+
+	assert.Equal(t, http.StatusOK, send(router, "GET", "/items", reader, "").Code)
+	assert.Equal(t, http.StatusOK, send(router, "GET", "/v2/items", reader, "").Code)
+	assert.Equal(t, http.StatusOK, send(router, "GET", "/tags", reader, "").Code)
+	w = send(router, "POST", "/items", reader, `{"title": "Tarea"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"insufficient_scope"`)
+	assert.Equal(t, http.StatusForbidden, send(router, "GET", "/webhooks", reader, "").Code)
+	assert.Equal(t, http.StatusForbidden, send(router, "GET", "/api-keys", reader, "").Code)
+	assert.Equal(t, http.StatusOK, send(router, "GET", "/graphql?query={items{id}}", reader, "").Code)
+	w = send(router, "POST", "/graphql", reader, `{"query": "mutation { createItem(input: {title: \"Tarea\"}) { id } }"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Se registra el último uso
+	key, _ := repo.GetAPIKey(1)
+	assert.NotNil(t, key.LastUsedAt)
+
+	// Al rotarla la key anterior deja de funcionar
+	w = send(router, "POST", "/api-keys/1/rotate", bootstrapKey, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &created)
+	assert.Equal(t, http.StatusUnauthorized, send(router, "GET", "/items", reader, "").Code)
+	reader = created.Data.Key
+	assert.Equal(t, http.StatusOK, send(router, "GET", "/items", reader, "").Code)
+
+	// Y al revocarla no se acepta más
+	assert.Equal(t, http.StatusOK, send(router, "POST", "/api-keys/1/revoke", bootstrapKey, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, send(router, "GET", "/items", reader, "").Code)
+
+	// Los errores de la base de datos se registran, pero no se devuelven
+	repo.SimulateError(true)
+	w = send(router, "GET", "/items", reader, "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"internal_error"`)
+	assert.NotContains(t, w.Body.String(), "simulated")
+}
+
+func TestAPIKeyAuthDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := repository.NewMockRepository()
+	repos := Repositories{Items: repo, Tags: repo, Projects: repo, Webhooks: repo, APIKeys: repo, Users: repo, Tenants: repo, Shares: repo}
+	authConfig := config.DefaultAuthConfig()
+	authConfig.Enabled = false
+	router := SetupRouter(repos, config.DefaultItemRules(), events.NewHub(events.NewMemoryPubSub(), 10), config.DefaultStreamConfig(),
+		config.DefaultWebSocketConfig(), config.DefaultGraphQLConfig(), config.DefaultVersionConfig(), authConfig, config.DefaultTenantConfig())
+
+	assert.Equal(t, http.StatusCreated, send(router, "POST", "/items", "", `{"title": "Tarea", "state": "pending"}`).Code)
+	assert.Equal(t, http.StatusOK, send(router, "GET", "/webhooks", "", "").Code)
+}
+
+func TestUserSession(t *testing.T) {
+	router, repo := setupRouterWithRepo()
+
+	assert.Equal(t, http.StatusCreated, send(router, "POST", "/auth/register", "", `{"email": "ana@example.com", "password": "contraseña-segura"}`).Code)
+	w := send(router, "POST", "/v1/auth/login", "", `{"email": "ana@example.com", "password": "contraseña-segura"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var session struct {
+		Data struct {
+			AccessToken string `json:"access_token"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &session)
+
+	bearer := func(method string, path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+session.Data.AccessToken)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// El usuario lee y escribe items, y queda como actor de sus cambios
+	assert.Equal(t, http.StatusOK, bearer("GET", "/auth/me", "").Code)
+	assert.Equal(t, http.StatusCreated, bearer("POST", "/items", `{"title": "Tarea", "state": "pending"}`).Code)
+	history, _, _ := repo.GetHistory(1, 1, 10)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, "ana@example.com", history[0].Actor)
+	}
+
+	// Pero no administra
+	assert.Equal(t, http.StatusForbidden, bearer("GET", "/api-keys", "").Code)
+
+	// Al cerrar la sesión el token de acceso deja de valer
+	assert.Equal(t, http.StatusOK, bearer("POST", "/auth/logout", "").Code)
+	w = bearer("GET", "/items", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), "invalid_token")
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"invalid_token"`)
+
+	// Con una API key /auth/me no tiene usuario
+	assert.Equal(t, http.StatusUnauthorized, send(router, "GET", "/auth/me", bootstrapKey, "").Code)
+}
+
+func TestWebSocketBrowserAuth(t *testing.T) {
+	router, repo := setupRouterWithRepo()
+	server := httptest.NewServer(router)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?actor=ana"
+
+	assert.Equal(t, http.StatusCreated, send(router, "POST", "/auth/register", "", `{"email": "ana@example.com", "password": "contraseña-segura"}`).Code)
+	w := send(router, "POST", "/v1/auth/login", "", `{"email": "ana@example.com", "password": "contraseña-segura"}`)
+	var session struct {
+		Data struct {
+			AccessToken string `json:"access_token"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &session)
+
+	// Un navegador no puede enviar cabeceras: sin credenciales no se conecta
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.Error(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+	dialer := websocket.Dialer{Subprotocols: []string{"todo.v1", "bearer.no-es-un-token"}}
+	_, resp, err = dialer.Dial(url, nil)
+	assert.Error(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	// Con las credenciales como subprotocolo sí, y el servidor elige solo todo.v1
+	for _, protocol := range []string{"bearer." + session.Data.AccessToken, "apikey." + bootstrapKey} {
+		dialer := websocket.Dialer{Subprotocols: []string{"todo.v1", protocol}}
+		conn, _, err := dialer.Dial(url, nil)
+		if !assert.NoError(t, err, protocol) {
+			continue
+		}
+		assert.Equal(t, "todo.v1", conn.Subprotocol())
+
+		// Las mutaciones del canal usan las mismas credenciales
+		conn.WriteJSON(map[string]any{"type": "create", "ref": "r1", "data": map[string]any{"title": "Desde el navegador", "state": "pending"}})
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		for {
+			var message struct {
+				Type   string `json:"type"`
+				Status int    `json:"status"`
+			}
+			if !assert.NoError(t, conn.ReadJSON(&message)) {
+				break
+			}
+			if message.Type == "result" {
+				assert.Equal(t, http.StatusCreated, message.Status, protocol)
+				break
+			}
+		}
+		conn.Close()
+	}
+
+	// El item creado con el token queda a nombre del usuario
+	history, _, _ := repo.GetHistory(1, 1, 10)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, "ana@example.com", history[0].Actor)
+	}
+}
+
+func TestOIDCLogin(t *testing.T) {
+	issuer := oidctest.NewIssuer()
+	defer issuer.Close()
+
+	gin.SetMode(gin.TestMode)
+	repo := repository.NewMockRepository()
+	repos := Repositories{Items: repo, Tags: repo, Projects: repo, Webhooks: repo, APIKeys: repo, Users: repo, Tenants: repo, Shares: repo}
+	authConfig := config.DefaultAuthConfig()
+	authConfig.OIDC.Issuer = issuer.URL()
+	authConfig.OIDC.Audience = "todo-api"
+	authConfig.OIDC.RoleScopes = map[string][]string{
+		"todo-viewer": {"items:read"},
+		"todo-editor": {"items:read", "items:write"},
+	}
+	router := SetupRouter(repos, config.DefaultItemRules(), events.NewHub(events.NewMemoryPubSub(), 10), config.DefaultStreamConfig(),
+		config.DefaultWebSocketConfig(), config.DefaultGraphQLConfig(), config.DefaultVersionConfig(), authConfig, config.DefaultTenantConfig())
+
+	bearer := func(token string, method string, path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// La primera vez se crea el usuario local con los datos del token
+	editor := issuer.Token(jwt.MapClaims{"aud": "todo-api", "sub": "ana", "email": "ana@example.com", "name": "Ana", "roles": []string{"todo-editor"}})
+	w := bearer(editor, "GET", "/auth/me", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"issuer":"`+issuer.URL()+`"`)
+	assert.Contains(t, w.Body.String(), `"roles":["todo-editor"]`)
+	assert.Equal(t, http.StatusCreated, bearer(editor, "POST", "/items", `{"title": "Tarea", "state": "pending"}`).Code)
+	history, _, _ := repo.GetHistory(1, 1, 10)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, "ana@example.com", history[0].Actor)
+	}
+	user, err := repo.GetUserByIdentity(issuer.URL(), "ana")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Ana", user.Name)
+	}
+
+	// Los roles otorgan los scopes
+	viewer := issuer.Token(jwt.MapClaims{"aud": "todo-api", "sub": "beto", "email": "beto@example.com", "roles": []string{"todo-viewer"}})
+	assert.Equal(t, http.StatusOK, bearer(viewer, "GET", "/items", "").Code)
+	assert.Equal(t, http.StatusForbidden, bearer(viewer, "POST", "/items", `{"title": "Tarea", "state": "pending"}`).Code)
+
+	// Una cuenta local se asocia solo si el proveedor verificó el email
+	assert.Equal(t, http.StatusCreated, send(router, "POST", "/auth/register", "", `{"email": "caro@example.com", "password": "contraseña-segura"}`).Code)
+	unverified := issuer.Token(jwt.MapClaims{"aud": "todo-api", "sub": "caro", "email": "caro@example.com", "roles": []string{"todo-viewer"}})
+	assert.Equal(t, http.StatusUnauthorized, bearer(unverified, "GET", "/items", "").Code)
+	verified := issuer.Token(jwt.MapClaims{"aud": "todo-api", "sub": "caro", "email": "caro@example.com", "email_verified": true, "roles": []string{"todo-viewer"}})
+	assert.Equal(t, http.StatusOK, bearer(verified, "GET", "/items", "").Code)
+	local, _ := repo.GetUserByEmail("caro@example.com")
+	assert.Equal(t, "caro", local.Subject)
+
+	// Sin email no hay usuario, y los tokens de otra audiencia no valen
+	assert.Equal(t, http.StatusUnauthorized, bearer(issuer.Token(jwt.MapClaims{"aud": "todo-api", "sub": "dani"}), "GET", "/items", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, bearer(issuer.Token(jwt.MapClaims{"aud": "otra-api", "sub": "ana", "email": "ana@example.com"}), "GET", "/items", "").Code)
+
+	// Los tokens propios siguen funcionando junto a los del proveedor
+	w = send(router, "POST", "/auth/login", "", `{"email": "caro@example.com", "password": "contraseña-segura"}`)
+	var session struct {
+		Data struct {
+			AccessToken string `json:"access_token"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &session)
+	assert.Equal(t, http.StatusOK, bearer(session.Data.AccessToken, "GET", "/items", "").Code)
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSharedLinks verifica que los enlaces compartidos no exijan credenciales y
+// que solo valgan en el tenant del item
+func TestSharedLinks(t *testing.T) {
+	router, _, acme, _ := setupTenants(t, config.DefaultTenantConfig())
+
+	itemID := createdID(t, send(router, "POST", "/items", acme, `{"title": "Tarea compartida", "state": "pending"}`))
+	w := send(router, "POST", "/shares", acme, `{"item_id": "`+itemID+`", "permission": "edit"}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var response struct {
+		Data models.Share `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	token := response.Data.Token
+
+	for _, prefix := range []string{"", "/v1"} {
+		w = sendTenant(router, "GET", prefix+"/shared/"+token, "", "", "")
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), `"id":"`+itemID+`"`)
+		assert.Equal(t, http.StatusOK, sendTenant(router, "PUT", prefix+"/shared/"+token+"/items/"+itemID, "", "acme", `{"title": "Editada", "state": "pending"}`).Code)
+		assert.Equal(t, http.StatusNotFound, sendTenant(router, "GET", prefix+"/shared/"+token, "", "globex", "").Code)
+	}
+
+	// El resto de las rutas de compartidos exige autenticarse
+	assert.Equal(t, http.StatusUnauthorized, sendTenant(router, "GET", "/shares?item_id="+itemID, "", "", "").Code)
+	assert.Contains(t, send(router, "GET", "/items/"+itemID, acme, "").Body.String(), "Editada")
+}
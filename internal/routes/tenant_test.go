@@ -0,0 +1,298 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/auth/oidctest"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupTenants arma el router con los tenants acme y globex (además del tenant
+// por defecto), de los que solo globex admite el registro, y devuelve una key
+// admin de cada uno
+func setupTenants(t *testing.T, tenantConfig config.TenantConfig) (*gin.Engine, *repository.MockRepository, string, string) {
+	gin.SetMode(gin.TestMode)
+	repo := repository.NewMockRepository()
+	repo.AddTenant(models.Tenant{Slug: "acme", Name: "Acme", MaxItems: 3, MaxProjects: 1})
+	repo.AddTenant(models.Tenant{Slug: "globex", Name: "Globex", AllowRegistration: true})
+
+	repos := Repositories{Items: repo, Tags: repo, Projects: repo, Webhooks: repo, APIKeys: repo, Users: repo, Tenants: repo, Shares: repo}
+	authConfig := config.DefaultAuthConfig()
+	authConfig.BootstrapKey = bootstrapKey
+	router := SetupRouter(repos, config.DefaultItemRules(), events.NewHub(events.NewMemoryPubSub(), 10), config.DefaultStreamConfig(),
+		config.DefaultWebSocketConfig(), config.DefaultGraphQLConfig(), config.DefaultVersionConfig(), authConfig, tenantConfig)
+
+	// La key de bootstrap no es de ningún tenant: emite las keys en el tenant de X-Tenant
+	return router, repo, createTenantKey(t, router, "acme"), createTenantKey(t, router, "globex")
+}
+
+func createTenantKey(t *testing.T, router *gin.Engine, slug string) string {
+	w := sendTenant(router, "POST", "/api-keys", bootstrapKey, slug, `{"name": "admin", "scopes": ["admin"]}`)
+	if !assert.Equal(t, http.StatusCreated, w.Code) {
+		t.FailNow()
+	}
+	var response struct {
+		Data models.APIKey `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Data.Key
+}
+
+// sendTenant hace la petición con la API key key y, si no está vacío, el tenant slug en X-Tenant
+func sendTenant(router *gin.Engine, method string, path string, key string, slug string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", key)
+	if slug != "" {
+		req.Header.Set("X-Tenant", slug)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// createdID devuelve el ID de la respuesta {data: {id}}
+func createdID(t *testing.T, w *httptest.ResponseRecorder) string {
+	if !assert.Equal(t, http.StatusCreated, w.Code, w.Body.String()) {
+		t.FailNow()
+	}
+	var response struct {
+		Data struct {
+			ID any `json:"id"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return fmt.Sprint(response.Data.ID)
+}
+
+// TestTenantIsolation recorre todas las rutas de items (y las de proyectos y
+// etiquetas) con las credenciales de globex sobre los datos de acme: ninguna
+// puede devolverlos ni modificarlos
+func TestTenantIsolation(t *testing.T) {
+	router, _, acme, globex := setupTenants(t, config.DefaultTenantConfig())
+
+	projectID := createdID(t, send(router, "POST", "/projects", acme, `{"name": "Proyecto secreto de acme"}`))
+	itemID := createdID(t, send(router, "POST", "/items", acme,
+		fmt.Sprintf(`{"title": "Item secreto de acme", "state": "pending", "project_id": "%s", "tags": ["secreto-acme"]}`, projectID)))
+	blockerID := createdID(t, send(router, "POST", "/items", acme, `{"title": "Bloqueante secreto de acme", "state": "pending"}`))
+	assert.Equal(t, http.StatusCreated, send(router, "POST", "/items/"+itemID+"/dependencies", acme, fmt.Sprintf(`{"blocker_id": "%s"}`, blockerID)).Code)
+
+	w := send(router, "GET", "/tags", acme, "")
+	var tags struct {
+		Data []models.Tag `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &tags))
+	if !assert.Len(t, tags.Data, 1) {
+		t.FailNow()
+	}
+	tagID := tags.Data[0].ID
+
+	before := send(router, "GET", "/items/"+itemID, acme, "").Body.String()
+
+	// Un cuerpo válido para todas las mutaciones, que apunta a los datos de acme
+	body := fmt.Sprintf(`{"title": "Intruso", "state": "in_progress", "to": "in_progress", "project_id": "%s", "parent_id": "%s",
+		"blocker_id": "%s", "assignee_id": "1", "name": "intruso", "target_id": %s, "role": "admin"}`, projectID, blockerID, blockerID, tagID)
+	for _, route := range router.Routes() {
+		path := strings.TrimPrefix(strings.TrimPrefix(route.Path, "/v1"), "/v2")
+		if !strings.HasPrefix(path, "/items") && !strings.HasPrefix(path, "/me") &&
+			!strings.HasPrefix(path, "/projects") && !strings.HasPrefix(path, "/tags") {
+			continue
+		}
+		if path == "/items/stream" {
+			continue
+		}
+
+		id := itemID
+		switch {
+		case strings.HasPrefix(path, "/projects"):
+			id = projectID
+		case strings.HasPrefix(path, "/tags"):
+			id = tagID
+		}
+		url := strings.NewReplacer(":id", id, ":blocker_id", blockerID, ":user_id", "1").Replace(route.Path)
+
+		// Las que responden 2xx trabajan sobre los datos de globex (por ejemplo
+		// POST /tags crea su propia etiqueta); al final se verifica que acme no cambió
+		w := send(router, route.Method, url, globex, body)
+		assert.Less(t, w.Code, 500, "%s %s: %s", route.Method, url, w.Body.String())
+		assert.NotContains(t, w.Body.String(), "acme", "%s %s devolvió datos de acme", route.Method, url)
+	}
+
+	// Los datos de acme siguen igual
+	assert.Equal(t, before, send(router, "GET", "/items/"+itemID, acme, "").Body.String())
+	w = send(router, "GET", "/items/"+itemID+"/blockers", acme, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Bloqueante secreto de acme")
+	w = send(router, "GET", "/projects/"+projectID, acme, "")
+	assert.Contains(t, w.Body.String(), "Proyecto secreto de acme")
+	assert.NotContains(t, w.Body.String(), `"archived":true`)
+	assert.Contains(t, send(router, "GET", "/tags", acme, "").Body.String(), "secreto-acme")
+
+	// Y globex solo ve lo suyo
+	w = send(router, "GET", "/items", globex, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "acme")
+}
+
+func TestTenantResolution(t *testing.T) {
+	router, _, acme, globex := setupTenants(t, config.TenantConfig{BaseDomain: "todo.test"})
+
+	itemID := createdID(t, send(router, "POST", "/items", acme, `{"title": "Tarea de acme", "state": "pending"}`))
+
+	// La cabecera X-Tenant debe coincidir con el tenant de las credenciales
+	assert.Equal(t, http.StatusOK, sendTenant(router, "GET", "/items/"+itemID, acme, "acme", "").Code)
+	w := sendTenant(router, "GET", "/items/"+itemID, acme, "globex", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"tenant_mismatch"`)
+	assert.Contains(t, w.Body.String(), `"detail":"globex"`)
+	w = sendTenant(router, "GET", "/items", acme, "initech", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"tenant_not_found"`)
+
+	// También el subdominio de TENANT_BASE_DOMAIN
+	subdomain := func(host string, key string) int {
+		req := httptest.NewRequest("GET", "/items/"+itemID, nil)
+		req.Host = host
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, subdomain("acme.todo.test:8080", acme))
+	assert.Equal(t, http.StatusForbidden, subdomain("acme.todo.test", globex))
+	assert.Equal(t, http.StatusNotFound, subdomain("initech.todo.test", acme))
+
+	// La key de bootstrap trabaja en el tenant pedido
+	assert.Equal(t, http.StatusOK, sendTenant(router, "GET", "/items/"+itemID, bootstrapKey, "acme", "").Code)
+	assert.Equal(t, http.StatusNotFound, sendTenant(router, "GET", "/items/"+itemID, bootstrapKey, "globex", "").Code)
+
+	w = send(router, "GET", "/tenant", acme, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data models.Tenant `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "acme", response.Data.Slug)
+	if assert.NotNil(t, response.Data.Usage) {
+		assert.Equal(t, 1, response.Data.Usage.Items)
+	}
+}
+
+func TestTenantQuotas(t *testing.T) {
+	router, _, acme, globex := setupTenants(t, config.DefaultTenantConfig())
+
+	// acme admite 3 items y 1 proyecto
+	var ids []string
+	for i := 0; i < 3; i++ {
+		ids = append(ids, createdID(t, send(router, "POST", "/items", acme, `{"title": "Tarea", "state": "pending"}`)))
+	}
+	w := send(router, "POST", "/items", acme, `{"title": "Una más", "state": "pending"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"quota_exceeded"`)
+
+	// Los items eliminados no cuentan, pero restaurarlos sí
+	assert.Equal(t, http.StatusOK, send(router, "DELETE", "/items/"+ids[0], acme, "").Code)
+	assert.Equal(t, http.StatusCreated, send(router, "POST", "/items", acme, `{"title": "Reemplazo", "state": "pending"}`).Code)
+	assert.Equal(t, http.StatusForbidden, send(router, "POST", "/items/"+ids[0]+"/restore", acme, "").Code)
+
+	assert.Equal(t, http.StatusCreated, send(router, "POST", "/projects", acme, `{"name": "Uno"}`).Code)
+	assert.Equal(t, http.StatusForbidden, send(router, "POST", "/projects", acme, `{"name": "Dos"}`).Code)
+
+	// Las cuotas son de cada tenant
+	assert.Equal(t, http.StatusCreated, send(router, "POST", "/items", globex, `{"title": "Tarea", "state": "pending"}`).Code)
+	assert.Equal(t, http.StatusCreated, send(router, "POST", "/projects", globex, `{"name": "Dos"}`).Code)
+}
+
+func TestTenantRegistration(t *testing.T) {
+	router, repo, _, _ := setupTenants(t, config.DefaultTenantConfig())
+
+	// Solo los tenants que lo admiten aceptan el registro
+	w := sendTenant(router, "POST", "/auth/register", "", "acme", `{"email": "ana@acme.com", "password": "contraseña-segura"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "El tenant no admite el registro de usuarios")
+	_, err := repo.GetUserByEmail("ana@acme.com")
+	assert.Error(t, err)
+
+	createdID(t, sendTenant(router, "POST", "/auth/register", "", "globex", `{"email": "beto@globex.com", "password": "contraseña-segura"}`))
+	user, err := repo.GetUserByEmail("beto@globex.com")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "3", user.TenantID)
+	}
+
+	// Sin tenant pedido se registra en el tenant por defecto, que lo admite
+	createdID(t, send(router, "POST", "/auth/register", "", `{"email": "caro@example.com", "password": "contraseña-segura"}`))
+	user, _ = repo.GetUserByEmail("caro@example.com")
+	assert.Equal(t, "1", user.TenantID)
+}
+
+func TestTenantOIDCClaim(t *testing.T) {
+	issuer := oidctest.NewIssuer()
+	defer issuer.Close()
+
+	gin.SetMode(gin.TestMode)
+	repo := repository.NewMockRepository()
+	repo.AddTenant(models.Tenant{Slug: "acme", Name: "Acme"})
+	repos := Repositories{Items: repo, Tags: repo, Projects: repo, Webhooks: repo, APIKeys: repo, Users: repo, Tenants: repo, Shares: repo}
+	authConfig := config.DefaultAuthConfig()
+	authConfig.OIDC.Issuer = issuer.URL()
+	authConfig.OIDC.Audience = "todo-api"
+	authConfig.OIDC.TenantClaim = "org"
+	router := SetupRouter(repos, config.DefaultItemRules(), events.NewHub(events.NewMemoryPubSub(), 10), config.DefaultStreamConfig(),
+		config.DefaultWebSocketConfig(), config.DefaultGraphQLConfig(), config.DefaultVersionConfig(), authConfig, config.DefaultTenantConfig())
+
+	bearer := func(claims jwt.MapClaims, slug string) *httptest.ResponseRecorder {
+		claims["aud"] = "todo-api"
+		req := httptest.NewRequest("GET", "/items", nil)
+		req.Header.Set("Authorization", "Bearer "+issuer.Token(claims))
+		if slug != "" {
+			req.Header.Set("X-Tenant", slug)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// El usuario nuevo se crea en el tenant del claim, aunque acme no admita el
+	// registro, y no puede trabajar en otro que pida la petición
+	assert.Equal(t, http.StatusOK, bearer(jwt.MapClaims{"sub": "ana", "email": "ana@acme.com", "org": "acme"}, "").Code)
+	user, err := repo.GetUserByIdentity(issuer.URL(), "ana")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "2", user.TenantID)
+	}
+	assert.Equal(t, http.StatusForbidden, bearer(jwt.MapClaims{"sub": "ana", "email": "ana@acme.com", "org": "acme"}, "default").Code)
+
+	// X-Tenant no elige el tenant de un usuario nuevo: sin claim o con un tenant
+	// inexistente no se crea
+	assert.Equal(t, http.StatusUnauthorized, bearer(jwt.MapClaims{"sub": "beto", "email": "beto@acme.com"}, "acme").Code)
+	assert.Equal(t, http.StatusUnauthorized, bearer(jwt.MapClaims{"sub": "beto", "email": "beto@acme.com", "org": "initech"}, "").Code)
+	_, err = repo.GetUserByIdentity(issuer.URL(), "beto")
+	assert.Error(t, err)
+
+	// Una cuenta local de otro tenant no se asocia a la identidad
+	createdID(t, send(router, "POST", "/auth/register", "", `{"email": "caro@example.com", "password": "contraseña-segura"}`))
+	assert.Equal(t, http.StatusUnauthorized, bearer(jwt.MapClaims{"sub": "caro", "email": "caro@example.com", "email_verified": true, "org": "acme"}, "").Code)
+
+	// Sin OIDC_TENANT_CLAIM los usuarios nuevos van al tenant por defecto, y
+	// solo si admite el registro
+	authConfig.OIDC.TenantClaim = ""
+	router = SetupRouter(repos, config.DefaultItemRules(), events.NewHub(events.NewMemoryPubSub(), 10), config.DefaultStreamConfig(),
+		config.DefaultWebSocketConfig(), config.DefaultGraphQLConfig(), config.DefaultVersionConfig(), authConfig, config.DefaultTenantConfig())
+	assert.Equal(t, http.StatusForbidden, bearer(jwt.MapClaims{"sub": "dani", "email": "dani@example.com"}, "acme").Code)
+	user, err = repo.GetUserByIdentity(issuer.URL(), "dani")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1", user.TenantID)
+	}
+}
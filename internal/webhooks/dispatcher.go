@@ -0,0 +1,206 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+)
+
+// batchSize es la cantidad máxima de eventos y entregas que se procesan por ciclo
+const batchSize = 100
+
+// Dispatcher reparte los eventos del outbox a los webhooks suscritos y envía las
+// entregas pendientes, reintentando con backoff exponencial
+type Dispatcher struct {
+	repo   repository.IWebhookRepository
+	config config.WebhookConfig
+	client *http.Client
+	// now permite fijar la hora en los tests
+	now func() time.Time
+	// allowed indica a qué direcciones se puede conectar; los tests admiten loopback
+	allowed func(netip.Addr) bool
+}
+
+// errInternalAddress es una conexión a una dirección que no es pública
+var errInternalAddress = errors.New(constants.EntregaDestinoInterno)
+
+func NewDispatcher(repo repository.IWebhookRepository, cfg config.WebhookConfig) *Dispatcher {
+	d := &Dispatcher{
+		repo:    repo,
+		config:  cfg,
+		now:     time.Now,
+		allowed: models.PublicAddress,
+	}
+
+	// La dirección se verifica al conectarse, después de resolver el nombre, para
+	// que un nombre que resuelve a la red interna tampoco llegue. Sin proxy, que
+	// se conectaría en nuestro lugar.
+	dialer := &net.Dialer{Timeout: cfg.Timeout, Control: d.checkAddress}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	d.client = &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+		// Una redirección podría llevar la entrega a la red interna; se responde
+		// la redirección, que no es 2xx y cuenta como fallo
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return d
+}
+
+// checkAddress rechaza las conexiones a direcciones que no son públicas
+func (d *Dispatcher) checkAddress(network string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || !d.allowed(addrPort.Addr()) {
+		return errInternalAddress
+	}
+	return nil
+}
+
+// Sign devuelve la firma HMAC-SHA256 del cuerpo, en el formato de la cabecera X-Webhook-Signature
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret genera un secreto aleatorio para firmar los payloads de un webhook
+func NewSecret() string {
+	buf := make([]byte, 32)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Backoff devuelve la espera antes del próximo intento tras attempts intentos fallidos
+func (d *Dispatcher) Backoff(attempts int) time.Duration {
+	wait := d.config.BaseBackoff
+	for i := 1; i < attempts && wait < d.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.config.MaxBackoff)
+}
+
+// Run procesa el outbox y las entregas cada PollInterval hasta que se cancele ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Tick(ctx); err != nil {
+			log.Printf("WARN: error al despachar webhooks: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick hace un ciclo de despacho: reparte el outbox y envía las entregas vencidas
+func (d *Dispatcher) Tick(ctx context.Context) error {
+	for {
+		count, err := d.repo.FanOutOutbox(batchSize)
+		if err != nil {
+			return err
+		}
+		if count < batchSize {
+			break
+		}
+	}
+
+	// La entrega se reserva por el timeout más un margen, por si el proceso muere a mitad del envío
+	deliveries, err := d.repo.ClaimDueDeliveries(d.now(), d.config.Timeout+time.Minute, batchSize)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		if err := d.deliver(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver envía una entrega y registra el resultado
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) error {
+	statusCode, sendErr := d.send(ctx, delivery)
+	if sendErr == nil {
+		return d.repo.CompleteDelivery(delivery.ID, statusCode)
+	}
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+	attempts := delivery.Attempts + 1
+	var next *time.Time
+	if attempts < d.config.MaxAttempts {
+		at := d.now().Add(d.Backoff(attempts))
+		next = &at
+	}
+	return d.repo.FailDelivery(delivery.ID, code, deliveryError(delivery, statusCode, sendErr), next)
+}
+
+// deliveryError devuelve el error de una entrega fallida que se guarda y ve el
+// dueño del webhook; el detalle de la conexión (direcciones, resolución de
+// nombres) solo va al log
+func deliveryError(delivery models.WebhookDelivery, statusCode int, err error) string {
+	var netErr net.Error
+	switch {
+	case statusCode != 0:
+		return fmt.Sprintf(constants.EntregaRespuestaFallida, statusCode)
+	case errors.Is(err, errInternalAddress):
+		log.Printf("WARN: entrega %s del webhook %s a una dirección interna: %v", delivery.ID, delivery.WebhookID, err)
+		return constants.EntregaDestinoInterno
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return constants.EntregaSinRespuesta
+	default:
+		log.Printf("WARN: entrega %s del webhook %s: %v", delivery.ID, delivery.WebhookID, err)
+		return constants.EntregaSinConexion
+	}
+}
+
+// send hace la petición al receptor; cualquier respuesta fuera de 2xx es un fallo
+func (d *Dispatcher) send(ctx context.Context, delivery models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(constants.WebhookEventHeader, delivery.Event)
+	req.Header.Set(constants.WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(constants.WebhookSignatureHeader, Sign(delivery.Secret, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf(constants.EntregaRespuestaFallida, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,154 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/constants"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/models"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// receiver es un receptor de webhooks local que guarda lo que recibe y responde con status
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+}
+
+func setupDispatcher(t *testing.T, status int, events []string) (*Dispatcher, *repository.MockRepository, *receiver) {
+	recv := &receiver{status: status}
+	server := httptest.NewServer(recv)
+	t.Cleanup(server.Close)
+
+	repo := repository.NewMockRepository()
+	repo.CreateWebhook(models.Webhook{URL: server.URL, Secret: "secreto", Events: events, Active: true})
+
+	cfg := config.DefaultWebhookConfig()
+	cfg.MaxAttempts = 2
+	dispatcher := NewDispatcher(repo, cfg)
+	// El receptor de las pruebas está en loopback
+	dispatcher.allowed = func(netip.Addr) bool { return true }
+	return dispatcher, repo, recv
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	dispatcher, repo, recv := setupDispatcher(t, http.StatusOK, []string{constants.WebhookItemCompleted})
+
+	repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+	repo.Update(models.TodoItem{ID: "1", Title: "Task 1", State: constants.StateCompleted})
+
+	assert.NoError(t, dispatcher.Tick(context.Background()))
+
+	// Solo se recibe item.completed, no item.created ni item.updated
+	assert.Len(t, recv.requests, 1)
+	req, body := recv.requests[0], recv.bodies[0]
+	assert.Equal(t, constants.WebhookItemCompleted, req.Header.Get(constants.WebhookEventHeader))
+	assert.Equal(t, Sign("secreto", body), req.Header.Get(constants.WebhookSignatureHeader))
+
+	var payload models.WebhookPayload
+	json.Unmarshal(body, &payload)
+	assert.Equal(t, "1", payload.ItemID)
+	assert.Equal(t, constants.StateCompleted, payload.Changes["state"].New)
+
+	deliveries, _, _ := repo.GetDeliveries(repository.DeliveryFilter{}, 1, 10)
+	assert.Equal(t, constants.DeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+}
+
+func TestDispatcher_RetriesThenDeadLetters(t *testing.T) {
+	dispatcher, repo, recv := setupDispatcher(t, http.StatusInternalServerError, nil)
+	// Las entregas se crean con la hora real, así que el reloj fijo va un poco adelante
+	now := time.Now().Add(time.Second)
+	dispatcher.now = func() time.Time { return now }
+
+	repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+	assert.NoError(t, dispatcher.Tick(context.Background()))
+
+	deliveries, _, _ := repo.GetDeliveries(repository.DeliveryFilter{}, 1, 10)
+	assert.Equal(t, constants.DeliveryPending, deliveries[0].Status)
+	assert.Equal(t, http.StatusInternalServerError, *deliveries[0].LastStatusCode)
+
+	// Antes de que pase el backoff no se reintenta
+	assert.NoError(t, dispatcher.Tick(context.Background()))
+	assert.Len(t, recv.requests, 1)
+
+	now = now.Add(dispatcher.Backoff(1))
+	assert.NoError(t, dispatcher.Tick(context.Background()))
+	assert.Len(t, recv.requests, 2)
+
+	dead, total, _ := repo.GetDeliveries(repository.DeliveryFilter{Status: constants.DeliveryDead}, 1, 10)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, 2, dead[0].Attempts)
+
+	// Al reprogramarla manualmente se vuelve a enviar
+	recv.mu.Lock()
+	recv.status = http.StatusNoContent
+	recv.mu.Unlock()
+	repo.Redeliver(1)
+	now = time.Now().Add(time.Second)
+	assert.NoError(t, dispatcher.Tick(context.Background()))
+
+	deliveries, _, _ = repo.GetDeliveries(repository.DeliveryFilter{}, 1, 10)
+	assert.Equal(t, constants.DeliveryDelivered, deliveries[0].Status)
+}
+
+func TestDispatcher_RejectsInternalAddresses(t *testing.T) {
+	dispatcher, repo, recv := setupDispatcher(t, http.StatusOK, nil)
+	dispatcher.allowed = models.PublicAddress
+
+	repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+	assert.NoError(t, dispatcher.Tick(context.Background()))
+
+	// El receptor está en loopback: no se conecta y el error no da detalles de la red
+	assert.Empty(t, recv.requests)
+	deliveries, _, _ := repo.GetDeliveries(repository.DeliveryFilter{}, 1, 10)
+	assert.Equal(t, constants.DeliveryPending, deliveries[0].Status)
+	assert.Nil(t, deliveries[0].LastStatusCode)
+	assert.Equal(t, constants.EntregaDestinoInterno, deliveries[0].LastError)
+}
+
+func TestDispatcher_DoesNotFollowRedirects(t *testing.T) {
+	dispatcher, repo, recv := setupDispatcher(t, http.StatusOK, nil)
+	webhook, _ := repo.GetWebhook(1)
+	redirect := httptest.NewServer(http.RedirectHandler(webhook.URL, http.StatusFound))
+	t.Cleanup(redirect.Close)
+	repo.UpdateWebhook(models.Webhook{ID: "1", URL: redirect.URL, Secret: "secreto", Active: true})
+
+	repo.Create(models.TodoItem{Title: "Task 1", State: constants.StatePending})
+	assert.NoError(t, dispatcher.Tick(context.Background()))
+
+	// La redirección cuenta como fallo y no llega al destino
+	assert.Empty(t, recv.requests)
+	deliveries, _, _ := repo.GetDeliveries(repository.DeliveryFilter{}, 1, 10)
+	assert.Equal(t, http.StatusFound, *deliveries[0].LastStatusCode)
+	assert.Equal(t, "respuesta 302 del receptor", deliveries[0].LastError)
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	dispatcher := NewDispatcher(repository.NewMockRepository(), config.WebhookConfig{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	assert.Equal(t, time.Second, dispatcher.Backoff(1))
+	assert.Equal(t, 2*time.Second, dispatcher.Backoff(2))
+	assert.Equal(t, 4*time.Second, dispatcher.Backoff(3))
+	assert.Equal(t, 5*time.Second, dispatcher.Backoff(4))
+}
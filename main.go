@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"log"
+	"net"
 
 	cfg "github.com/Milagrosgzmn/devops_todo_go.git/internal/config"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/db"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/events"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/grpcapi"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/repository"
 	"github.com/Milagrosgzmn/devops_todo_go.git/internal/routes"
+	"github.com/Milagrosgzmn/devops_todo_go.git/internal/webhooks"
 	"github.com/joho/godotenv"
 )
 
@@ -31,10 +36,67 @@ func main() {
 		log.Fatalf("Error al ejecutar migraciones: %v", err)
 	}
 
-	// Inicializamos el repositorio
-	var repo repository.IRepository = repository.NewItemMySqlRepository(dbInstance)
+	// Elegimos cómo se guardan los items: directamente o derivados de un flujo de eventos
+	storage := cfg.NewStorageConfig()
+	var items repository.IRepository = repository.NewItemMySqlRepository(dbInstance)
+	if storage.Mode == cfg.StorageEventSourced {
+		eventSourced := repository.NewItemEventSourcedRepository(dbInstance, storage.SnapshotEvery)
+		if storage.RebuildProjections {
+			if err := eventSourced.RebuildProjections(); err != nil {
+				log.Fatalf("Error al reconstruir las proyecciones: %v", err)
+			}
+		}
+		items = eventSourced
+	}
+
+	// Los cambios de items se publican en el stream (GET /items/stream)
+	streamConfig := cfg.NewStreamConfig()
+	var pubsub events.PubSub = events.NewMemoryPubSub()
+	if streamConfig.PubSub == cfg.StreamPubSubMySQL {
+		pubsub = events.NewMySQLPubSub(dbInstance, streamConfig.PollInterval, streamConfig.Retention)
+	}
+	hub := events.NewHub(pubsub, streamConfig.ReplayBuffer)
+	go hub.Run(context.Background())
+
+	// Inicializamos los repositorios
+	repos := routes.Repositories{
+		Items:    events.NewPublishingRepository(items, hub),
+		Tags:     repository.NewTagMySqlRepository(dbInstance),
+		Projects: repository.NewProjectMySqlRepository(dbInstance),
+		Webhooks: repository.NewWebhookMySqlRepository(dbInstance),
+		APIKeys:  repository.NewAPIKeyMySqlRepository(dbInstance),
+		Users:    repository.NewUserMySqlRepository(dbInstance),
+		Tenants:  repository.NewTenantMySqlRepository(dbInstance),
+		Shares:   repository.NewShareMySqlRepository(dbInstance),
+	}
+
+	// Despachamos en segundo plano los eventos del outbox a los webhooks
+	dispatcher := webhooks.NewDispatcher(repos.Webhooks, cfg.NewWebhookConfig())
+	go dispatcher.Run(context.Background())
+
+	// Cargamos las reglas de negocio (flujo de estados, subtareas, dependencias)
+	itemRules, err := cfg.NewItemRules()
+	if err != nil {
+		log.Fatalf("Error al cargar las reglas de items: %v", err)
+	}
+
+	// Servimos la API gRPC junto a la REST, sobre los mismos repositorios y con
+	// las mismas credenciales
+	authConfig := cfg.NewAuthConfig()
+	if grpcConfig := cfg.NewGRPCConfig(); grpcConfig.Addr != "" {
+		listener, err := net.Listen("tcp", grpcConfig.Addr)
+		if err != nil {
+			log.Fatalf("Error al escuchar en %s para la API gRPC: %v", grpcConfig.Addr, err)
+		}
+		grpcServer := grpcapi.NewServer(repos.Items, repos.Tenants, routes.NewAuthenticator(repos, authConfig), itemRules, hub)
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Printf("WARN: la API gRPC se detuvo: %v", err)
+			}
+		}()
+	}
 
 	// Configuramos el router
-	router := routes.SetupRouter(repo);
+	router := routes.SetupRouter(repos, itemRules, hub, streamConfig, cfg.NewWebSocketConfig(), cfg.NewGraphQLConfig(), cfg.NewVersionConfig(), authConfig, cfg.NewTenantConfig());
 	router.Run(":8080")
 }
\ No newline at end of file